@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// App passwords are prefixed so they can't be mistaken for access tokens
+const appPasswordPrefix = "rela_"
+
+// authenticateAppPassword checks HTTP basic auth with an email and app password, or an app password
+// sent as bearer token, and returns the user it belongs to.
+func authenticateAppPassword(c *gin.Context) (bson.ObjectID, bool) {
+	var password, email string
+	if username, secret, ok := c.Request.BasicAuth(); ok {
+		email, password = strings.ToLower(strings.TrimSpace(username)), secret
+	} else if header := c.GetHeader("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		password = strings.TrimPrefix(header, "Bearer ")
+	}
+	if !strings.HasPrefix(password, appPasswordPrefix) {
+		return bson.ObjectID{}, false
+	}
+	var appPassword AppPassword
+	if err := appPasswordsDb.FindOne(context.TODO(), bson.D{{"hash", hashToken(password)}}).Decode(&appPassword); err != nil {
+		return bson.ObjectID{}, false
+	}
+	if email != "" {
+		var user User
+		if err := usersDb.FindOne(context.TODO(), bson.D{{"_id", appPassword.User}}).Decode(&user); err != nil || strings.ToLower(user.Email) != email {
+			return bson.ObjectID{}, false
+		}
+	}
+	if _, err := appPasswordsDb.UpdateOne(context.TODO(), bson.D{{"_id", appPassword.Id}}, bson.D{{"$set", bson.D{{"last_used_at", time.Now().UTC().Unix()}}}}); err != nil {
+		println("WARNING Failed to update app password ", appPassword.Id.Hex(), ": ", err.Error())
+	}
+	return appPassword.User, true
+}
+
+// @Summary 		Get your app passwords
+// @Description 	Returns the app passwords you created, without the passwords.
+// @Router 			/users/app_passwords [get]
+// @Tags 			Users
+// @Security 		BearerAuth
+// @Produce 		json
+// @Success 		200 {object} AllAppPasswordsResponse "A list of app passwords"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func getAllAppPasswords(c *gin.Context) {
+	userId, _ := c.Get("id")
+	cursor, err := appPasswordsDb.Find(context.TODO(), bson.D{{"user", userId}})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	appPasswords := make([]AppPassword, 0)
+	if err := cursor.All(context.TODO(), &appPasswords); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(200, gin.H{"app_passwords": appPasswords})
+}
+
+// @Summary 		Create an app password
+// @Description 	Creates a password for apps like CalDAV clients, used with your email for basic auth or as a personal bearer token. The password is only returned here.
+// @Router 			/users/app_passwords [post]
+// @Tags 			Users
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			data body CreateAppPassword true "A name to recognize the app by"
+// @Success 		200 {object} AppPassword "The created app password including the password"
+// @Failure 		400 {object} ErrorSwagger "Bad request - missing name"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func createAppPassword(c *gin.Context) {
+	userId, _ := c.Get("id")
+	var input CreateAppPassword
+	if err := json.NewDecoder(c.Request.Body).Decode(&input); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return
+	} else if strings.TrimSpace(input.Name) == "" {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'name' is not specified"})
+		return
+	}
+	secret, _, err := generateToken()
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	password := appPasswordPrefix + secret
+	appPassword := AppPassword{
+		User:      userId.(bson.ObjectID),
+		Name:      strings.TrimSpace(input.Name),
+		Hash:      hashToken(password),
+		CreatedAt: time.Now().UTC().Unix(),
+	}
+	result, err := appPasswordsDb.InsertOne(context.TODO(), appPassword)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to create app password"})
+		return
+	}
+	appPassword.Id = result.InsertedID.(bson.ObjectID)
+	appPassword.Password = password
+	c.JSON(200, appPassword)
+}
+
+// @Summary 		Revoke an app password
+// @Description 	Deletes an app password, apps using it can no longer sign in.
+// @Router 			/users/app_passwords/{passwordId} [delete]
+// @Tags 			Users
+// @Security 		BearerAuth
+// @Param 			passwordId path string true "App password ID"
+// @Success 		200 "App password revoked"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid id"
+// @Failure 		404 {object} ErrorSwagger "Not Found - app password not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func deleteAppPassword(c *gin.Context) {
+	userId, _ := c.Get("id")
+	passwordId, err := bson.ObjectIDFromHex(c.Param("passwordId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid passwordId"})
+		return
+	}
+	result, err := appPasswordsDb.DeleteOne(context.TODO(), bson.D{{"_id", passwordId}, {"user", userId}})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to delete app password"})
+		return
+	} else if result.DeletedCount == 0 {
+		c.AbortWithStatusJSON(404, gin.H{"error": "App password does not exist"})
+		return
+	}
+	c.AbortWithStatus(200)
+}
+
+// appPasswordMiddleware authenticates apps that can't log in. They get a basic auth challenge.
+func appPasswordMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userId, ok := authenticateAppPassword(c)
+		if !ok {
+			c.Header("WWW-Authenticate", `Basic realm="Rela", charset="UTF-8"`)
+			c.AbortWithStatusJSON(401, gin.H{"error": "An app password is required"})
+			return
+		}
+		c.Set("id", userId)
+		c.Next()
+	}
+}
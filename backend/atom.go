@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const maxAtomEntries = 100
+
+var atomEventTypes = bson.A{eventTaskCreated, eventTaskUpdated, eventTaskDeleted}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Id       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Updated  string      `xml:"updated"`
+	Author   *atomPerson `xml:"author,omitempty"`
+	Link     *atomLink   `xml:"link,omitempty"`
+	Category []atomLabel `xml:"category"`
+	Content  *atomText   `xml:"content,omitempty"`
+}
+
+type atomLabel struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Id      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+func atomTime(timestamp int64) string {
+	return time.Unix(timestamp, 0).UTC().Format(time.RFC3339)
+}
+
+// atomFilter matches the task events a feed covers. Board feeds include tasks moved off the board.
+func atomFilter(feed FeedToken) bson.D {
+	filter := bson.D{{"workspace", feed.Workspace}, {"type", bson.D{{"$in", atomEventTypes}}}}
+	if feed.Scope == feedScopeBoard {
+		filter = append(filter, bson.E{"$or", bson.A{
+			bson.D{{"board_id", feed.Board}},
+			bson.D{{"previous_board", feed.Board}},
+		}})
+	}
+	return filter
+}
+
+// atomNotModified answers conditional requests. Events are only ever appended, so the newest one
+// identifies the content of a feed.
+func atomNotModified(c *gin.Context, etag string, modified time.Time) bool {
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == etag || candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+	if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil {
+		return !modified.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+func atomEntryFor(event Event, actors map[bson.ObjectID]string, boards map[bson.ObjectID]string) atomEntry {
+	task := *event.Task
+	actor, ok := actors[event.Actor]
+	if !ok {
+		actor = "Someone"
+	}
+	entry := atomEntry{
+		Id:       "urn:rela:event:" + event.Id.Hex(),
+		Updated:  atomTime(event.CreatedAt),
+		Author:   &atomPerson{Name: actor},
+		Category: []atomLabel{{Term: event.Type}},
+	}
+	board := func(id bson.ObjectID) string {
+		if name, ok := boards[id]; ok {
+			return name
+		}
+		return "a deleted board"
+	}
+	switch {
+	case event.Type == eventTaskCreated:
+		entry.Title = actor + " created " + taskTitle(task) + " in " + board(task.Board)
+	case event.Type == eventTaskDeleted:
+		entry.Title = actor + " deleted " + taskTitle(task) + " from " + board(task.Board)
+	case !event.PreviousBoard.IsZero():
+		entry.Title = actor + " moved " + taskTitle(task) + " from " + board(event.PreviousBoard) + " to " + board(task.Board)
+	default:
+		entry.Title = actor + " updated " + taskTitle(task)
+	}
+	// Deleted tasks have no page to link to
+	if event.Type != eventTaskDeleted {
+		entry.Link = &atomLink{Href: taskWebUrl(task), Rel: "alternate", Type: "text/html"}
+	}
+	if task.Description != "" {
+		entry.Content = &atomText{Type: "text", Value: task.Description}
+	}
+	return entry
+}
+
+// @Summary 		Get an Atom feed
+// @Description 	Returns the latest task creations, edits, moves between boards and deletions of a workspace or board as an Atom feed. The secret token in the URL replaces the bearer header, see POST /feeds. Supports conditional requests with If-None-Match and If-Modified-Since.
+// @Router 			/atom/{token} [get]
+// @Tags 			Feeds
+// @Produce 		application/atom+xml
+// @Param 			token path string true "Feed token, optionally followed by .xml"
+// @Success 		200 {string} string "The feed"
+// @Success 		304 "Not modified since the last request"
+// @Failure 		404 {object} ErrorSwagger "Not Found - unknown or revoked feed"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func getAtomFeed(c *gin.Context) {
+	feed, workspace, ok := resolveFeed(strings.TrimSuffix(c.Param("token"), ".xml"), feedAtom)
+	if !ok {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Feed does not exist"})
+		return
+	}
+	filter := atomFilter(feed)
+	var latest Event
+	if err := eventsDb.FindOne(context.TODO(), filter, options.FindOne().SetSort(bson.D{{"_id", -1}}).SetProjection(bson.D{{"_id", 1}, {"created_at", 1}})).Decode(&latest); err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	updated := feed.CreatedAt
+	if !latest.Id.IsZero() {
+		updated = latest.CreatedAt
+	}
+	boardIds := []bson.ObjectID{feed.Board}
+	boards, err := boardNames(boardIds)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	title := "Rela: " + workspace.Name
+	if feed.Scope == feedScopeBoard {
+		title += " / " + boards[feed.Board]
+	}
+	// Renaming the workspace or board changes the title, so it is part of the ETag
+	etag := `"` + hashToken(feed.Id.Hex() + latest.Id.Hex() + title)[:32] + `"`
+	modified := time.Unix(updated, 0).UTC()
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", modified.Format(http.TimeFormat))
+	c.Header("Cache-Control", "private, max-age=300")
+	if atomNotModified(c, etag, modified) {
+		c.AbortWithStatus(304)
+		return
+	}
+
+	cursor, err := eventsDb.Find(context.TODO(), filter, options.Find().SetSort(bson.D{{"_id", -1}}).SetLimit(maxAtomEntries))
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	activity := make([]Event, 0)
+	if err := cursor.All(context.TODO(), &activity); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	actorIds := make([]bson.ObjectID, 0)
+	for _, event := range activity {
+		actorIds = append(actorIds, event.Actor)
+		if event.Task != nil {
+			boardIds = append(boardIds, event.Task.Board, event.PreviousBoard)
+		}
+	}
+	if boards, err = boardNames(boardIds); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	cursor, err = usersDb.Find(context.TODO(), bson.D{{"_id", bson.D{{"$in", actorIds}}}}, options.Find().SetProjection(bson.D{{"_id", 1}, {"name", 1}}))
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	users := make([]User, 0)
+	if err := cursor.All(context.TODO(), &users); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	actors := make(map[bson.ObjectID]string, len(users))
+	for _, user := range users {
+		actors[user.Id] = user.Name
+	}
+
+	self := feedUrl(feed, strings.TrimSuffix(c.Param("token"), ".xml"))
+	document := atomFeed{
+		Id:      "urn:rela:feed:" + feed.Id.Hex(),
+		Title:   title,
+		Updated: atomTime(updated),
+		Links: []atomLink{
+			{Href: self, Rel: "self", Type: "application/atom+xml"},
+			{Href: webUrl("/workspaces/" + workspace.Id.Hex()), Rel: "alternate", Type: "text/html"},
+		},
+		Entries: make([]atomEntry, 0, len(activity)),
+	}
+	for _, event := range activity {
+		if event.Task != nil {
+			document.Entries = append(document.Entries, atomEntryFor(event, actors, boards))
+		}
+	}
+	output, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.Data(200, "application/atom+xml; charset=utf-8", append([]byte(xml.Header), output...))
+}
@@ -0,0 +1,684 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	backupFormat  = "rela-workspace-backup"
+	backupVersion = 1
+	maxBackupSize = 1 << 30
+	// Documents are stored one per line, a task with a huge description must still fit
+	maxBackupLine = 32 << 20
+	maxAvatarSize = 16 << 20
+)
+
+// backupImageTypes are the image formats a backup may contain, by extension.
+var backupImageTypes = map[string]string{".png": "image/png", ".jpg": "image/jpeg", ".jpeg": "image/jpeg"}
+
+// idMap gives the documents of a copied workspace new ObjectIDs. An id gets the same new id every
+// time, so references between documents can be remapped in any order.
+type idMap map[bson.ObjectID]bson.ObjectID
+
+func (m idMap) get(id bson.ObjectID) bson.ObjectID {
+	if id.IsZero() {
+		return id
+	}
+	if mapped, ok := m[id]; ok {
+		return mapped
+	}
+	mapped := bson.NewObjectID()
+	m[id] = mapped
+	return mapped
+}
+
+// hex remaps an id stored as a hex string, like the custom field keys of tasks.
+func (m idMap) hex(value string) string {
+	id, err := bson.ObjectIDFromHex(value)
+	if err != nil {
+		return value
+	}
+	return m.get(id).Hex()
+}
+
+// writeBackupLines streams the documents matched by filter into a JSON lines entry of the archive.
+// Documents are canonical extended JSON, so ObjectIDs and number types survive the round trip.
+func writeBackupLines[T any](archive *zip.Writer, name string, collection *mongo.Collection, filter bson.D) (int, error) {
+	cursor, err := collection.Find(context.TODO(), filter, options.Find().SetSort(bson.D{{"_id", 1}}))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.TODO())
+	entry, err := archive.Create(name)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for cursor.Next(context.TODO()) {
+		var document T
+		if err := cursor.Decode(&document); err != nil {
+			return count, err
+		}
+		line, err := bson.MarshalExtJSON(document, true, false)
+		if err != nil {
+			return count, err
+		}
+		if _, err := entry.Write(append(line, '\n')); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, cursor.Err()
+}
+
+// writeBackupFile copies an uploaded image into the archive and returns its name there.
+func writeBackupFile(archive *zip.Writer, avatar string) string {
+	if avatar == "" || filepath.Dir(filepath.Clean(avatar)) != "img" {
+		return ""
+	}
+	file, err := os.Open(filepath.Clean(avatar))
+	if err != nil {
+		println("WARNING Avatar ", avatar, " is missing from the backup: ", err.Error())
+		return ""
+	}
+	defer file.Close()
+	name := "files/" + filepath.Base(avatar)
+	entry, err := archive.Create(name)
+	if err != nil {
+		return ""
+	}
+	if _, err := io.Copy(entry, file); err != nil {
+		return ""
+	}
+	return name
+}
+
+func backupMembers(workspace Workspace) ([]BackupMember, error) {
+	cursor, err := usersDb.Find(context.TODO(),
+		bson.D{{"_id", bson.D{{"$in", append([]bson.ObjectID{workspace.OwnedBy}, workspace.Members...)}}}},
+		options.Find().SetProjection(bson.D{{"_id", 1}, {"name", 1}, {"email", 1}, {"avatar", 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]User, 0)
+	if err := cursor.All(context.TODO(), &users); err != nil {
+		return nil, err
+	}
+	members := make([]BackupMember, 0, len(users))
+	for _, user := range users {
+		members = append(members, BackupMember{Id: user.Id, Name: user.Name, Email: user.Email, Avatar: user.Avatar, Owner: user.Id == workspace.OwnedBy})
+	}
+	return members, nil
+}
+
+// writeBackup writes all documents of a workspace and the images they reference. The manifest comes
+// last because it counts the documents.
+func writeBackup(archive *zip.Writer, workspace Workspace, members []BackupMember) error {
+	manifest := BackupManifest{
+		Format:     backupFormat,
+		Version:    backupVersion,
+		ExportedAt: time.Now().UTC().Unix(),
+		Workspace:  workspace.Name,
+		Counts:     map[string]int{"members": len(members)},
+	}
+	// Invite tokens only work on this instance
+	workspace.Tokens = nil
+	workspace.Avatar = writeBackupFile(archive, workspace.Avatar)
+	line, err := bson.MarshalExtJSON(workspace, true, false)
+	if err != nil {
+		return err
+	}
+	entry, err := archive.Create("workspace.json")
+	if err != nil {
+		return err
+	}
+	if _, err := entry.Write(line); err != nil {
+		return err
+	}
+
+	// A zip entry ends when the next one is created, so the avatars go in before the member list
+	for i := range members {
+		members[i].Avatar = writeBackupFile(archive, members[i].Avatar)
+	}
+	entry, err = archive.Create("members.jsonl")
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		line, err := bson.MarshalExtJSON(member, true, false)
+		if err != nil {
+			return err
+		}
+		if _, err := entry.Write(append(line, '\n')); err != nil {
+			return err
+		}
+	}
+
+	if manifest.Counts["boards"], err = writeBackupLines[Board](archive, "boards.jsonl", boardsDb, bson.D{{"owned_by", workspace.Id}}); err != nil {
+		return err
+	}
+	if manifest.Counts["labels"], err = writeBackupLines[Label](archive, "labels.jsonl", labelsDb, bson.D{{"workspace", workspace.Id}}); err != nil {
+		return err
+	}
+	if manifest.Counts["custom_fields"], err = writeBackupLines[CustomField](archive, "custom_fields.jsonl", customFieldsDb, bson.D{{"workspace", workspace.Id}}); err != nil {
+		return err
+	}
+	if manifest.Counts["views"], err = writeBackupLines[SavedView](archive, "views.jsonl", viewsDb, bson.D{{"workspace", workspace.Id}}); err != nil {
+		return err
+	}
+	if manifest.Counts["tasks"], err = writeBackupLines[Task](archive, "tasks.jsonl", tasksDb, bson.D{{"created_by", workspace.Id}}); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	entry, err = archive.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+	_, err = entry.Write(data)
+	return err
+}
+
+// workspaceBackup is a parsed backup archive. Images are read into memory because the upload is gone
+// once the restore job runs.
+type workspaceBackup struct {
+	manifest  BackupManifest
+	workspace Workspace
+	members   []BackupMember
+	boards    []Board
+	labels    []Label
+	fields    []CustomField
+	views     []SavedView
+	tasks     []Task
+	files     map[string][]byte
+}
+
+func readBackupLines[T any](archive *zip.Reader, name string) ([]T, error) {
+	documents := make([]T, 0)
+	file, err := archive.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return documents, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxBackupLine)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var document T
+		if err := bson.UnmarshalExtJSON(scanner.Bytes(), true, &document); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", name, line, err)
+		}
+		documents = append(documents, document)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return documents, nil
+}
+
+func readBackupFile(archive *zip.Reader, name string) ([]byte, error) {
+	file, err := archive.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxAvatarSize+1))
+	if err != nil {
+		return nil, err
+	} else if len(data) > maxAvatarSize {
+		return nil, fmt.Errorf("%s is too large", name)
+	}
+	return data, nil
+}
+
+// readBackup parses and checks a backup archive. Errors describe what is wrong with the file.
+func readBackup(archive *zip.Reader) (*workspaceBackup, error) {
+	backup := &workspaceBackup{files: map[string][]byte{}}
+	// Entries can't be read past the size in their header, so the headers bound what a restore unpacks
+	var unpacked uint64
+	for _, entry := range archive.File {
+		if unpacked += entry.UncompressedSize64; unpacked > maxBackupSize {
+			return nil, errors.New("The backup is too large when unpacked")
+		}
+	}
+	file, err := archive.Open("manifest.json")
+	if err != nil {
+		return nil, errors.New("The file is not a workspace backup")
+	}
+	err = json.NewDecoder(file).Decode(&backup.manifest)
+	file.Close()
+	if err != nil || backup.manifest.Format != backupFormat {
+		return nil, errors.New("The file is not a workspace backup")
+	} else if backup.manifest.Version > backupVersion || backup.manifest.Version < 1 {
+		return nil, fmt.Errorf("Backups of version %d are not supported, this server restores up to version %d", backup.manifest.Version, backupVersion)
+	}
+
+	file, err = archive.Open("workspace.json")
+	if err != nil {
+		return nil, errors.New("The backup has no workspace.json")
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxBackupLine))
+	file.Close()
+	if err != nil {
+		return nil, err
+	} else if err := bson.UnmarshalExtJSON(data, true, &backup.workspace); err != nil {
+		return nil, fmt.Errorf("workspace.json: %w", err)
+	}
+	if backup.members, err = readBackupLines[BackupMember](archive, "members.jsonl"); err != nil {
+		return nil, err
+	}
+	if backup.boards, err = readBackupLines[Board](archive, "boards.jsonl"); err != nil {
+		return nil, err
+	}
+	if backup.labels, err = readBackupLines[Label](archive, "labels.jsonl"); err != nil {
+		return nil, err
+	}
+	if backup.fields, err = readBackupLines[CustomField](archive, "custom_fields.jsonl"); err != nil {
+		return nil, err
+	}
+	if backup.views, err = readBackupLines[SavedView](archive, "views.jsonl"); err != nil {
+		return nil, err
+	}
+	if backup.tasks, err = readBackupLines[Task](archive, "tasks.jsonl"); err != nil {
+		return nil, err
+	}
+
+	// Only the workspace avatar is restored, users on this server keep their own avatars
+	if name := backup.workspace.Avatar; name != "" {
+		if data, err := readBackupFile(archive, name); err == nil {
+			backup.files[name] = data
+		}
+	}
+	return backup, nil
+}
+
+// restoreFile writes an image of the backup to ./img under a new name and returns its path. Files
+// whose content is not an image of the type their extension claims are left out.
+func (b *workspaceBackup) restoreFile(name string) string {
+	data := b.files[name]
+	ext := strings.ToLower(path.Ext(name))
+	if contentType, ok := backupImageTypes[ext]; data == nil || !ok || http.DetectContentType(data) != contentType {
+		return ""
+	}
+	if err := os.MkdirAll("./img", 0755); err != nil {
+		return ""
+	}
+	fullPath := filepath.Join("img", uuid.New().String()+ext)
+	if err := os.WriteFile(fullPath, data, 0644); err != nil {
+		println("WARNING Failed to restore ", name, ": ", err.Error())
+		return ""
+	}
+	return fullPath
+}
+
+// backupRestore tracks the ids of a restore. Documents get new ids, the member that restores the
+// backup gets their own id and references to the other members are dropped.
+type backupRestore struct {
+	ids     idMap
+	users   map[bson.ObjectID]bson.ObjectID
+	fields  map[string]string
+	dropped int
+	// When only some tasks are copied, references to the others are dropped
+	tasks map[bson.ObjectID]bool
+}
+
+// user returns the id of a member on this instance, references to missing members are dropped.
+func (r *backupRestore) user(id bson.ObjectID) (bson.ObjectID, bool) {
+	mapped, ok := r.users[id]
+	if !ok {
+		r.dropped++
+	}
+	return mapped, ok
+}
+
+func (r *backupRestore) userList(ids []bson.ObjectID) []bson.ObjectID {
+	mapped := make([]bson.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if userId, ok := r.user(id); ok && !slices.Contains(mapped, userId) {
+			mapped = append(mapped, userId)
+		}
+	}
+	return mapped
+}
+
+func (r *backupRestore) customFields(values map[string]any) map[string]any {
+	if values == nil {
+		return nil
+	}
+	mapped := make(map[string]any, len(values))
+	for fieldId, value := range values {
+		if r.fields[fieldId] == fieldUser {
+			userId, ok := value.(bson.ObjectID)
+			if !ok {
+				continue
+			}
+			if value, ok = r.user(userId); !ok {
+				continue
+			}
+		}
+		mapped[r.ids.hex(fieldId)] = value
+	}
+	return mapped
+}
+
+func (r *backupRestore) task(task Task, workspaceId bson.ObjectID) Task {
+	task.Id = r.ids.get(task.Id)
+	task.CreatedBy = workspaceId
+	task.Board = r.ids.get(task.Board)
+	for i, label := range task.Labels {
+		task.Labels[i] = r.ids.get(label)
+	}
+	links := make([]TaskLink, 0, len(task.Links))
+	for _, link := range task.Links {
+		if r.tasks == nil || r.tasks[link.Task] {
+			links = append(links, TaskLink{Type: link.Type, Task: r.ids.get(link.Task)})
+		}
+	}
+	task.Links = links
+	task.Assignees = r.userList(task.Assignees)
+	task.Watchers = r.userList(task.Watchers)
+	task.CustomFields = r.customFields(task.CustomFields)
+	if task.Recurrence != nil {
+		task.Recurrence.Series = r.ids.get(task.Recurrence.Series)
+		task.Recurrence.ClaimedUntil = 0
+		if task.Recurrence.NextTask != nil && r.tasks != nil && !r.tasks[*task.Recurrence.NextTask] {
+			task.Recurrence.NextTask = nil
+		} else if task.Recurrence.NextTask != nil {
+			next := r.ids.get(*task.Recurrence.NextTask)
+			task.Recurrence.NextTask = &next
+		}
+	}
+	return task
+}
+
+func (r *backupRestore) view(view SavedView, workspaceId bson.ObjectID, actor bson.ObjectID) (SavedView, bool) {
+	owner, ok := r.users[view.Owner]
+	if !ok && !view.Shared {
+		return view, false
+	} else if !ok {
+		// Shared views of missing members stay available to the workspace
+		owner = actor
+	}
+	view.Id = r.ids.get(view.Id)
+	view.Workspace = workspaceId
+	view.Owner = owner
+	view.Board = r.ids.get(view.Board)
+	view.Query.Label = r.ids.hex(view.Query.Label)
+	if assignee, err := bson.ObjectIDFromHex(view.Query.Assignee); err == nil {
+		view.Query.Assignee = ""
+		if userId, ok := r.user(assignee); ok {
+			view.Query.Assignee = userId.Hex()
+		}
+	}
+	if view.Query.CustomFields != nil {
+		query := make(map[string]string, len(view.Query.CustomFields))
+		for fieldId, value := range view.Query.CustomFields {
+			if r.fields[fieldId] == fieldUser {
+				if userId, err := bson.ObjectIDFromHex(value); err == nil {
+					mapped, ok := r.user(userId)
+					if !ok {
+						continue
+					}
+					value = mapped.Hex()
+				}
+			}
+			query[r.ids.hex(fieldId)] = value
+		}
+		view.Query.CustomFields = query
+	}
+	return view, true
+}
+
+// restoreWorkspace creates a new workspace owned by actor from a backup. If restoring fails the
+// partial workspace is removed again.
+func restoreWorkspace(backup *workspaceBackup, name string, actor bson.ObjectID, runner *jobRunner) (err error) {
+	runner.setTotal(len(backup.tasks))
+	restore := &backupRestore{
+		ids:    idMap{},
+		users:  map[bson.ObjectID]bson.ObjectID{},
+		fields: map[string]string{},
+	}
+
+	// Anyone can write an archive, so it can't decide who joins the workspace. Only the user restoring
+	// it is matched by email, everyone else has to be invited again.
+	var user User
+	if err := usersDb.FindOne(context.TODO(), bson.D{{"_id", actor}}).Decode(&user); err != nil {
+		return err
+	}
+	missing := make([]BackupMember, 0)
+	for _, member := range backup.members {
+		if member.Email != "" && strings.EqualFold(member.Email, user.Email) {
+			restore.users[member.Id] = actor
+			continue
+		}
+		missing = append(missing, BackupMember{Id: member.Id, Name: member.Name, Email: member.Email, Owner: member.Owner})
+		runner.warn("Member %s <%s> has to be invited to the workspace again, their assignments were dropped", member.Name, member.Email)
+	}
+
+	workspace := Workspace{
+		Name:    backup.workspace.Name,
+		Key:     backup.workspace.Key,
+		Avatar:  backup.restoreFile(backup.workspace.Avatar),
+		OwnedBy: actor,
+		Members: []bson.ObjectID{actor},
+	}
+	if name != "" {
+		workspace.Name = name
+	}
+	if workspace.Key == "" {
+		workspace.Key = deriveWorkspaceKey(workspace.Name)
+	}
+	result, err := workspacesDb.InsertOne(context.TODO(), workspace)
+	if err != nil {
+		return err
+	}
+	workspace.Id = result.InsertedID.(bson.ObjectID)
+	runner.setWorkspace(workspace.Id)
+	defer func() {
+		if err == nil {
+			return
+		}
+		if discardErr := discardWorkspace(workspace.Id); discardErr != nil {
+			println("WARNING Failed to remove partial restore ", workspace.Id.Hex(), ": ", discardErr.Error())
+			return
+		}
+		runner.setWorkspace(bson.ObjectID{})
+	}()
+
+	labels := make([]Label, 0, len(backup.labels))
+	for _, label := range backup.labels {
+		label.Id = restore.ids.get(label.Id)
+		label.Workspace = workspace.Id
+		labels = append(labels, label)
+	}
+	if len(labels) != 0 {
+		if _, err := labelsDb.InsertMany(context.TODO(), labels); err != nil {
+			return err
+		}
+	}
+	fields := make([]CustomField, 0, len(backup.fields))
+	for _, field := range backup.fields {
+		restore.fields[field.Id.Hex()] = field.Type
+		field.Id = restore.ids.get(field.Id)
+		field.Workspace = workspace.Id
+		fields = append(fields, field)
+	}
+	if len(fields) != 0 {
+		if _, err := customFieldsDb.InsertMany(context.TODO(), fields); err != nil {
+			return err
+		}
+	}
+	boards := make([]Board, 0, len(backup.boards))
+	for _, board := range backup.boards {
+		board.Id = restore.ids.get(board.Id)
+		board.OwnedBy = workspace.Id
+		board.Watchers = restore.userList(board.Watchers)
+		boards = append(boards, board)
+	}
+	if len(boards) != 0 {
+		if _, err := boardsDb.InsertMany(context.TODO(), boards); err != nil {
+			return err
+		}
+	}
+
+	var lastNumber int64
+	for start := 0; start < len(backup.tasks); start += importBatchSize {
+		batch := backup.tasks[start:min(start+importBatchSize, len(backup.tasks))]
+		tasks := make([]Task, 0, len(batch))
+		for _, task := range batch {
+			task = restore.task(task, workspace.Id)
+			lastNumber = max(lastNumber, task.Number)
+			tasks = append(tasks, task)
+		}
+		if _, err := tasksDb.InsertMany(context.TODO(), tasks); err != nil {
+			return err
+		}
+		runner.advance(len(tasks))
+	}
+	// New tasks continue after the highest restored key
+	if _, err := countersDb.UpdateOne(context.TODO(), bson.D{{"_id", workspace.Id}}, bson.D{{"$max", bson.D{{"seq", lastNumber}}}}, options.UpdateOne().SetUpsert(true)); err != nil {
+		return err
+	}
+
+	views := make([]SavedView, 0, len(backup.views))
+	skippedViews := 0
+	for _, view := range backup.views {
+		if view, ok := restore.view(view, workspace.Id, actor); ok {
+			views = append(views, view)
+		} else {
+			skippedViews++
+		}
+	}
+	if len(views) != 0 {
+		if _, err := viewsDb.InsertMany(context.TODO(), views); err != nil {
+			return err
+		}
+	}
+	if skippedViews != 0 {
+		runner.warn("%d personal views of missing members were not restored", skippedViews)
+	}
+
+	runner.setResult("boards", len(boards))
+	runner.setResult("labels", len(labels))
+	runner.setResult("custom_fields", len(fields))
+	runner.setResult("views", len(views))
+	runner.setResult("tasks", len(backup.tasks))
+	runner.setResult("missing_members", missing)
+	runner.setResult("dropped_references", restore.dropped)
+	return nil
+}
+
+// @Summary 		Back up a workspace
+// @Description 	Streams a zip archive with the workspace, its boards, tasks, labels, custom fields, saved views, references to its members and their avatars. Documents are JSON lines of canonical extended JSON next to a versioned manifest.json. Invite links and webhooks are not part of the backup. Only the owner can back up a workspace.
+// @Router 			/workspaces/{workspaceId}/backup [get]
+// @Tags 			Import and export
+// @Security 		BearerAuth
+// @Produce 		application/zip
+// @Param 			workspaceId path string true "Workspace ID"
+// @Success 		200 {file} file "The backup archive"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not the owner of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - workspace not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func exportWorkspaceBackup(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceOwner(c)
+	if !ok {
+		return
+	}
+	members, err := backupMembers(workspace)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	filename := fmt.Sprintf("%s-backup-%s", workspace.Name, time.Now().UTC().Format("20060102"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, csvFilenameRegex.ReplaceAllString(filename, "_")))
+	c.Status(200)
+	archive := zip.NewWriter(c.Writer)
+	if err := writeBackup(archive, workspace, members); err != nil {
+		// The status is already sent, without the central directory the client gets an invalid archive
+		println("WARNING Workspace backup ended early: ", err.Error())
+		return
+	}
+	if err := archive.Close(); err != nil {
+		println("WARNING Failed to finish workspace backup: ", err.Error())
+	}
+}
+
+// @Summary 		Restore a workspace backup
+// @Description 	Starts a background job creating a new workspace you own from a backup archive, possibly made on another server. All documents get new ids. You become the only member, the other members are listed in the missing_members result of the job so they can be invited again, and their assignments are dropped. Avatars of members are not restored.
+// @Router 			/workspaces/restore [post]
+// @Tags 			Import and export
+// @Security 		BearerAuth
+// @Accept 			multipart/form-data
+// @Produce 		json
+// @Param 			file formData file true "Backup archive"
+// @Param 			name query string false "Name of the restored workspace instead of the original one"
+// @Success 		202 {object} Job "The started restore job"
+// @Failure 		400 {object} ErrorSwagger "Bad request - missing file, not a backup or an unsupported version"
+// @Failure 		413 {object} ErrorSwagger "The file is too large"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func restoreWorkspaceBackup(c *gin.Context) {
+	userId, _ := c.Get("id")
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'file' is required"})
+		return
+	} else if fileHeader.Size > maxBackupSize {
+		c.AbortWithStatusJSON(413, gin.H{"error": "The file is too large"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return
+	}
+	defer file.Close()
+	archive, err := zip.NewReader(file, fileHeader.Size)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "The file is not a zip archive"})
+		return
+	}
+	backup, err := readBackup(archive)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	name := strings.TrimSpace(c.Query("name"))
+	job, err := startJob(Job{Type: "restore", CreatedBy: userId.(bson.ObjectID)}, func(runner *jobRunner) error {
+		return restoreWorkspace(backup, name, userId.(bson.ObjectID), runner)
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to start restore"})
+		return
+	}
+	c.JSON(202, job)
+}
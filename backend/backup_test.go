@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestRestoreWorkspaceFailure(t *testing.T) {
+	useTestDatabase(t)
+	user := User{Id: bson.NewObjectID(), Name: "Ada", Email: "ada@example.com"}
+	if _, err := usersDb.InsertOne(context.TODO(), user); err != nil {
+		t.Fatal(err)
+	}
+	board := Board{Id: bson.NewObjectID(), Name: "Backlog"}
+	label := Label{Id: bson.NewObjectID(), Name: "bug"}
+	task := Task{Id: bson.NewObjectID(), Name: "Ship it", Board: board.Id}
+	// The task is in the archive twice, so inserting the tasks fails after everything else was created
+	backup := &workspaceBackup{
+		workspace: Workspace{Name: "Web", Key: "WEB"},
+		boards:    []Board{board},
+		labels:    []Label{label},
+		tasks:     []Task{task, task},
+	}
+	job, err := jobsDb.InsertOne(context.TODO(), Job{Type: "restore", CreatedBy: user.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := &jobRunner{job: Job{Id: job.InsertedID.(bson.ObjectID), Result: map[string]any{}}}
+
+	if err := restoreWorkspace(backup, "", user.Id, runner); err == nil {
+		t.Fatal("restoring a backup with duplicate tasks succeeded")
+	}
+	for name, collection := range map[string]*mongo.Collection{
+		"workspaces": workspacesDb,
+		"boards":     boardsDb,
+		"labels":     labelsDb,
+		"tasks":      tasksDb,
+		"counters":   countersDb,
+	} {
+		count, err := collection.CountDocuments(context.TODO(), bson.D{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 {
+			t.Errorf("%d %s left after a failed restore", count, name)
+		}
+	}
+	if !runner.job.Workspace.IsZero() {
+		t.Errorf("job workspace = %s, want none", runner.job.Workspace.Hex())
+	}
+}
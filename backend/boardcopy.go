@@ -0,0 +1,543 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func findWorkspaceBoard(c *gin.Context, workspace Workspace) (Board, bool) {
+	var board Board
+	boardId, err := bson.ObjectIDFromHex(c.Param("boardId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid boardId"})
+		return board, false
+	}
+	err = boardsDb.FindOne(context.TODO(), bson.D{{"_id", boardId}, {"owned_by", workspace.Id}}).Decode(&board)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Board does not exist"})
+		return board, false
+	} else if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return board, false
+	}
+	return board, true
+}
+
+// workspaceMembers returns the members of a workspace including its owner.
+func workspaceMembers(workspace Workspace) map[bson.ObjectID]bool {
+	members := map[bson.ObjectID]bool{workspace.OwnedBy: true}
+	for _, member := range workspace.Members {
+		members[member] = true
+	}
+	return members
+}
+
+// @Summary 		Duplicate a board
+// @Description 	Creates a copy of a board in the same workspace, optionally with copies of its tasks. Copied tasks get new keys and keep their labels, custom fields, assignees and the links between them. Links to tasks on other boards are not copied.
+// @Router 			/workspaces/{workspaceId}/boards/{boardId}/duplicate [post]
+// @Tags 			Boards
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			boardId path string true "Board ID"
+// @Param 			data body DuplicateBoard false "Name of the copy instead of the original name with (copy) and whether to copy tasks"
+// @Success 		200 {object} DuplicateBoardResponse "The created board and the number of copied tasks"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid board id"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - board not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func duplicateBoard(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	board, ok := findWorkspaceBoard(c, workspace)
+	if !ok {
+		return
+	}
+	var input DuplicateBoard
+	if c.Request.ContentLength != 0 {
+		if err := json.NewDecoder(c.Request.Body).Decode(&input); err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+			return
+		}
+	}
+	key, err := ensureWorkspaceKey(workspace)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	workspace.Key = key
+
+	// The copy shares labels, custom fields and members with the original, only tasks get new ids
+	restore := &backupRestore{ids: idMap{}, users: map[bson.ObjectID]bson.ObjectID{}, fields: map[string]string{}}
+	for member := range workspaceMembers(workspace) {
+		restore.users[member] = member
+	}
+	labels, err := workspaceLabels(workspace.Id)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	for _, label := range labels {
+		restore.ids[label.Id] = label.Id
+	}
+	fields, err := loadCustomFields(workspace.Id)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	for fieldId, field := range fields {
+		restore.ids[field.Id] = field.Id
+		restore.fields[fieldId] = field.Type
+	}
+
+	duplicate := Board{Id: restore.ids.get(board.Id), Name: strings.TrimSpace(input.Name), OwnedBy: workspace.Id}
+	if duplicate.Name == "" {
+		duplicate.Name = board.Name + " (copy)"
+	}
+	if _, err := boardsDb.InsertOne(context.TODO(), duplicate); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to create board"})
+		return
+	}
+	copied := 0
+	if input.IncludeTasks {
+		filter := bson.D{{"created_by", workspace.Id}, {"board", board.Id}}
+		taskIds, err := distinctIds(tasksDb, filter)
+		if err != nil {
+			discardBoard(workspace.Id, duplicate.Id)
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		restore.tasks = make(map[bson.ObjectID]bool, len(taskIds))
+		for _, taskId := range taskIds {
+			restore.tasks[taskId] = true
+		}
+		if copied, _, err = copyTasks(restore, filter, workspace, true, nil); err != nil {
+			discardBoard(workspace.Id, duplicate.Id)
+			c.AbortWithStatusJSON(500, gin.H{"error": "Failed to copy tasks"})
+			return
+		}
+	}
+	publishEvent(Event{Type: eventBoardCreated, Workspace: workspace.Id, BoardId: duplicate.Id, Actor: userId.(bson.ObjectID), Board: &duplicate})
+	c.JSON(200, DuplicateBoardResponse{Board: duplicate, Tasks: copied})
+}
+
+// discardBoard removes a board copy that failed halfway together with the tasks copied onto it.
+func discardBoard(workspaceId bson.ObjectID, boardId bson.ObjectID) {
+	if _, err := tasksDb.DeleteMany(context.TODO(), bson.D{{"created_by", workspaceId}, {"board", boardId}}); err != nil {
+		println("WARNING failed to remove tasks of board copy " + boardId.Hex() + ": " + err.Error())
+		return
+	}
+	if _, err := boardsDb.DeleteOne(context.TODO(), bson.D{{"_id", boardId}}); err != nil {
+		println("WARNING failed to remove board copy " + boardId.Hex() + ": " + err.Error())
+	}
+}
+
+// transferLabels maps the labels used on a board to the labels of the target workspace with the same
+// name, creating the ones it lacks.
+func transferLabels(source bson.ObjectID, target bson.ObjectID, used map[bson.ObjectID]bool) (map[bson.ObjectID]bson.ObjectID, error) {
+	mapped := map[bson.ObjectID]bson.ObjectID{}
+	sourceLabels, err := workspaceLabels(source)
+	if err != nil {
+		return nil, err
+	}
+	targetLabels, err := workspaceLabels(target)
+	if err != nil {
+		return nil, err
+	}
+	for _, label := range sourceLabels {
+		if !used[label.Id] {
+			continue
+		}
+		index := slices.IndexFunc(targetLabels, func(existing Label) bool { return strings.EqualFold(existing.Name, label.Name) })
+		if index != -1 {
+			mapped[label.Id] = targetLabels[index].Id
+			continue
+		}
+		created := Label{Name: label.Name, Color: label.Color, Workspace: target}
+		result, err := labelsDb.InsertOne(context.TODO(), created)
+		if err != nil {
+			return nil, err
+		}
+		created.Id = result.InsertedID.(bson.ObjectID)
+		mapped[label.Id] = created.Id
+		targetLabels = append(targetLabels, created)
+	}
+	return mapped, nil
+}
+
+// transferCustomFields maps the custom fields used on a board to the fields of the target workspace
+// with the same name and type, creating the ones it lacks. Options of select fields are merged.
+func transferCustomFields(source bson.ObjectID, target bson.ObjectID, used map[string]bool) (map[string]CustomField, error) {
+	mapped := map[string]CustomField{}
+	sourceFields, err := loadCustomFields(source)
+	if err != nil {
+		return nil, err
+	}
+	targetFields, err := loadCustomFields(target)
+	if err != nil {
+		return nil, err
+	}
+	for fieldId, field := range sourceFields {
+		if !used[fieldId] {
+			continue
+		}
+		var match *CustomField
+		for _, existing := range targetFields {
+			if strings.EqualFold(existing.Name, field.Name) && existing.Type == field.Type {
+				match = &existing
+				break
+			}
+		}
+		if match != nil {
+			if len(field.Options) != 0 {
+				update := bson.D{{"$addToSet", bson.D{{"options", bson.D{{"$each", field.Options}}}}}}
+				if _, err := customFieldsDb.UpdateOne(context.TODO(), bson.D{{"_id", match.Id}}, update); err != nil {
+					return nil, err
+				}
+			}
+			mapped[fieldId] = *match
+			continue
+		}
+		created := CustomField{Name: field.Name, Type: field.Type, Options: field.Options, Workspace: target}
+		result, err := customFieldsDb.InsertOne(context.TODO(), created)
+		if err != nil {
+			return nil, err
+		}
+		created.Id = result.InsertedID.(bson.ObjectID)
+		mapped[fieldId] = created
+		targetFields[created.Id.Hex()] = created
+	}
+	return mapped, nil
+}
+
+// maxTransferPasses bounds how often a transfer looks for tasks added to the board while it runs.
+const maxTransferPasses = 3
+
+// boardTransfer moves the tasks of a board that was moved to another workspace. Tasks are moved in
+// passes, so tasks created on the board while a pass runs are picked up by the next one.
+type boardTransfer struct {
+	board        Board
+	source       Workspace
+	target       Workspace
+	members      map[bson.ObjectID]bool
+	onBoard      map[bson.ObjectID]bool
+	series       idMap
+	removedLinks int
+}
+
+func (t *boardTransfer) keepMembers(ids []bson.ObjectID) []bson.ObjectID {
+	return slices.DeleteFunc(ids, func(id bson.ObjectID) bool { return !t.members[id] })
+}
+
+// moveTasks moves the tasks of the board that are still in the source workspace and returns how many.
+func (t *boardTransfer) moveTasks() (int, error) {
+	filter := bson.D{{"created_by", t.source.Id}, {"board", t.board.Id}}
+	cursor, err := tasksDb.Find(context.TODO(), filter, options.Find().SetSort(bson.D{{"number", 1}, {"_id", 1}}))
+	if err != nil {
+		return 0, err
+	}
+	tasks := make([]Task, 0)
+	if err := cursor.All(context.TODO(), &tasks); err != nil || len(tasks) == 0 {
+		return 0, err
+	}
+	usedLabels := map[bson.ObjectID]bool{}
+	usedFields := map[string]bool{}
+	externalIds := make([]string, 0)
+	for _, task := range tasks {
+		t.onBoard[task.Id] = true
+		for _, label := range task.Labels {
+			usedLabels[label] = true
+		}
+		for fieldId := range task.CustomFields {
+			usedFields[fieldId] = true
+		}
+		if task.ExternalId != "" {
+			externalIds = append(externalIds, task.ExternalId)
+		}
+	}
+	labels, err := transferLabels(t.source.Id, t.target.Id, usedLabels)
+	if err != nil {
+		return 0, err
+	}
+	fields, err := transferCustomFields(t.source.Id, t.target.Id, usedFields)
+	if err != nil {
+		return 0, err
+	}
+	// External ids the target already uses would break the uniqueness of importer matches
+	takenExternalIds := map[string]bool{}
+	if len(externalIds) != 0 {
+		var taken []string
+		if err := tasksDb.Distinct(context.TODO(), "external_id", bson.D{{"created_by", t.target.Id}, {"external_id", bson.D{{"$in", externalIds}}}}).Decode(&taken); err != nil {
+			return 0, err
+		}
+		for _, externalId := range taken {
+			takenExternalIds[externalId] = true
+		}
+	}
+	first, err := reserveTaskNumbers(t.target.Id, int64(len(tasks)))
+	if err != nil {
+		return 0, err
+	}
+
+	moved := make([]bson.ObjectID, 0, len(tasks))
+	count := 0
+	for start := 0; start < len(tasks); start += importBatchSize {
+		batch := tasks[start:min(start+importBatchSize, len(tasks))]
+		models := make([]mongo.WriteModel, 0, len(batch))
+		for i, task := range batch {
+			task.CreatedBy = t.target.Id
+			task.Number = first + int64(start+i)
+			task.Key = formatTaskKey(t.target.Key, task.Number)
+			mappedLabels := make([]bson.ObjectID, 0, len(task.Labels))
+			for _, label := range task.Labels {
+				if mapped, ok := labels[label]; ok {
+					mappedLabels = append(mappedLabels, mapped)
+				}
+			}
+			task.Labels = mappedLabels
+			values := make(map[string]any, len(task.CustomFields))
+			for fieldId, value := range task.CustomFields {
+				field, ok := fields[fieldId]
+				if !ok {
+					continue
+				}
+				if id, isId := value.(bson.ObjectID); field.Type == fieldUser && isId && !t.members[id] {
+					continue
+				}
+				values[field.Id.Hex()] = value
+			}
+			task.CustomFields = values
+			task.Assignees = t.keepMembers(task.Assignees)
+			task.Watchers = t.keepMembers(task.Watchers)
+			links := len(task.Links)
+			task.Links = slices.DeleteFunc(task.Links, func(link TaskLink) bool { return !t.onBoard[link.Task] })
+			t.removedLinks += links - len(task.Links)
+			if takenExternalIds[task.ExternalId] {
+				task.ExternalId = ""
+			}
+			if task.Recurrence != nil {
+				// Occurrences that stay behind keep the series in the source, the moved ones continue as a series of their own
+				if !t.onBoard[task.Recurrence.Series] {
+					task.Recurrence.Series = t.series.get(task.Recurrence.Series)
+				}
+				// The next occurrence already exists in the source, so this one must not spawn another
+				if task.Recurrence.NextTask != nil && !t.onBoard[*task.Recurrence.NextTask] {
+					task.Recurrence.NextTask = nil
+					task.Recurrence.Ended = true
+				}
+				task.Recurrence.ClaimedUntil = 0
+			}
+			// Tasks that were moved off the board or deleted in the meantime stay where they are
+			models = append(models, mongo.NewReplaceOneModel().SetFilter(bson.D{{"_id", task.Id}, {"created_by", t.source.Id}, {"board", t.board.Id}}).SetReplacement(task))
+			moved = append(moved, task.Id)
+		}
+		result, err := tasksDb.BulkWrite(context.TODO(), models)
+		if result != nil {
+			count += int(result.MatchedCount)
+		}
+		if err != nil {
+			return count, err
+		}
+	}
+	// Links are stored on both tasks, the tasks that stay behind drop theirs too
+	if _, err := tasksDb.UpdateMany(context.TODO(), bson.D{{"created_by", t.source.Id}, {"links.task", bson.D{{"$in", moved}}}}, bson.D{{"$pull", bson.D{{"links", bson.D{{"task", bson.D{{"$in", moved}}}}}}}}); err != nil {
+		return count, err
+	}
+	// Notifications follow their tasks, users that can't see the target workspace lose theirs
+	memberIds := make([]bson.ObjectID, 0, len(t.members))
+	for member := range t.members {
+		memberIds = append(memberIds, member)
+	}
+	if _, err := notificationsDb.DeleteMany(context.TODO(), bson.D{{"task", bson.D{{"$in", moved}}}, {"user", bson.D{{"$nin", memberIds}}}}); err != nil {
+		return count, err
+	}
+	if _, err := notificationsDb.UpdateMany(context.TODO(), bson.D{{"task", bson.D{{"$in", moved}}}}, bson.D{{"$set", bson.D{{"workspace", t.target.Id}}}}); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+// unmoved lists the tasks of the board still left in the source workspace.
+func (t *boardTransfer) unmoved() ([]string, error) {
+	cursor, err := tasksDb.Find(context.TODO(), bson.D{{"created_by", t.source.Id}, {"board", t.board.Id}}, options.Find().SetProjection(bson.D{{"_id", 1}, {"key", 1}, {"name", 1}}))
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]Task, 0)
+	if err := cursor.All(context.TODO(), &tasks); err != nil {
+		return nil, err
+	}
+	titles := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		titles = append(titles, taskTitle(task))
+	}
+	return titles, nil
+}
+
+// @Summary 		Transfer a board to another workspace
+// @Description 	Moves a board with all its tasks to another workspace you own. Tasks keep their ids but get new keys of the target workspace. Labels and custom fields are matched by name in the target workspace and created there if missing. Links to tasks on other boards are removed, as are saved views of the board. Recurring tasks whose next occurrence stays in the source workspace stop repeating, and notifications of moved tasks move along. The board is moved first and its tasks afterwards, tasks added to it in the meantime that couldn't be moved are listed in unmoved_tasks; if moving tasks fails the 500 response lists the tasks left behind. If assignees aren't members of the target workspace the transfer is refused with a 409 listing them, unless force is set: then their assignments, watches and user field values are removed and they are listed in non_members. Only the owner of both workspaces can transfer boards.
+// @Router 			/workspaces/{workspaceId}/boards/{boardId}/transfer [post]
+// @Tags 			Boards
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			boardId path string true "Board ID"
+// @Param 			data body TransferBoard true "The target workspace"
+// @Success 		200 {object} TransferBoardResponse "The moved board and what had to be removed"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid board id or target workspace"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not the owner of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - board not found"
+// @Failure 		409 {object} ErrorSwagger "Conflict - assignees aren't members of the target workspace, listed in non_members"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func transferBoard(c *gin.Context) {
+	source, ok := authorizeWorkspaceOwner(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	board, ok := findWorkspaceBoard(c, source)
+	if !ok {
+		return
+	}
+	var input TransferBoard
+	if err := json.NewDecoder(c.Request.Body).Decode(&input); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return
+	}
+	var target Workspace
+	err := workspacesDb.FindOne(context.TODO(), bson.D{{"_id", input.Workspace}}).Decode(&target)
+	if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && target.OwnedBy != userId.(bson.ObjectID)) {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'workspace' must be a workspace you own"})
+		return
+	} else if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	} else if target.Id == source.Id {
+		c.AbortWithStatusJSON(400, gin.H{"error": "The board already belongs to this workspace"})
+		return
+	}
+	if target.Key, err = ensureWorkspaceKey(target); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	cursor, err := tasksDb.Find(context.TODO(), bson.D{{"created_by", source.Id}, {"board", board.Id}}, options.Find().SetSort(bson.D{{"number", 1}, {"_id", 1}}))
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	tasks := make([]Task, 0)
+	if err := cursor.All(context.TODO(), &tasks); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	members := workspaceMembers(target)
+	onBoard := map[bson.ObjectID]bool{}
+	nonMembers := map[bson.ObjectID][]string{}
+	for _, task := range tasks {
+		onBoard[task.Id] = true
+		for _, assignee := range task.Assignees {
+			if !members[assignee] {
+				nonMembers[assignee] = append(nonMembers[assignee], taskTitle(task))
+			}
+		}
+	}
+	report := make([]NonMemberAssignee, 0, len(nonMembers))
+	if len(nonMembers) != 0 {
+		ids := make([]bson.ObjectID, 0, len(nonMembers))
+		for id := range nonMembers {
+			ids = append(ids, id)
+		}
+		cursor, err := usersDb.Find(context.TODO(), bson.D{{"_id", bson.D{{"$in", ids}}}}, options.Find().SetProjection(bson.D{{"_id", 1}, {"name", 1}, {"email", 1}}))
+		if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		users := make([]User, 0)
+		if err := cursor.All(context.TODO(), &users); err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		for _, id := range ids {
+			assignee := NonMemberAssignee{Id: id, Tasks: nonMembers[id]}
+			// Deleted users are reported by id only
+			if index := slices.IndexFunc(users, func(user User) bool { return user.Id == id }); index != -1 {
+				assignee.Name, assignee.Email = users[index].Name, users[index].Email
+			}
+			report = append(report, assignee)
+		}
+		if !input.Force {
+			c.AbortWithStatusJSON(409, gin.H{"error": "Some assignees are not members of the target workspace, invite them or transfer with force", "non_members": report})
+			return
+		}
+	}
+
+	// Moving the board first keeps new tasks from being moved onto it in the source workspace. Tasks
+	// that still end up there meanwhile are moved by the following passes.
+	board.OwnedBy = target.Id
+	board.Watchers = slices.DeleteFunc(board.Watchers, func(id bson.ObjectID) bool { return !members[id] })
+	result, err := boardsDb.UpdateOne(context.TODO(), bson.D{{"_id", board.Id}, {"owned_by", source.Id}}, bson.D{{"$set", bson.D{{"owned_by", target.Id}, {"watchers", board.Watchers}}}})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to transfer board"})
+		return
+	} else if result.MatchedCount == 0 {
+		c.AbortWithStatusJSON(409, gin.H{"error": "The board was moved or deleted in the meantime"})
+		return
+	}
+	transfer := &boardTransfer{
+		board:   board,
+		source:  source,
+		target:  target,
+		members: members,
+		onBoard: onBoard,
+		series:  idMap{},
+	}
+	moved := 0
+	for pass := 0; pass < maxTransferPasses; pass++ {
+		count, err := transfer.moveTasks()
+		if err != nil {
+			// The board already belongs to the target, the tasks left behind are reported so they can be moved by hand
+			unmoved, _ := transfer.unmoved()
+			c.AbortWithStatusJSON(500, gin.H{"error": "Failed to transfer tasks", "moved_tasks": moved + count, "unmoved_tasks": unmoved})
+			return
+		}
+		if count == 0 {
+			break
+		}
+		moved += count
+	}
+	unmoved, err := transfer.unmoved()
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	views, err := viewsDb.DeleteMany(context.TODO(), bson.D{{"workspace", source.Id}, {"board", board.Id}})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to remove views of the board"})
+		return
+	}
+	publishEvent(Event{Type: eventBoardDeleted, Workspace: source.Id, BoardId: board.Id, Actor: userId.(bson.ObjectID), Board: &board})
+	publishEvent(Event{Type: eventBoardCreated, Workspace: target.Id, BoardId: board.Id, Actor: userId.(bson.ObjectID), Board: &board})
+	c.JSON(200, TransferBoardResponse{
+		Board:        board,
+		Tasks:        moved,
+		NonMembers:   report,
+		RemovedLinks: transfer.removedLinks,
+		RemovedViews: int(views.DeletedCount),
+		UnmovedTasks: unmoved,
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/goccy/go-json"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"slices"
 	"sync"
 )
@@ -56,12 +57,14 @@ func addBoard(c *gin.Context) {
 	}
 
 	input.OwnedBy = workspaceId
+	input.Watchers = nil
 	result, err := boardsDb.InsertOne(context.TODO(), input)
 	if err != nil {
 		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to create board"})
 		return
 	}
 	input.Id = result.InsertedID.(bson.ObjectID)
+	publishEvent(Event{Type: eventBoardCreated, Workspace: workspaceId, BoardId: input.Id, Actor: userId.(bson.ObjectID), Board: &input})
 	c.JSON(200, input)
 }
 
@@ -134,6 +137,22 @@ func deleteBoard(c *gin.Context) {
 		return
 	}
 
+	// Collect ids of tasks on the board so links pointing to them can be cleaned up
+	taskIds := make([]bson.ObjectID, 0)
+	idsCursor, err := tasksDb.Find(context.TODO(), bson.D{{"board", boardId}}, options.Find().SetProjection(bson.D{{"_id", 1}}))
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to retrieve tasks on board"})
+		return
+	}
+	boardTasks := make([]Task, 0)
+	if err := idsCursor.All(context.TODO(), &boardTasks); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to retrieve tasks on board"})
+		return
+	}
+	for _, task := range boardTasks {
+		taskIds = append(taskIds, task.Id)
+	}
+
 	wg.Add(2)
 	var boardDeleteErr, tasksDeleteErr error
 	go func() {
@@ -154,6 +173,13 @@ func deleteBoard(c *gin.Context) {
 		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to remove tasks on board"})
 		return
 	}
+	if len(taskIds) > 0 {
+		if _, err := tasksDb.UpdateMany(context.TODO(), bson.D{{"links.task", bson.D{{"$in", taskIds}}}}, bson.D{{"$pull", bson.D{{"links", bson.D{{"task", bson.D{{"$in", taskIds}}}}}}}}); err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Failed to remove links to tasks on board"})
+			return
+		}
+	}
+	publishEvent(Event{Type: eventBoardDeleted, Workspace: workspaceId, BoardId: boardId, Actor: userId.(bson.ObjectID), Board: &board})
 	c.AbortWithStatus(200)
 }
 
@@ -226,17 +252,21 @@ func editBoard(c *gin.Context) {
 		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to update board"})
 		return
 	}
+	publishEvent(Event{Type: eventBoardUpdated, Workspace: workspaceId, BoardId: boardId, Actor: userId.(bson.ObjectID), Board: &board})
 	c.JSON(200, board)
 }
 
 // @Summary 		Get all boards
-// @Description 	Returns all boards for a given workspace.
+// @Description 	Returns all boards for a given workspace together with the estimate totals of their tasks.
 // @Router 			/workspaces/{workspaceId}/boards [get]
 // @Tags 			Boards
 // @Security 		BearerAuth
 // @Produce 		json
 // @Param 			workspaceId path string true "Workspace ID"
-// @Success 		200 {object} AllBoardsResponse "A list of boards"
+// @Param 			limit query int false "Page size, max 500. Without limit and cursor all items are returned"
+// @Param 			cursor query string false "Cursor from a previous page"
+// @Success 		200 {object} AllBoardsResponse "A page of boards"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid limit or cursor"
 // @Failure			403 {object} ErrorSwagger "Forbidden"
 // @Failure 		500 {object} ErrorSwagger "Internal Server Error"
 func getAllBoards(c *gin.Context) {
@@ -259,12 +289,23 @@ func getAllBoards(c *gin.Context) {
 		return
 	}
 
-	cursor, _ := boardsDb.Find(context.TODO(), bson.D{{"owned_by", workspaceId}})
-	boards := make([]Board, 0)
-	_ = cursor.All(context.TODO(), &boards)
-	c.IndentedJSON(200, gin.H{"boards": boards})
-	if err := cursor.Close(context.TODO()); err != nil {
-		// Log the error but don't abort, as the response has already been sent.
-		println("Failed to close cursor")
+	page, ok := parseListRequest(c, "", "$_id", false)
+	if !ok {
+		return
+	}
+	boards, nextCursor, err := paginate[Board](boardsDb, bson.D{{"owned_by", workspaceId}}, page)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal server error"})
+		return
+	}
+	totals, err := estimateTotals(bson.D{{"created_by", workspaceId}})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal server error"})
+		return
+	}
+	for i := range boards {
+		boardTotals := totals[boards[i].Id]
+		boards[i].Estimate = &boardTotals
 	}
+	c.JSON(200, gin.H{"boards": boards, "next_cursor": nextCursor})
 }
@@ -0,0 +1,717 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	caldavRoot         = "/api/v1/caldav/"
+	maxCaldavBody      = 1 << 20
+	caldavContentType  = "text/calendar; charset=utf-8; component=VTODO"
+	davNamespace       = "DAV:"
+	caldavNamespace    = "urn:ietf:params:xml:ns:caldav"
+	calendarServerSpec = "http://calendarserver.org/ns/"
+)
+
+var davPrefixes = map[string]string{davNamespace: "d", caldavNamespace: "cal", calendarServerSpec: "cs"}
+
+var (
+	davResourceType     = xml.Name{Space: davNamespace, Local: "resourcetype"}
+	davDisplayName      = xml.Name{Space: davNamespace, Local: "displayname"}
+	davPrincipal        = xml.Name{Space: davNamespace, Local: "current-user-principal"}
+	davPrincipalUrl     = xml.Name{Space: davNamespace, Local: "principal-URL"}
+	davOwner            = xml.Name{Space: davNamespace, Local: "owner"}
+	davPrivileges       = xml.Name{Space: davNamespace, Local: "current-user-privilege-set"}
+	davSupportedReports = xml.Name{Space: davNamespace, Local: "supported-report-set"}
+	davEtag             = xml.Name{Space: davNamespace, Local: "getetag"}
+	davContentType      = xml.Name{Space: davNamespace, Local: "getcontenttype"}
+	calHomeSet          = xml.Name{Space: caldavNamespace, Local: "calendar-home-set"}
+	calUserAddressSet   = xml.Name{Space: caldavNamespace, Local: "calendar-user-address-set"}
+	calComponentSet     = xml.Name{Space: caldavNamespace, Local: "supported-calendar-component-set"}
+	calDescription      = xml.Name{Space: caldavNamespace, Local: "calendar-description"}
+	calData             = xml.Name{Space: caldavNamespace, Local: "calendar-data"}
+	csCtag              = xml.Name{Space: calendarServerSpec, Local: "getctag"}
+)
+
+// CalDAV priorities back to Rela, 0 means undefined
+var caldavPriorities = []string{"none", "urgent", "high", "high", "high", "medium", "low", "low", "low", "low"}
+
+type davAny struct {
+	XMLName xml.Name
+}
+
+type davPropNames struct {
+	Names []davAny `xml:",any"`
+}
+
+// davRequest is the body of a PROPFIND or REPORT. Without a prop element all properties are returned.
+type davRequest struct {
+	XMLName xml.Name
+	Prop    *davPropNames `xml:"DAV: prop"`
+	Hrefs   []string      `xml:"DAV: href"`
+}
+
+func (r davRequest) wants(name xml.Name) bool {
+	if r.Prop == nil {
+		return name != calData
+	}
+	return slices.ContainsFunc(r.Prop.Names, func(prop davAny) bool { return prop.XMLName == name })
+}
+
+// davResource is a resource of a multistatus response with the values of its properties as XML.
+// Resources without properties don't exist.
+type davResource struct {
+	href  string
+	props map[xml.Name]string
+}
+
+func xmlText(value string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
+
+func davHref(path string) string {
+	return "<d:href>" + xmlText(path) + "</d:href>"
+}
+
+func davElement(name xml.Name, value string) string {
+	prefix, ok := davPrefixes[name.Space]
+	namespace := ""
+	if !ok {
+		prefix, namespace = "x", fmt.Sprintf(` xmlns:x="%s"`, xmlText(name.Space))
+	}
+	if value == "" {
+		return fmt.Sprintf("<%s:%s%s/>", prefix, name.Local, namespace)
+	}
+	return fmt.Sprintf("<%s:%s%s>%s</%s:%s>", prefix, name.Local, namespace, value, prefix, name.Local)
+}
+
+// writeMultistatus answers with the requested properties of each resource, properties it doesn't
+// have are listed as not found.
+func writeMultistatus(c *gin.Context, request davRequest, resources []davResource) {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	b.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">`)
+	for _, resource := range resources {
+		b.WriteString("<d:response>" + davHref(resource.href))
+		if resource.props == nil {
+			b.WriteString("<d:status>HTTP/1.1 404 Not Found</d:status></d:response>")
+			continue
+		}
+		found, missing := make([]string, 0), make([]string, 0)
+		if request.Prop == nil {
+			for name, value := range resource.props {
+				if request.wants(name) {
+					found = append(found, davElement(name, value))
+				}
+			}
+		} else {
+			for _, prop := range request.Prop.Names {
+				if value, ok := resource.props[prop.XMLName]; ok {
+					found = append(found, davElement(prop.XMLName, value))
+				} else {
+					missing = append(missing, davElement(prop.XMLName, ""))
+				}
+			}
+		}
+		slices.Sort(found)
+		if len(found) != 0 {
+			b.WriteString("<d:propstat><d:prop>" + strings.Join(found, "") + "</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>")
+		}
+		if len(missing) != 0 {
+			b.WriteString("<d:propstat><d:prop>" + strings.Join(missing, "") + "</d:prop><d:status>HTTP/1.1 404 Not Found</d:status></d:propstat>")
+		}
+		b.WriteString("</d:response>")
+	}
+	b.WriteString("</d:multistatus>")
+	c.Data(207, "application/xml; charset=utf-8", []byte(b.String()))
+}
+
+func caldavPrincipal(userId bson.ObjectID) string {
+	return caldavRoot + "principals/" + userId.Hex() + "/"
+}
+
+func caldavCalendar(boardId bson.ObjectID) string {
+	return caldavRoot + "calendars/" + boardId.Hex() + "/"
+}
+
+// caldavName is the resource name of a task, the one a client chose or one derived from its id.
+func caldavName(task Task) string {
+	if task.IcalName != "" {
+		return task.IcalName
+	}
+	return task.Id.Hex() + ".ics"
+}
+
+func caldavData(task Task) string {
+	calendar := newIcalCalendar("")
+	calendar.todo(task)
+	return calendar.end()
+}
+
+func caldavEtag(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// caldavTask is a task with its CalDAV representation.
+type caldavTask struct {
+	task Task
+	data string
+	etag string
+}
+
+func newCaldavTask(task Task) caldavTask {
+	data := caldavData(task)
+	return caldavTask{task: task, data: data, etag: caldavEtag(data)}
+}
+
+func (t caldavTask) resource(calendar string, request davRequest) davResource {
+	props := map[xml.Name]string{
+		davResourceType: "",
+		davEtag:         xmlText(t.etag),
+		davContentType:  caldavContentType,
+	}
+	if request.wants(calData) {
+		props[calData] = xmlText(t.data)
+	}
+	return davResource{href: calendar + url.PathEscape(caldavName(t.task)), props: props}
+}
+
+// caldavBoard returns a board and its workspace if the user is a member of it.
+func caldavBoard(boardHex string, userId bson.ObjectID) (Board, Workspace, error) {
+	var board Board
+	var workspace Workspace
+	boardId, err := bson.ObjectIDFromHex(boardHex)
+	if err != nil {
+		return board, workspace, mongo.ErrNoDocuments
+	}
+	if err := boardsDb.FindOne(context.TODO(), bson.D{{"_id", boardId}}).Decode(&board); err != nil {
+		return board, workspace, err
+	}
+	workspace, err = memberWorkspace(board.OwnedBy, userId)
+	return board, workspace, err
+}
+
+func caldavBoardTasks(board Board) ([]caldavTask, error) {
+	cursor, err := tasksDb.Find(context.TODO(), bson.D{{"board", board.Id}, {"created_by", board.OwnedBy}}, options.Find().SetSort(bson.D{{"_id", 1}}))
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]Task, 0)
+	if err := cursor.All(context.TODO(), &tasks); err != nil {
+		return nil, err
+	}
+	items := make([]caldavTask, 0, len(tasks))
+	for _, task := range tasks {
+		items = append(items, newCaldavTask(task))
+	}
+	return items, nil
+}
+
+// caldavFindTask finds a task of a board by its resource name.
+func caldavFindTask(board Board, name string) (Task, error) {
+	var task Task
+	err := tasksDb.FindOne(context.TODO(), bson.D{{"board", board.Id}, {"created_by", board.OwnedBy}, {"ical_name", name}}).Decode(&task)
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return task, err
+	}
+	taskId, err := bson.ObjectIDFromHex(strings.TrimSuffix(name, ".ics"))
+	if err != nil {
+		return task, mongo.ErrNoDocuments
+	}
+	err = tasksDb.FindOne(context.TODO(), bson.D{{"_id", taskId}, {"board", board.Id}, {"created_by", board.OwnedBy}}).Decode(&task)
+	return task, err
+}
+
+func caldavCalendarProps(board Board, workspace Workspace, userId bson.ObjectID, items []caldavTask) davResource {
+	etags := make([]string, 0, len(items))
+	for _, item := range items {
+		etags = append(etags, item.etag)
+	}
+	ctag := caldavEtag(board.Id.Hex() + strings.Join(etags, ""))
+	return davResource{href: caldavCalendar(board.Id), props: map[xml.Name]string{
+		davResourceType:     "<d:collection/><cal:calendar/>",
+		davDisplayName:      xmlText(workspace.Name + " / " + board.Name),
+		davPrincipal:        davHref(caldavPrincipal(userId)),
+		davOwner:            davHref(caldavPrincipal(userId)),
+		davPrivileges:       "<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege><d:privilege><d:write-content/></d:privilege><d:privilege><d:bind/></d:privilege><d:privilege><d:unbind/></d:privilege>",
+		davSupportedReports: "<d:supported-report><d:report><cal:calendar-query/></d:report></d:supported-report><d:supported-report><d:report><cal:calendar-multiget/></d:report></d:supported-report>",
+		davEtag:             xmlText(ctag),
+		calComponentSet:     `<cal:comp name="VTODO"/>`,
+		calDescription:      xmlText("Tasks of the board " + board.Name),
+		csCtag:              xmlText(ctag),
+	}}
+}
+
+func caldavError(c *gin.Context, status int, condition string) {
+	c.Data(status, "application/xml; charset=utf-8", []byte(`<?xml version="1.0" encoding="utf-8"?>`+"\n"+`<d:error xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">`+condition+`</d:error>`))
+	c.Abort()
+}
+
+// caldavPropfind answers PROPFIND on the root, the principal, the calendar home, a board or a task.
+func caldavPropfind(c *gin.Context, userId bson.ObjectID, segments []string, request davRequest) {
+	depth := c.GetHeader("Depth")
+	children := depth != "0"
+	principal := davHref(caldavPrincipal(userId))
+	resources := make([]davResource, 0)
+	switch {
+	case len(segments) == 0:
+		resources = append(resources, davResource{href: caldavRoot, props: map[xml.Name]string{
+			davResourceType: "<d:collection/>",
+			davDisplayName:  "Rela",
+			davPrincipal:    principal,
+		}})
+	case segments[0] == "principals" && len(segments) == 2:
+		if segments[1] != userId.Hex() {
+			c.AbortWithStatus(404)
+			return
+		}
+		var user User
+		if err := usersDb.FindOne(context.TODO(), bson.D{{"_id", userId}}).Decode(&user); err != nil {
+			c.AbortWithStatus(500)
+			return
+		}
+		resources = append(resources, davResource{href: caldavPrincipal(userId), props: map[xml.Name]string{
+			davResourceType:   "<d:collection/><d:principal/>",
+			davDisplayName:    xmlText(user.Name),
+			davPrincipal:      principal,
+			davPrincipalUrl:   principal,
+			calHomeSet:        davHref(caldavRoot + "calendars/"),
+			calUserAddressSet: davHref("mailto:" + user.Email),
+		}})
+	case segments[0] == "calendars" && len(segments) == 1:
+		resources = append(resources, davResource{href: caldavRoot + "calendars/", props: map[xml.Name]string{
+			davResourceType: "<d:collection/>",
+			davDisplayName:  "Calendars",
+			davPrincipal:    principal,
+		}})
+		if children {
+			workspaceIds, err := distinctIds(workspacesDb, bson.D{{"$or", bson.A{bson.D{{"members", userId}}, bson.D{{"owned_by", userId}}}}})
+			if err != nil {
+				c.AbortWithStatus(500)
+				return
+			}
+			cursor, err := boardsDb.Find(context.TODO(), bson.D{{"owned_by", bson.D{{"$in", workspaceIds}}}})
+			if err != nil {
+				c.AbortWithStatus(500)
+				return
+			}
+			boards := make([]Board, 0)
+			if err := cursor.All(context.TODO(), &boards); err != nil {
+				c.AbortWithStatus(500)
+				return
+			}
+			for _, board := range boards {
+				workspace, err := memberWorkspace(board.OwnedBy, userId)
+				if err != nil {
+					continue
+				}
+				items, err := caldavBoardTasks(board)
+				if err != nil {
+					c.AbortWithStatus(500)
+					return
+				}
+				resources = append(resources, caldavCalendarProps(board, workspace, userId, items))
+			}
+		}
+	case segments[0] == "calendars" && len(segments) <= 3:
+		board, workspace, err := caldavBoard(segments[1], userId)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.AbortWithStatus(404)
+			return
+		} else if err != nil {
+			c.AbortWithStatus(500)
+			return
+		}
+		if len(segments) == 3 {
+			task, err := caldavFindTask(board, segments[2])
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				c.AbortWithStatus(404)
+				return
+			} else if err != nil {
+				c.AbortWithStatus(500)
+				return
+			}
+			resources = append(resources, newCaldavTask(task).resource(caldavCalendar(board.Id), request))
+			break
+		}
+		items, err := caldavBoardTasks(board)
+		if err != nil {
+			c.AbortWithStatus(500)
+			return
+		}
+		resources = append(resources, caldavCalendarProps(board, workspace, userId, items))
+		if children {
+			for _, item := range items {
+				resources = append(resources, item.resource(caldavCalendar(board.Id), request))
+			}
+		}
+	default:
+		c.AbortWithStatus(404)
+		return
+	}
+	writeMultistatus(c, request, resources)
+}
+
+// caldavReport answers calendar-query with all tasks of a board and calendar-multiget with the listed ones.
+func caldavReport(c *gin.Context, userId bson.ObjectID, segments []string, request davRequest) {
+	if len(segments) != 2 || segments[0] != "calendars" {
+		c.AbortWithStatus(404)
+		return
+	}
+	board, _, err := caldavBoard(segments[1], userId)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatus(404)
+		return
+	} else if err != nil {
+		c.AbortWithStatus(500)
+		return
+	}
+	items, err := caldavBoardTasks(board)
+	if err != nil {
+		c.AbortWithStatus(500)
+		return
+	}
+	calendar := caldavCalendar(board.Id)
+	resources := make([]davResource, 0)
+	switch request.XMLName {
+	case xml.Name{Space: caldavNamespace, Local: "calendar-query"}:
+		for _, item := range items {
+			resources = append(resources, item.resource(calendar, request))
+		}
+	case xml.Name{Space: caldavNamespace, Local: "calendar-multiget"}:
+		for _, href := range request.Hrefs {
+			if parsed, err := url.Parse(strings.TrimSpace(href)); err == nil {
+				href = parsed.Path
+			}
+			index := slices.IndexFunc(items, func(item caldavTask) bool { return calendar+caldavName(item.task) == href })
+			if index == -1 {
+				resources = append(resources, davResource{href: href})
+				continue
+			}
+			resources = append(resources, items[index].resource(calendar, request))
+		}
+	default:
+		caldavError(c, 403, "<d:supported-report/>")
+		return
+	}
+	writeMultistatus(c, request, resources)
+}
+
+// applyTodo copies what a client can edit from a VTODO to a task.
+func applyTodo(task *Task, properties []icalProperty) {
+	task.Description = ""
+	task.Deadline = 0
+	status := ""
+	var completedAt int64
+	priority := "none"
+	for _, property := range properties {
+		switch property.Name {
+		case "UID":
+			if task.Id.IsZero() {
+				task.IcalUid = property.Value
+			}
+		case "SUMMARY":
+			if name := strings.TrimSpace(property.text()); name != "" {
+				task.Name = name
+			}
+		case "DESCRIPTION":
+			task.Description = property.text()
+		case "DUE":
+			task.Deadline, _ = property.time()
+		case "PRIORITY":
+			var value int
+			if _, err := fmt.Sscan(property.Value, &value); err == nil && value >= 0 && value < len(caldavPriorities) {
+				priority = caldavPriorities[value]
+			}
+		case "STATUS":
+			status = strings.ToUpper(property.Value)
+		case "COMPLETED":
+			completedAt, _ = property.time()
+		}
+	}
+	setTaskPriority(task, priority)
+	completed := completedAt != 0
+	if status != "" {
+		completed = status == "COMPLETED"
+	}
+	if !completed {
+		task.CompletedAt = 0
+	} else if task.CompletedAt == 0 {
+		task.CompletedAt = completedAt
+		if task.CompletedAt == 0 {
+			task.CompletedAt = time.Now().UTC().Unix()
+		}
+	}
+	if task.Name == "" {
+		task.Name = "Untitled task"
+	}
+}
+
+// caldavPut creates or updates the task of a resource, with the side effects of the task endpoints.
+func caldavPut(c *gin.Context, userId bson.ObjectID, segments []string) {
+	if len(segments) != 3 || segments[0] != "calendars" {
+		c.AbortWithStatus(405)
+		return
+	}
+	board, workspace, err := caldavBoard(segments[1], userId)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatus(404)
+		return
+	} else if err != nil {
+		c.AbortWithStatus(500)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxCaldavBody+1))
+	if err != nil {
+		c.AbortWithStatus(400)
+		return
+	} else if len(body) > maxCaldavBody {
+		c.AbortWithStatus(413)
+		return
+	}
+	properties, ok := parseIcalComponent(string(body), "VTODO")
+	if !ok {
+		caldavError(c, 403, "<cal:supported-calendar-component/>")
+		return
+	}
+
+	task, err := caldavFindTask(board, segments[2])
+	exists := err == nil
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatus(500)
+		return
+	}
+	ifMatch, ifNoneMatch := c.GetHeader("If-Match"), c.GetHeader("If-None-Match")
+	if (ifNoneMatch == "*" && exists) || (ifMatch != "" && (!exists || (ifMatch != "*" && ifMatch != newCaldavTask(task).etag))) {
+		c.AbortWithStatus(412)
+		return
+	}
+
+	if !exists {
+		task = Task{
+			CreatedAt: time.Now().UTC().Unix(),
+			CreatedBy: workspace.Id,
+			Board:     board.Id,
+			IcalName:  segments[2],
+			// Creators follow their tasks
+			Watchers: []bson.ObjectID{userId},
+		}
+		applyTodo(&task, properties)
+		if task.IcalUid != "" {
+			err := tasksDb.FindOne(context.TODO(), bson.D{{"board", board.Id}, {"ical_uid", task.IcalUid}}).Err()
+			if err == nil {
+				caldavError(c, 409, "<cal:no-uid-conflict/>")
+				return
+			} else if !errors.Is(err, mongo.ErrNoDocuments) {
+				c.AbortWithStatus(500)
+				return
+			}
+		}
+		keyPrefix, err := ensureWorkspaceKey(workspace)
+		if err != nil {
+			c.AbortWithStatus(500)
+			return
+		}
+		if task.Number, err = nextTaskNumber(workspace.Id); err != nil {
+			c.AbortWithStatus(500)
+			return
+		}
+		task.Key = formatTaskKey(keyPrefix, task.Number)
+		result, err := tasksDb.InsertOne(context.TODO(), task)
+		if err != nil {
+			c.AbortWithStatus(500)
+			return
+		}
+		task.Id = result.InsertedID.(bson.ObjectID)
+		notifyMentions(workspace, task, "", userId)
+		notifyWatchers(task, userId, notificationCreated, userName(userId)+" created "+taskTitle(task))
+		publishEvent(Event{Type: eventTaskCreated, Workspace: workspace.Id, Actor: userId, Task: &task})
+		c.AbortWithStatus(201)
+		return
+	}
+
+	previous := task
+	applyTodo(&task, properties)
+	completed := previous.CompletedAt == 0 && task.CompletedAt != 0
+	if completed {
+		// Clients can't force completing a blocked task like the API can
+		blockers, err := unfinishedBlockers(task)
+		if err != nil {
+			c.AbortWithStatus(500)
+			return
+		} else if len(blockers) > 0 {
+			c.AbortWithStatusJSON(409, gin.H{"error": "Task is blocked by unfinished tasks", "blocked_by": blockers})
+			return
+		}
+	}
+	if _, err := tasksDb.ReplaceOne(context.TODO(), bson.D{{"_id", task.Id}}, &task); err != nil {
+		c.AbortWithStatus(500)
+		return
+	}
+	if task.Description != previous.Description {
+		notifyMentions(workspace, task, previous.Description, userId)
+	}
+	if completed {
+		notifyWatchers(task, userId, notificationUpdated, userName(userId)+" completed "+taskTitle(task))
+	} else {
+		notifyWatchers(task, userId, notificationUpdated, userName(userId)+" updated "+taskTitle(task))
+	}
+	publishEvent(Event{Type: eventTaskUpdated, Workspace: task.CreatedBy, Actor: userId, Task: &task})
+	if completed && task.Recurrence != nil {
+		// The scheduler retries on its next run if this fails
+		if err := spawnNextOccurrence(task.Id); err != nil {
+			println("WARNING Failed to create next occurrence of task ", task.Id.Hex(), ": ", err.Error())
+		}
+	}
+	c.AbortWithStatus(204)
+}
+
+func caldavDelete(c *gin.Context, userId bson.ObjectID, segments []string) {
+	if len(segments) != 3 || segments[0] != "calendars" {
+		c.AbortWithStatus(405)
+		return
+	}
+	board, _, err := caldavBoard(segments[1], userId)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatus(404)
+		return
+	} else if err != nil {
+		c.AbortWithStatus(500)
+		return
+	}
+	task, err := caldavFindTask(board, segments[2])
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatus(404)
+		return
+	} else if err != nil {
+		c.AbortWithStatus(500)
+		return
+	}
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" && ifMatch != "*" && ifMatch != newCaldavTask(task).etag {
+		c.AbortWithStatus(412)
+		return
+	}
+	if _, err := tasksDb.DeleteOne(context.TODO(), bson.D{{"_id", task.Id}}); err != nil {
+		c.AbortWithStatus(500)
+		return
+	}
+	if _, err := tasksDb.UpdateMany(context.TODO(), bson.D{{"links.task", task.Id}}, bson.D{{"$pull", bson.D{{"links", bson.D{{"task", task.Id}}}}}}); err != nil {
+		c.AbortWithStatus(500)
+		return
+	}
+	notifyWatchers(task, userId, notificationDeleted, userName(userId)+" deleted "+taskTitle(task))
+	publishEvent(Event{Type: eventTaskDeleted, Workspace: task.CreatedBy, Actor: userId, Task: &task})
+	c.AbortWithStatus(204)
+}
+
+func caldavGet(c *gin.Context, userId bson.ObjectID, segments []string) {
+	if len(segments) != 3 || segments[0] != "calendars" {
+		c.AbortWithStatus(405)
+		return
+	}
+	board, _, err := caldavBoard(segments[1], userId)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatus(404)
+		return
+	} else if err != nil {
+		c.AbortWithStatus(500)
+		return
+	}
+	task, err := caldavFindTask(board, segments[2])
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatus(404)
+		return
+	} else if err != nil {
+		c.AbortWithStatus(500)
+		return
+	}
+	item := newCaldavTask(task)
+	c.Header("ETag", item.etag)
+	if c.GetHeader("If-None-Match") == item.etag {
+		c.AbortWithStatus(304)
+		return
+	}
+	c.Data(200, caldavContentType, []byte(item.data))
+}
+
+// @Summary 		CalDAV
+// @Description 	Serves every board you can access as a CalDAV calendar of VTODO items, at /caldav/calendars/{boardId}/. Creating, completing, renaming, re-dating or deleting a todo in the client changes the task. Supports OPTIONS, PROPFIND, REPORT (calendar-query and calendar-multiget), GET, PUT and DELETE with ETags. Sign in with your email and an app password, or the app password as bearer token.
+// @Router 			/caldav/{path} [get]
+// @Tags 			Feeds
+// @Param 			path path string false "Resource path"
+// @Success 		200 {string} string "The requested resource"
+// @Failure 		401 {object} ErrorSwagger "An app password is required"
+// @Failure 		404 "Not Found"
+func serveCaldav(c *gin.Context) {
+	id, _ := c.Get("id")
+	userId := id.(bson.ObjectID)
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(c.Param("path"), "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	c.Header("DAV", "1, 3, calendar-access")
+
+	switch c.Request.Method {
+	case "OPTIONS":
+		c.Header("Allow", "OPTIONS, PROPFIND, REPORT, GET, HEAD, PUT, DELETE")
+		c.AbortWithStatus(200)
+	case "PROPFIND", "REPORT":
+		var request davRequest
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxCaldavBody))
+		if err != nil {
+			c.AbortWithStatus(400)
+			return
+		}
+		if len(bytes.TrimSpace(body)) != 0 {
+			if err := xml.Unmarshal(body, &request); err != nil {
+				c.AbortWithStatus(400)
+				return
+			}
+		}
+		if request.Prop != nil && request.XMLName.Local == "propfind" && len(request.Prop.Names) == 0 {
+			request.Prop = nil
+		}
+		if c.Request.Method == "PROPFIND" {
+			caldavPropfind(c, userId, segments, request)
+		} else {
+			caldavReport(c, userId, segments, request)
+		}
+	case "GET", "HEAD":
+		caldavGet(c, userId, segments)
+	case "PUT":
+		caldavPut(c, userId, segments)
+	case "DELETE":
+		caldavDelete(c, userId, segments)
+	default:
+		c.AbortWithStatus(405)
+	}
+}
+
+// CalDAV clients use WebDAV methods besides the usual ones
+var caldavMethods = []string{"OPTIONS", "PROPFIND", "REPORT", "GET", "HEAD", "PUT", "DELETE"}
+
+// redirectCaldav points clients looking up the well-known URL to the CalDAV root.
+func redirectCaldav(c *gin.Context) {
+	c.Redirect(301, caldavRoot)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestParseIcalComponent(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:abc@example.com\r\n" +
+		"SUMMARY:Write the\r\n  report\r\n" +
+		"DUE;TZID=\"Europe/Berlin\":20260310T090000\r\n" +
+		"X-NOTE;ALTREP=\"cid:a;b\":value:with:colons\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"TRIGGER:-PT15M\r\n" +
+		"END:VALARM\r\n" +
+		"status:completed\r\n" +
+		"END:VTODO\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:second\r\n" +
+		"END:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+	got, ok := parseIcalComponent(data, "vtodo")
+	if !ok {
+		t.Fatal("VTODO not found")
+	}
+	want := []icalProperty{
+		{Name: "UID", Params: map[string]string{}, Value: "abc@example.com"},
+		{Name: "SUMMARY", Params: map[string]string{}, Value: "Write the report"},
+		{Name: "DUE", Params: map[string]string{"TZID": `"Europe/Berlin"`}, Value: "20260310T090000"},
+		{Name: "X-NOTE", Params: map[string]string{"ALTREP": `"cid:a;b"`}, Value: "value:with:colons"},
+		{Name: "STATUS", Params: map[string]string{}, Value: "completed"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseIcalComponent = %+v, want %+v", got, want)
+	}
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"missing component", "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:a\nEND:VEVENT\nEND:VCALENDAR\n"},
+		{"unterminated component", "BEGIN:VCALENDAR\nBEGIN:VTODO\nUID:a\n"},
+		{"empty", ""},
+	}
+	for _, test := range tests {
+		if _, ok := parseIcalComponent(test.data, "VTODO"); ok {
+			t.Errorf("%s: parseIcalComponent found a VTODO", test.name)
+		}
+	}
+}
+
+func TestApplyTodo(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no time zone database")
+	}
+	completedAt := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC).Unix()
+	existing := func() Task {
+		return Task{Id: bson.NewObjectID(), Name: "Old name", Description: "Old description", Deadline: 1, Priority: "high", PriorityRank: priorityRanks["high"]}
+	}
+	property := func(name string, value string) icalProperty {
+		return icalProperty{Name: name, Params: map[string]string{}, Value: value}
+	}
+	tests := []struct {
+		name       string
+		task       Task
+		properties []icalProperty
+		check      func(Task) bool
+	}{
+		{"summary and escaped description", existing(), []icalProperty{property("SUMMARY", "New name"), property("DESCRIPTION", `a\, b\nc`)},
+			func(task Task) bool { return task.Name == "New name" && task.Description == "a, b\nc" }},
+		{"blank summary keeps the name", existing(), []icalProperty{property("SUMMARY", "  ")},
+			func(task Task) bool { return task.Name == "Old name" }},
+		{"removed properties are cleared", existing(), []icalProperty{},
+			func(task Task) bool { return task.Description == "" && task.Deadline == 0 && task.Priority == "none" }},
+		{"new task without summary", Task{}, []icalProperty{property("UID", "client-uid")},
+			func(task Task) bool { return task.Name == "Untitled task" && task.IcalUid == "client-uid" }},
+		{"existing tasks keep their UID", existing(), []icalProperty{property("UID", "client-uid")},
+			func(task Task) bool { return task.IcalUid == "" }},
+		{"due in UTC", existing(), []icalProperty{property("DUE", "20260310T090000Z")},
+			func(task Task) bool { return task.Deadline == time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC).Unix() }},
+		{"due with a time zone", existing(), []icalProperty{{Name: "DUE", Params: map[string]string{"TZID": "Europe/Berlin"}, Value: "20260310T090000"}},
+			func(task Task) bool { return task.Deadline == time.Date(2026, 3, 10, 9, 0, 0, 0, berlin).Unix() }},
+		{"due date", existing(), []icalProperty{property("DUE", "20260310")},
+			func(task Task) bool { return task.Deadline == time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC).Unix() }},
+		{"priority", existing(), []icalProperty{property("PRIORITY", "1")},
+			func(task Task) bool { return task.Priority == "urgent" && task.PriorityRank == priorityRanks["urgent"] }},
+		{"invalid priority", existing(), []icalProperty{property("PRIORITY", "12")},
+			func(task Task) bool { return task.Priority == "none" }},
+		{"completed with a time", existing(), []icalProperty{property("STATUS", "COMPLETED"), property("COMPLETED", "20260301T080000Z")},
+			func(task Task) bool { return task.CompletedAt == completedAt }},
+		{"completed without a time", existing(), []icalProperty{property("STATUS", "completed")},
+			func(task Task) bool { return task.CompletedAt > completedAt }},
+		{"completion time alone completes", existing(), []icalProperty{property("COMPLETED", "20260301T080000Z")},
+			func(task Task) bool { return task.CompletedAt == completedAt }},
+		{"already completed keeps its time", Task{CompletedAt: 42}, []icalProperty{property("STATUS", "COMPLETED"), property("COMPLETED", "20260301T080000Z")},
+			func(task Task) bool { return task.CompletedAt == 42 }},
+		{"reopened", Task{CompletedAt: 42}, []icalProperty{property("STATUS", "NEEDS-ACTION"), property("COMPLETED", "20260301T080000Z")},
+			func(task Task) bool { return task.CompletedAt == 0 }},
+	}
+	for _, test := range tests {
+		task := test.task
+		applyTodo(&task, test.properties)
+		if !test.check(task) {
+			t.Errorf("%s: got %+v", test.name, task)
+		}
+	}
+}
@@ -0,0 +1,850 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	maxCsvImportRows = 5000
+	// Custom fields are exported as "cf:<field name>" columns
+	csvCustomFieldPrefix = "cf:"
+	// Separates values of list columns like labels and assignees
+	csvListSeparator = ";"
+	csvFlushEvery    = 100
+	// Spreadsheet apps evaluate cells starting with one of these as formulas
+	csvFormulaPrefixes = "=+-@\t\r"
+)
+
+var csvColumns = []string{
+	"key", "id", "name", "description", "board", "board_id", "status", "priority", "deadline",
+	"created_at", "completed_at", "labels", "assignees", "watchers", "estimate", "estimate_unit",
+	"links", "recurrence", "external_id",
+}
+
+// Columns that are exported for reference but can not be set by an import.
+var csvReadOnlyColumns = []string{"key", "id", "created_at", "watchers", "links", "recurrence", "external_id"}
+
+// Common spreadsheet headers that mean one of the task columns.
+var csvColumnAliases = map[string]string{
+	"title":    "name",
+	"summary":  "name",
+	"due":      "deadline",
+	"due_date": "deadline",
+	"label":    "labels",
+	"assignee": "assignees",
+	"state":    "status",
+}
+
+var csvFilenameRegex = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// workspaceUsers loads the owner and members of a workspace.
+func workspaceUsers(workspace Workspace) ([]User, error) {
+	cursor, err := usersDb.Find(context.TODO(),
+		bson.D{{"_id", bson.D{{"$in", append([]bson.ObjectID{workspace.OwnedBy}, workspace.Members...)}}}},
+		options.Find().SetProjection(bson.D{{"_id", 1}, {"name", 1}, {"email", 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]User, 0)
+	if err := cursor.All(context.TODO(), &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func workspaceBoards(workspaceId bson.ObjectID) ([]Board, error) {
+	cursor, err := boardsDb.Find(context.TODO(), bson.D{{"owned_by", workspaceId}})
+	if err != nil {
+		return nil, err
+	}
+	boards := make([]Board, 0)
+	if err := cursor.All(context.TODO(), &boards); err != nil {
+		return nil, err
+	}
+	return boards, nil
+}
+
+func workspaceLabels(workspaceId bson.ObjectID) ([]Label, error) {
+	cursor, err := labelsDb.Find(context.TODO(), bson.D{{"workspace", workspaceId}})
+	if err != nil {
+		return nil, err
+	}
+	labels := make([]Label, 0)
+	if err := cursor.All(context.TODO(), &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+func formatCsvTime(timestamp int64) string {
+	if timestamp == 0 {
+		return ""
+	}
+	return time.Unix(timestamp, 0).UTC().Format(time.RFC3339)
+}
+
+// parseCsvTime accepts RFC 3339, dates with or without a time of day in UTC and unix timestamps.
+func parseCsvTime(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return timestamp, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.Unix(), nil
+		}
+	}
+	return 0, fmt.Errorf("'%s' is not a date", value)
+}
+
+func splitCsvList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, csvListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// csvExporter turns tasks into CSV rows, with ids resolved to names, emails and keys.
+type csvExporter struct {
+	boards map[bson.ObjectID]string
+	labels map[bson.ObjectID]string
+	emails map[bson.ObjectID]string
+	keys   map[bson.ObjectID]string
+	fields []CustomField
+}
+
+func newCsvExporter(workspace Workspace) (*csvExporter, error) {
+	exporter := &csvExporter{
+		boards: map[bson.ObjectID]string{},
+		labels: map[bson.ObjectID]string{},
+		emails: map[bson.ObjectID]string{},
+		keys:   map[bson.ObjectID]string{},
+	}
+	boards, err := workspaceBoards(workspace.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, board := range boards {
+		exporter.boards[board.Id] = board.Name
+	}
+	labels, err := workspaceLabels(workspace.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, label := range labels {
+		exporter.labels[label.Id] = label.Name
+	}
+	users, err := workspaceUsers(workspace)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		exporter.emails[user.Id] = user.Email
+	}
+	fields, err := loadCustomFields(workspace.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		exporter.fields = append(exporter.fields, field)
+	}
+	slices.SortFunc(exporter.fields, func(a, b CustomField) int { return strings.Compare(a.Name, b.Name) })
+	// Links point to tasks of the whole workspace, even when only one board is exported
+	cursor, err := tasksDb.Find(context.TODO(), bson.D{{"created_by", workspace.Id}}, options.Find().SetProjection(bson.D{{"_id", 1}, {"key", 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+	for cursor.Next(context.TODO()) {
+		var task Task
+		if err := cursor.Decode(&task); err == nil && task.Key != "" {
+			exporter.keys[task.Id] = task.Key
+		}
+	}
+	return exporter, cursor.Err()
+}
+
+func (e *csvExporter) header() []string {
+	header := slices.Clone(csvColumns)
+	for _, field := range e.fields {
+		header = append(header, csvCustomFieldPrefix+field.Name)
+	}
+	return header
+}
+
+// email falls back to the id for users that are no longer part of the workspace.
+func (e *csvExporter) email(userId bson.ObjectID) string {
+	if email, ok := e.emails[userId]; ok {
+		return email
+	}
+	return userId.Hex()
+}
+
+func (e *csvExporter) customFieldValue(field CustomField, value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		if field.Type == fieldDate {
+			return formatCsvTime(v)
+		}
+		return strconv.FormatInt(v, 10)
+	case int32:
+		if field.Type == fieldDate {
+			return formatCsvTime(int64(v))
+		}
+		return strconv.FormatInt(int64(v), 10)
+	case bson.ObjectID:
+		return e.email(v)
+	case bson.A:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, csvListSeparator+" ")
+	}
+	return fmt.Sprint(value)
+}
+
+func (e *csvExporter) row(task Task) []string {
+	status := "open"
+	if task.CompletedAt != 0 {
+		status = "done"
+	}
+	labels := make([]string, 0, len(task.Labels))
+	for _, labelId := range task.Labels {
+		if name, ok := e.labels[labelId]; ok {
+			labels = append(labels, name)
+		}
+	}
+	assignees := make([]string, 0, len(task.Assignees))
+	for _, userId := range task.Assignees {
+		assignees = append(assignees, e.email(userId))
+	}
+	watchers := make([]string, 0, len(task.Watchers))
+	for _, userId := range task.Watchers {
+		watchers = append(watchers, e.email(userId))
+	}
+	links := make([]string, 0, len(task.Links))
+	for _, link := range task.Links {
+		target, ok := e.keys[link.Task]
+		if !ok {
+			target = link.Task.Hex()
+		}
+		links = append(links, link.Type+" "+target)
+	}
+	estimate := ""
+	if task.Estimate.Unit != "" {
+		estimate = strconv.FormatFloat(task.Estimate.Value, 'f', -1, 64)
+	}
+	recurrence := ""
+	if task.Recurrence != nil {
+		recurrence = task.Recurrence.Rule
+	}
+	row := []string{
+		task.Key,
+		task.Id.Hex(),
+		task.Name,
+		task.Description,
+		e.boards[task.Board],
+		task.Board.Hex(),
+		status,
+		task.Priority,
+		formatCsvTime(task.Deadline),
+		formatCsvTime(task.CreatedAt),
+		formatCsvTime(task.CompletedAt),
+		strings.Join(labels, csvListSeparator+" "),
+		strings.Join(assignees, csvListSeparator+" "),
+		strings.Join(watchers, csvListSeparator+" "),
+		estimate,
+		task.Estimate.Unit,
+		strings.Join(links, csvListSeparator+" "),
+		recurrence,
+		task.ExternalId,
+	}
+	for _, field := range e.fields {
+		row = append(row, e.customFieldValue(field, task.CustomFields[field.Id.Hex()]))
+	}
+	for i := range row {
+		row[i] = escapeCsvCell(row[i])
+	}
+	return row
+}
+
+// escapeCsvCell prefixes cells that would be read as a formula with a quote, so opening an export
+// in a spreadsheet never runs text that members typed into tasks.
+func escapeCsvCell(cell string) string {
+	if cell != "" && strings.ContainsRune(csvFormulaPrefixes, rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// unescapeCsvCell reverses escapeCsvCell, so exported files import unchanged.
+func unescapeCsvCell(cell string) string {
+	if len(cell) > 1 && cell[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes, rune(cell[1])) {
+		return cell[1:]
+	}
+	return cell
+}
+
+// streamTasksCsv writes the tasks matching the filter as CSV while reading them from MongoDB,
+// so large workspaces are never held in memory.
+func streamTasksCsv(c *gin.Context, workspace Workspace, filter bson.D, filename string) {
+	exporter, err := newCsvExporter(workspace)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	cursor, err := tasksDb.Find(context.TODO(), filter, options.Find().SetSort(bson.D{{"number", 1}, {"_id", 1}}))
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	defer cursor.Close(context.TODO())
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, csvFilenameRegex.ReplaceAllString(filename, "_")))
+	c.Status(200)
+	writer := csv.NewWriter(c.Writer)
+	if err := writer.Write(exporter.header()); err != nil {
+		return
+	}
+	for rows := 1; cursor.Next(context.TODO()); rows++ {
+		var task Task
+		if err := cursor.Decode(&task); err != nil {
+			println("WARNING Failed to decode task for CSV export: ", err.Error())
+			continue
+		}
+		if err := writer.Write(exporter.row(task)); err != nil {
+			return
+		}
+		if rows%csvFlushEvery == 0 {
+			writer.Flush()
+			c.Writer.Flush()
+		}
+	}
+	writer.Flush()
+	if err := cursor.Err(); err != nil {
+		// The status is already sent, the truncated file is all we can do
+		println("WARNING CSV export ended early: ", err.Error())
+	}
+}
+
+// @Summary 		Export a board as CSV
+// @Description 	Streams all tasks of a board with all their fields. List columns like labels and assignees are separated by semicolons, custom fields are cf:<name> columns. Cells starting with =, +, -, @, a tab or a carriage return get a leading quote so spreadsheets do not run them as formulas, imports remove it again.
+// @Router 			/workspaces/{workspaceId}/boards/{boardId}/export.csv [get]
+// @Tags 			Import and export
+// @Security 		BearerAuth
+// @Produce 		text/csv
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			boardId path string true "Board ID"
+// @Success 		200 {string} string "CSV file"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid board id"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - board or workspace not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func exportBoardCsv(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	boardId, err := bson.ObjectIDFromHex(c.Param("boardId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid boardId"})
+		return
+	}
+	var board Board
+	err = boardsDb.FindOne(context.TODO(), bson.D{{"_id", boardId}, {"owned_by", workspace.Id}}).Decode(&board)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Board does not exist"})
+		return
+	} else if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	streamTasksCsv(c, workspace, bson.D{{"created_by", workspace.Id}, {"board", board.Id}}, workspace.Name+"-"+board.Name)
+}
+
+// @Summary 		Export a workspace as CSV
+// @Description 	Streams all tasks of all boards of a workspace, in the same format as the board export.
+// @Router 			/workspaces/{workspaceId}/export.csv [get]
+// @Tags 			Import and export
+// @Security 		BearerAuth
+// @Produce 		text/csv
+// @Param 			workspaceId path string true "Workspace ID"
+// @Success 		200 {string} string "CSV file"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - workspace not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func exportWorkspaceCsv(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	streamTasksCsv(c, workspace, bson.D{{"created_by", workspace.Id}}, workspace.Name)
+}
+
+// csvImport holds what rows are resolved against: the boards, labels, members and custom fields of the workspace.
+type csvImport struct {
+	workspace    Workspace
+	actor        bson.ObjectID
+	columns      []string // target field of every column, "" for ignored ones
+	boards       map[string]Board
+	boardsById   map[bson.ObjectID]Board
+	labels       map[string]bson.ObjectID
+	users        map[string]bson.ObjectID
+	fields       map[string]CustomField
+	createBoards bool
+	defaultBoard bson.ObjectID
+}
+
+// csvImportRow is a validated row. Tasks on boards that do not exist yet only carry the board name.
+type csvImportRow struct {
+	Task      Task
+	NewBoard  string
+	Line      int
+	Assignees []bson.ObjectID
+}
+
+func newCsvImport(workspace Workspace, actor bson.ObjectID) (*csvImport, error) {
+	imp := &csvImport{
+		workspace:  workspace,
+		actor:      actor,
+		boards:     map[string]Board{},
+		boardsById: map[bson.ObjectID]Board{},
+		labels:     map[string]bson.ObjectID{},
+		users:      map[string]bson.ObjectID{},
+		fields:     map[string]CustomField{},
+	}
+	boards, err := workspaceBoards(workspace.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, board := range boards {
+		imp.boards[strings.ToLower(board.Name)] = board
+		imp.boardsById[board.Id] = board
+	}
+	labels, err := workspaceLabels(workspace.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, label := range labels {
+		imp.labels[strings.ToLower(label.Name)] = label.Id
+	}
+	users, err := workspaceUsers(workspace)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		imp.users[strings.ToLower(user.Email)] = user.Id
+	}
+	fields, err := loadCustomFields(workspace.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		imp.fields[strings.ToLower(field.Name)] = field
+	}
+	return imp, nil
+}
+
+// targetColumn maps a CSV header to the task field it is imported into. Known column names and their
+// aliases are matched case-insensitively, as are custom field names with or without the cf: prefix.
+func (imp *csvImport) targetColumn(header string) (string, bool) {
+	name := strings.ToLower(strings.TrimSpace(header))
+	if strings.HasPrefix(name, csvCustomFieldPrefix) {
+		_, ok := imp.fields[strings.TrimPrefix(name, csvCustomFieldPrefix)]
+		return name, ok
+	}
+	name = strings.ReplaceAll(name, " ", "_")
+	if alias, ok := csvColumnAliases[name]; ok {
+		name = alias
+	}
+	if slices.Contains(csvColumns, name) {
+		return name, !slices.Contains(csvReadOnlyColumns, name)
+	}
+	if _, ok := imp.fields[strings.ToLower(strings.TrimSpace(header))]; ok {
+		return csvCustomFieldPrefix + strings.ToLower(strings.TrimSpace(header)), true
+	}
+	return "", false
+}
+
+// mapColumns resolves the header, applying the explicit mapping from column name to field first.
+// It returns the columns that are not imported.
+func (imp *csvImport) mapColumns(header []string, mapping map[string]string) ([]string, error) {
+	ignored := make([]string, 0)
+	imp.columns = make([]string, len(header))
+	for i, column := range header {
+		target, mapped := mapping[column]
+		if mapped && target == "" {
+			ignored = append(ignored, column)
+			continue
+		}
+		if !mapped {
+			target = column
+		}
+		field, ok := imp.targetColumn(target)
+		if !ok {
+			if mapped {
+				return nil, fmt.Errorf("Column '%s' can not be mapped to '%s'", column, target)
+			}
+			ignored = append(ignored, column)
+			continue
+		}
+		if slices.Contains(imp.columns, field) {
+			return nil, fmt.Errorf("More than one column maps to '%s'", field)
+		}
+		imp.columns[i] = field
+	}
+	if !slices.Contains(imp.columns, "name") {
+		return nil, errors.New("No column maps to 'name'")
+	}
+	return ignored, nil
+}
+
+// customFieldCell converts a cell to the JSON representation normalizeCustomFieldValue validates.
+func (imp *csvImport) customFieldCell(field CustomField, value string) (any, error) {
+	switch field.Type {
+	case fieldNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number", value)
+		}
+		return number, nil
+	case fieldDate:
+		timestamp, err := parseCsvTime(value)
+		if err != nil {
+			return nil, err
+		}
+		return float64(timestamp), nil
+	case fieldMultiSelect:
+		options := make([]any, 0)
+		for _, option := range splitCsvList(value) {
+			options = append(options, option)
+		}
+		return options, nil
+	case fieldUser:
+		userId, ok := imp.users[strings.ToLower(value)]
+		if !ok {
+			return nil, fmt.Errorf("'%s' is not a member of this workspace", value)
+		}
+		return userId.Hex(), nil
+	}
+	return value, nil
+}
+
+// parseRow validates one row and builds its task. All problems of the row are reported, not just the first.
+func (imp *csvImport) parseRow(record []string, line int, now int64) (csvImportRow, []CsvRowError) {
+	row := csvImportRow{Line: line, Task: Task{CreatedBy: imp.workspace.Id, CreatedAt: now}}
+	task := &row.Task
+	rowErrors := make([]CsvRowError, 0)
+	fail := func(column string, format string, args ...any) {
+		rowErrors = append(rowErrors, CsvRowError{Row: line, Column: column, Error: fmt.Sprintf(format, args...)})
+	}
+	cells := map[string]string{}
+	for i, column := range imp.columns {
+		if column != "" && i < len(record) {
+			cells[column] = unescapeCsvCell(strings.TrimSpace(record[i]))
+		}
+	}
+
+	task.Name = cells["name"]
+	if task.Name == "" {
+		fail("name", "Name is required")
+	}
+	if i := slices.Index(imp.columns, "description"); i != -1 && i < len(record) {
+		// Descriptions keep their whitespace
+		task.Description = unescapeCsvCell(record[i])
+	}
+
+	switch {
+	case cells["board_id"] != "":
+		boardId, err := bson.ObjectIDFromHex(cells["board_id"])
+		if _, ok := imp.boardsById[boardId]; err != nil || !ok {
+			fail("board_id", "Board %s does not exist in this workspace", cells["board_id"])
+		}
+		task.Board = boardId
+	case cells["board"] != "":
+		if board, ok := imp.boards[strings.ToLower(cells["board"])]; ok {
+			task.Board = board.Id
+		} else if imp.createBoards {
+			row.NewBoard = cells["board"]
+		} else {
+			fail("board", "Board '%s' does not exist", cells["board"])
+		}
+	case !imp.defaultBoard.IsZero():
+		task.Board = imp.defaultBoard
+	default:
+		fail("board", "No board given")
+	}
+
+	if !setTaskPriority(task, strings.ToLower(cells["priority"])) {
+		fail("priority", "Priority must be one of urgent, high, medium, low, none")
+	}
+	var err error
+	if task.Deadline, err = parseCsvTime(cells["deadline"]); err != nil {
+		fail("deadline", "%s", err.Error())
+	}
+	if task.CompletedAt, err = parseCsvTime(cells["completed_at"]); err != nil {
+		fail("completed_at", "%s", err.Error())
+	}
+	switch strings.ToLower(cells["status"]) {
+	case "", "open", "todo", "to do":
+	case "done", "completed", "closed", "resolved":
+		if task.CompletedAt == 0 {
+			task.CompletedAt = now
+		}
+	default:
+		fail("status", "Status must be open or done")
+	}
+
+	for _, name := range splitCsvList(cells["labels"]) {
+		labelId, ok := imp.labels[strings.ToLower(name)]
+		if !ok {
+			fail("labels", "Unknown label '%s'", name)
+		} else if !slices.Contains(task.Labels, labelId) {
+			task.Labels = append(task.Labels, labelId)
+		}
+	}
+	for _, email := range splitCsvList(cells["assignees"]) {
+		userId, ok := imp.users[strings.ToLower(email)]
+		if !ok {
+			fail("assignees", "'%s' is not a member of this workspace", email)
+		} else if !slices.Contains(row.Assignees, userId) {
+			row.Assignees = append(row.Assignees, userId)
+		}
+	}
+
+	if cells["estimate"] != "" || cells["estimate_unit"] != "" {
+		task.Estimate.Unit = strings.ToLower(cells["estimate_unit"])
+		if task.Estimate.Unit == "" {
+			task.Estimate.Unit = estimatePoints
+		}
+		value, err := strconv.ParseFloat(cells["estimate"], 64)
+		if err != nil || !validateEstimate(TaskEstimate{Unit: task.Estimate.Unit, Value: value}) {
+			fail("estimate", "Estimate must be a non-negative number of points or whole minutes")
+		}
+		task.Estimate.Value = value
+	}
+
+	values := map[string]any{}
+	for column, cell := range cells {
+		if !strings.HasPrefix(column, csvCustomFieldPrefix) || cell == "" {
+			continue
+		}
+		field := imp.fields[strings.TrimPrefix(column, csvCustomFieldPrefix)]
+		value, err := imp.customFieldCell(field, cell)
+		if err != nil {
+			fail(column, "%s", err.Error())
+			continue
+		}
+		normalized, err := normalizeCustomFieldValue(field, value, imp.workspace.Members)
+		if err != nil {
+			fail(column, "%s", strings.TrimPrefix(err.Error(), errInvalidCustomField.Error()+": "))
+			continue
+		}
+		values[field.Id.Hex()] = normalized
+	}
+	if len(values) != 0 {
+		task.CustomFields = values
+	}
+
+	task.Assignees = row.Assignees
+	task.Watchers = importedTaskWatchers(imp.actor, row.Assignees)
+	return row, rowErrors
+}
+
+// commit creates the missing boards and inserts the tasks of all valid rows.
+func (imp *csvImport) commit(rows []csvImportRow) ([]string, error) {
+	createdBoards := make([]string, 0)
+	for i := range rows {
+		if rows[i].NewBoard == "" {
+			continue
+		}
+		board, ok := imp.boards[strings.ToLower(rows[i].NewBoard)]
+		if !ok {
+			board = Board{Name: rows[i].NewBoard, OwnedBy: imp.workspace.Id}
+			result, err := boardsDb.InsertOne(context.TODO(), board)
+			if err != nil {
+				return createdBoards, err
+			}
+			board.Id = result.InsertedID.(bson.ObjectID)
+			imp.boards[strings.ToLower(board.Name)] = board
+			createdBoards = append(createdBoards, board.Name)
+			publishEvent(Event{Type: eventBoardCreated, Workspace: imp.workspace.Id, BoardId: board.Id, Actor: imp.actor, Board: &board})
+		}
+		rows[i].Task.Board = board.Id
+	}
+	if len(rows) == 0 {
+		return createdBoards, nil
+	}
+
+	keyPrefix, err := ensureWorkspaceKey(imp.workspace)
+	if err != nil {
+		return createdBoards, err
+	}
+	first, err := reserveTaskNumbers(imp.workspace.Id, int64(len(rows)))
+	if err != nil {
+		return createdBoards, err
+	}
+	documents := make([]any, 0, len(rows))
+	for i := range rows {
+		rows[i].Task.Id = bson.NewObjectID()
+		rows[i].Task.Number = first + int64(i)
+		rows[i].Task.Key = formatTaskKey(keyPrefix, rows[i].Task.Number)
+		documents = append(documents, rows[i].Task)
+	}
+	if _, err := tasksDb.InsertMany(context.TODO(), documents); err != nil {
+		return createdBoards, err
+	}
+	for i := range rows {
+		publishEvent(Event{Type: eventTaskCreated, Workspace: imp.workspace.Id, Actor: imp.actor, Task: &rows[i].Task})
+	}
+	return createdBoards, nil
+}
+
+// @Summary 		Import tasks from CSV
+// @Description 	Creates a task for every valid row of a CSV file. Columns are matched to task fields by name, as in the CSV export, or through an explicit mapping. Invalid rows are skipped and reported with the reason. With dry_run nothing is written and the report shows what an import would do.
+// @Router 			/workspaces/{workspaceId}/import.csv [post]
+// @Tags 			Import and export
+// @Security 		BearerAuth
+// @Accept 			multipart/form-data
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			file formData file true "CSV file, the first row is the header"
+// @Param 			mapping formData string false "JSON object from column name to task field like name, board, deadline or cf:<field name>, an empty field ignores the column"
+// @Param 			dry_run query bool false "Only validate and report, do not import"
+// @Param 			create_boards query bool false "Create boards named in the board column that do not exist"
+// @Param 			board query string false "Board for rows without a board column"
+// @Success 		200 {object} CsvImportReport "What was or would be imported and the errors of every invalid row"
+// @Failure 		400 {object} ErrorSwagger "Bad request - missing file, invalid mapping or malformed CSV"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - workspace not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func importTasksCsv(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'file' is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return
+	}
+	defer file.Close()
+	mapping := map[string]string{}
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Field 'mapping' must be a JSON object of column names to fields"})
+			return
+		}
+	}
+
+	imp, err := newCsvImport(workspace, userId.(bson.ObjectID))
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	imp.createBoards = c.Query("create_boards") == "true"
+	if b := c.Query("board"); b != "" {
+		boardId, err := bson.ObjectIDFromHex(b)
+		if _, ok := imp.boardsById[boardId]; err != nil || !ok {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Board does not exist"})
+			return
+		}
+		imp.defaultBoard = boardId
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "The file has no header row"})
+		return
+	}
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	ignored, err := imp.mapColumns(header, mapping)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	report := CsvImportReport{DryRun: c.Query("dry_run") == "true", IgnoredColumns: ignored, CreatedBoards: make([]string, 0), Errors: make([]CsvRowError, 0)}
+	valid := make([]csvImportRow, 0)
+	now := time.Now().UTC().Unix()
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Malformed CSV: " + err.Error()})
+			return
+		}
+		if !slices.ContainsFunc(record, func(cell string) bool { return strings.TrimSpace(cell) != "" }) {
+			continue
+		}
+		report.Rows++
+		if report.Rows > maxCsvImportRows {
+			c.AbortWithStatusJSON(400, gin.H{"error": fmt.Sprintf("At most %d rows can be imported at once", maxCsvImportRows)})
+			return
+		}
+		line, _ := reader.FieldPos(0)
+		row, rowErrors := imp.parseRow(record, line, now)
+		if len(rowErrors) != 0 {
+			report.Errors = append(report.Errors, rowErrors...)
+			report.Failed++
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	if report.DryRun {
+		for _, row := range valid {
+			if row.NewBoard != "" && !slices.ContainsFunc(report.CreatedBoards, func(name string) bool { return strings.EqualFold(name, row.NewBoard) }) {
+				report.CreatedBoards = append(report.CreatedBoards, row.NewBoard)
+			}
+		}
+		report.Imported = len(valid)
+		c.JSON(200, report)
+		return
+	}
+	report.CreatedBoards, err = imp.commit(valid)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to import tasks"})
+		return
+	}
+	report.Imported = len(valid)
+	c.JSON(200, report)
+}
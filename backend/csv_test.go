@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestEscapeCsvCell(t *testing.T) {
+	tests := []struct {
+		cell string
+		want string
+	}{
+		{"", ""},
+		{"Fix login", "Fix login"},
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+1", "'+1"},
+		{"-5", "'-5"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tindented", "'\tindented"},
+		{"\rreturn", "'\rreturn"},
+		{"a=b", "a=b"},
+		{"'quoted", "'quoted"},
+	}
+	for _, test := range tests {
+		got := escapeCsvCell(test.cell)
+		if got != test.want {
+			t.Errorf("escapeCsvCell(%q) = %q, want %q", test.cell, got, test.want)
+		}
+		if back := unescapeCsvCell(got); back != test.cell {
+			t.Errorf("unescapeCsvCell(%q) = %q, want %q", got, back, test.cell)
+		}
+	}
+}
+
+func TestCsvRoundTrip(t *testing.T) {
+	board := Board{Id: bson.NewObjectID(), Name: "Backlog"}
+	label := bson.NewObjectID()
+	user := bson.NewObjectID()
+	exporter := &csvExporter{
+		boards: map[bson.ObjectID]string{board.Id: board.Name},
+		labels: map[bson.ObjectID]string{label: "bug"},
+		emails: map[bson.ObjectID]string{user: "ada@example.com"},
+		keys:   map[bson.ObjectID]string{},
+	}
+	deadline := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC).Unix()
+	tasks := []Task{
+		{Name: "=SUM(A1)", Description: "  - first\n  - second\n", Board: board.Id, Priority: "high", Deadline: deadline, Labels: []bson.ObjectID{label}, Assignees: []bson.ObjectID{user}},
+		{Name: "Plain", Description: "@here see\tbelow", Board: board.Id, Priority: "none"},
+		{Name: "-1 day", Description: "+ added ", Board: board.Id, Priority: "none", CompletedAt: deadline},
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write(exporter.header()); err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range tasks {
+		if err := writer.Write(exporter.row(task)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writer.Flush()
+
+	records, err := csv.NewReader(&buffer).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	imp := &csvImport{
+		actor:      user,
+		boards:     map[string]Board{"backlog": board},
+		boardsById: map[bson.ObjectID]Board{board.Id: board},
+		labels:     map[string]bson.ObjectID{"bug": label},
+		users:      map[string]bson.ObjectID{"ada@example.com": user},
+		fields:     map[string]CustomField{},
+	}
+	if _, err := imp.mapColumns(records[0], nil); err != nil {
+		t.Fatal(err)
+	}
+	for i, record := range records[1:] {
+		row, rowErrors := imp.parseRow(record, i+2, 0)
+		if len(rowErrors) != 0 {
+			t.Errorf("row %d: %+v", i+2, rowErrors)
+			continue
+		}
+		got, want := row.Task, tasks[i]
+		if got.Name != want.Name || got.Description != want.Description || got.Board != want.Board || got.Priority != want.Priority ||
+			got.Deadline != want.Deadline || got.CompletedAt != want.CompletedAt ||
+			!slices.Equal(got.Labels, want.Labels) || !slices.Equal(got.Assignees, want.Assignees) {
+			t.Errorf("row %d = %+v, want %+v", i+2, got, want)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	digestImmediate = "immediate"
+	digestHourly    = "hourly"
+	digestDaily     = "daily"
+	digestOff       = "off"
+)
+
+var digestFrequencies = []string{digestImmediate, digestHourly, digestDaily, digestOff}
+
+const (
+	digestTick = time.Minute
+	// Daily digests go out at this hour in the timezone of the user
+	digestDailyHour = 8
+	// Older unread notifications are not mailed, e.g. after turning digests back on
+	digestLookback = 7 * 24 * time.Hour
+	maxDigestItems = 50
+)
+
+// Reminders are mailed as soon as they are due, so digests leave them out.
+var digestSkippedTypes = []string{notificationDeadline, notificationOverdue}
+
+// digestEvents matches notifications with events for a digest between since and until. A
+// notification merges the events of its task, its own type is only that of the latest one.
+func digestEvents(since int64, until int64) bson.E {
+	window := bson.D{{"$gt", since}}
+	if until != 0 {
+		window = append(window, bson.E{"$lt", until})
+	}
+	return bson.E{"events", bson.D{{"$elemMatch", bson.D{{"type", bson.D{{"$nin", digestSkippedTypes}}}, {"created_at", window}}}}}
+}
+
+// publicUrl is where the API is reachable from the outside, used for links in emails.
+var publicUrl = strings.TrimSuffix(os.Getenv("PUBLIC_URL"), "/")
+
+func apiUrl(path string) string {
+	if publicUrl == "" {
+		return "http://localhost:4444/api/v1" + path
+	}
+	return publicUrl + "/api/v1" + path
+}
+
+// webUrl links to a page of the web app, which is served from the same origin as the API.
+func webUrl(path string) string {
+	if publicUrl == "" {
+		return "http://localhost:4444" + path
+	}
+	return publicUrl + path
+}
+
+// taskWebUrl links to a task in the web app by its key, or its id for tasks without one.
+func taskWebUrl(task Task) string {
+	ref := task.Key
+	if ref == "" {
+		ref = task.Id.Hex()
+	}
+	return webUrl("/workspaces/" + task.CreatedBy.Hex() + "/tasks/" + ref)
+}
+
+type digestItem struct {
+	Message string
+	Count   int
+	Time    string
+}
+
+type digestData struct {
+	Name           string
+	Items          []digestItem
+	More           int
+	UnsubscribeUrl string
+}
+
+var digestTextTemplate = texttemplate.Must(texttemplate.New("digest").Parse(`Hi {{.Name}},
+
+Here is what happened in Rela:
+{{range .Items}}
+- {{.Message}}{{if gt .Count 1}} ({{.Count}} updates){{end}}, {{.Time}}{{end}}
+{{if .More}}
+...and {{.More}} more.
+{{end}}
+To stop receiving these emails, open {{.UnsubscribeUrl}}
+`))
+
+var digestHtmlTemplate = htmltemplate.Must(htmltemplate.New("digest").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #222;">
+<p>Hi {{.Name}},</p>
+<p>Here is what happened in Rela:</p>
+<ul>
+{{range .Items}}<li>{{.Message}}{{if gt .Count 1}} <span style="color: #666;">({{.Count}} updates)</span>{{end}} <span style="color: #666;">{{.Time}}</span></li>
+{{end}}</ul>
+{{if .More}}<p>...and {{.More}} more.</p>{{end}}
+<p style="font-size: 12px; color: #666;"><a href="{{.UnsubscribeUrl}}">Unsubscribe</a> from these emails.</p>
+</body>
+</html>
+`))
+
+var unsubscribePageTemplate = htmltemplate.Must(htmltemplate.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #222;">
+{{if .Done}}<p>You will no longer receive notification emails from Rela. You can turn them back on in your settings.</p>
+{{else}}<form method="post"><p>Stop receiving notification emails from Rela?</p><button type="submit">Unsubscribe</button></form>
+{{end}}</body>
+</html>
+`))
+
+// unsubscribeToken signs the user id, so the link in an email works without logging in.
+func unsubscribeToken(userId bson.ObjectID) string {
+	mac := hmac.New(sha256.New, []byte(pepper))
+	mac.Write([]byte("unsubscribe:" + userId.Hex()))
+	return userId.Hex() + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func parseUnsubscribeToken(token string) (bson.ObjectID, bool) {
+	id, _, found := strings.Cut(token, ".")
+	if !found {
+		return bson.ObjectID{}, false
+	}
+	userId, err := bson.ObjectIDFromHex(id)
+	if err != nil || !hmac.Equal([]byte(unsubscribeToken(userId)), []byte(token)) {
+		return bson.ObjectID{}, false
+	}
+	return userId, true
+}
+
+// digestDue reports whether a user should get a digest now, given when the last one was sent.
+func digestDue(user User, now time.Time) bool {
+	last := time.Unix(user.DigestSentAt, 0)
+	switch user.EmailDigest {
+	case "", digestImmediate:
+		return true
+	case digestHourly:
+		return now.Sub(last) >= time.Hour
+	case digestDaily:
+		location := userLocation(user)
+		local := now.In(location)
+		if local.Hour() < digestDailyHour {
+			return false
+		}
+		lastLocal := last.In(location)
+		return lastLocal.YearDay() != local.YearDay() || lastLocal.Year() != local.Year()
+	}
+	return false
+}
+
+// sendDigest mails the unread notifications a user got since the last digest. The digest is claimed
+// by moving digest_sent_at forward, so with several replicas only one of them sends it. If sending
+// fails the previous value is restored, so the next run tries again.
+func sendDigest(user User, now time.Time) error {
+	// Notifications of the current second could still change, they go into the next digest
+	until := now.Unix()
+	since := max(user.DigestSentAt, now.Add(-digestLookback).Unix())
+	filter := bson.D{
+		{"user", user.Id},
+		{"read", false},
+		{"updated_at", bson.D{{"$gt", since}}},
+		digestEvents(since, until),
+	}
+	total, err := notificationsDb.CountDocuments(context.TODO(), filter)
+	if err != nil || total == 0 {
+		return err
+	}
+	claim := bson.D{{"_id", user.Id}, {"digest_sent_at", user.DigestSentAt}}
+	if user.DigestSentAt == 0 {
+		claim = bson.D{{"_id", user.Id}, {"digest_sent_at", bson.D{{"$in", bson.A{0, nil}}}}}
+	}
+	result, err := usersDb.UpdateOne(context.TODO(), claim, bson.D{{"$set", bson.D{{"digest_sent_at", until - 1}}}})
+	if err != nil || result.ModifiedCount == 0 {
+		return err
+	}
+	if err := deliverDigest(user, filter, since, until, total); err != nil {
+		release := bson.D{{"$set", bson.D{{"digest_sent_at", user.DigestSentAt}}}}
+		if _, releaseErr := usersDb.UpdateOne(context.TODO(), bson.D{{"_id", user.Id}, {"digest_sent_at", until - 1}}, release); releaseErr != nil {
+			println("WARNING Failed to release digest of user ", user.Id.Hex(), ": ", releaseErr.Error())
+		}
+		return err
+	}
+	return nil
+}
+
+func deliverDigest(user User, filter bson.D, since int64, until int64, total int64) error {
+	cursor, err := notificationsDb.Find(context.TODO(), filter,
+		options.Find().SetSort(bson.D{{"updated_at", -1}}).SetLimit(maxDigestItems))
+	if err != nil {
+		return err
+	}
+	notifications := make([]Notification, 0)
+	if err := cursor.All(context.TODO(), &notifications); err != nil {
+		return err
+	}
+	location := userLocation(user)
+	data := digestData{
+		Name:           user.Name,
+		Items:          make([]digestItem, 0, len(notifications)),
+		More:           int(total) - len(notifications),
+		UnsubscribeUrl: apiUrl("/users/unsubscribe?token=" + unsubscribeToken(user.Id)),
+	}
+	for i, notification := range notifications {
+		// Only the events of this digest count, reminders merged into the notification were mailed already
+		item := digestItem{}
+		var latest int64
+		for _, event := range notification.Events {
+			if slices.Contains(digestSkippedTypes, event.Type) || event.CreatedAt <= since || event.CreatedAt >= until {
+				continue
+			}
+			item.Count++
+			if event.CreatedAt >= latest {
+				item.Message, latest = event.Message, event.CreatedAt
+			}
+		}
+		item.Time = time.Unix(latest, 0).In(location).Format("Mon, 02 Jan 15:04 MST")
+		notifications[i].Message = item.Message
+		data.Items = append(data.Items, item)
+	}
+	var text, body strings.Builder
+	if err := digestTextTemplate.Execute(&text, data); err != nil {
+		return err
+	}
+	if err := digestHtmlTemplate.Execute(&body, data); err != nil {
+		return err
+	}
+	subject := fmt.Sprintf("%d new notifications in Rela", total)
+	if total == 1 {
+		subject = notifications[0].Message
+	}
+	return mailer.Send(Email{
+		To:      user.Email,
+		Subject: subject,
+		Text:    text.String(),
+		HTML:    body.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + data.UnsubscribeUrl + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
+}
+
+// sendDigests sends the digests that are due to every user with unread notifications.
+func sendDigests(now time.Time) error {
+	var userIds []bson.ObjectID
+	err := notificationsDb.Distinct(context.TODO(), "user",
+		bson.D{{"read", false}, {"updated_at", bson.D{{"$gt", now.Add(-digestLookback).Unix()}}}, digestEvents(now.Add(-digestLookback).Unix(), 0)},
+	).Decode(&userIds)
+	if err != nil || len(userIds) == 0 {
+		return err
+	}
+	cursor, err := usersDb.Find(context.TODO(), bson.D{{"_id", bson.D{{"$in", userIds}}}, {"email_digest", bson.D{{"$ne", digestOff}}}})
+	if err != nil {
+		return err
+	}
+	users := make([]User, 0)
+	if err := cursor.All(context.TODO(), &users); err != nil {
+		return err
+	}
+	for _, user := range users {
+		if !digestDue(user, now) {
+			continue
+		}
+		if err := sendDigest(user, now); err != nil {
+			println("WARNING Failed to send digest to user ", user.Id.Hex(), ": ", err.Error())
+		}
+	}
+	return nil
+}
+
+// runDigestScheduler checks for due digests once a minute. Every replica can run it.
+func runDigestScheduler() {
+	ticker := time.NewTicker(digestTick)
+	defer ticker.Stop()
+	for ; ; <-ticker.C {
+		if err := sendDigests(time.Now().UTC()); err != nil {
+			println("WARNING Failed to send email digests: ", err.Error())
+		}
+	}
+}
+
+// @Summary 		Unsubscribe from email digests
+// @Description 	Target of the unsubscribe link in digest emails, works without logging in. GET shows a confirmation page, POST turns digests off, which is also what mail clients do for one-click unsubscribe.
+// @Router 			/users/unsubscribe [get]
+// @Router 			/users/unsubscribe [post]
+// @Tags 			Users
+// @Produce 		html
+// @Param 			token query string true "Unsubscribe token from the email"
+// @Success 		200 "Digests turned off"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid token"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func unsubscribeDigest(c *gin.Context) {
+	userId, ok := parseUnsubscribeToken(c.Query("token"))
+	if !ok {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Invalid unsubscribe token"})
+		return
+	}
+	// Link scanners of mail providers open every link, so GET only asks for confirmation
+	if c.Request.Method == "POST" {
+		if _, err := usersDb.UpdateOne(context.TODO(), bson.D{{"_id", userId}}, bson.D{{"$set", bson.D{{"email_digest", digestOff}}}}); err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		}
+	}
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(200)
+	if err := unsubscribePageTemplate.Execute(c.Writer, gin.H{"Done": c.Request.Method == "POST"}); err != nil {
+		println("WARNING Failed to render unsubscribe page: ", err.Error())
+	}
+}
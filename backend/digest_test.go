@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type fakeMailer struct {
+	sent []Email
+	err  error
+}
+
+func (m *fakeMailer) Send(email Email) error {
+	m.sent = append(m.sent, email)
+	return m.err
+}
+
+func useFakeMailer(t *testing.T, err error) *fakeMailer {
+	t.Helper()
+	fake := &fakeMailer{err: err}
+	previous := mailer
+	mailer = fake
+	t.Cleanup(func() { mailer = previous })
+	return fake
+}
+
+// useTestDatabase points the collections at a throwaway database. Tests are skipped when
+// MONGO_CREDS is unset or the MongoDB it points at is not reachable.
+func useTestDatabase(t *testing.T) {
+	t.Helper()
+	if mongodbCredentials == "" {
+		t.Skip("MONGO_CREDS is not set")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := dbClient.Ping(ctx, nil); err != nil {
+		t.Skip("MongoDB is not reachable: ", err)
+	}
+	database := dbClient.Database("rela_test_" + bson.NewObjectID().Hex())
+	collections := map[string]**mongo.Collection{
+		"tasks":               &tasksDb,
+		"users":               &usersDb,
+		"boards":              &boardsDb,
+		"workspaces":          &workspacesDb,
+		"labels":              &labelsDb,
+		"custom_fields":       &customFieldsDb,
+		"counters":            &countersDb,
+		"views":               &viewsDb,
+		"notifications":       &notificationsDb,
+		"reminders":           &remindersDb,
+		"events":              &eventsDb,
+		"webhooks":            &webhooksDb,
+		"webhook_deliveries":  &webhookDeliveriesDb,
+		"jobs":                &jobsDb,
+		"feeds":               &feedsDb,
+		"app_passwords":       &appPasswordsDb,
+		"export_templates":    &exportTemplatesDb,
+		"workspace_templates": &workspaceTemplatesDb,
+	}
+	for name, collection := range collections {
+		previous := *collection
+		*collection = database.Collection(name)
+		t.Cleanup(func() { *collection = previous })
+	}
+	t.Cleanup(func() { _ = database.Drop(context.Background()) })
+}
+
+func digestSentAt(t *testing.T, userId bson.ObjectID) int64 {
+	t.Helper()
+	var user User
+	if err := usersDb.FindOne(context.TODO(), bson.D{{"_id", userId}}).Decode(&user); err != nil {
+		t.Fatal(err)
+	}
+	return user.DigestSentAt
+}
+
+func TestDigestDue(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		user User
+		want bool
+	}{
+		{"immediate", User{EmailDigest: digestImmediate, DigestSentAt: now.Unix() - 1}, true},
+		{"default is immediate", User{}, true},
+		{"off", User{EmailDigest: digestOff}, false},
+		{"hourly within the hour", User{EmailDigest: digestHourly, DigestSentAt: now.Add(-59 * time.Minute).Unix()}, false},
+		{"hourly after an hour", User{EmailDigest: digestHourly, DigestSentAt: now.Add(-time.Hour).Unix()}, true},
+		{"daily already sent today", User{EmailDigest: digestDaily, DigestSentAt: now.Add(-3 * time.Hour).Unix()}, false},
+		{"daily sent yesterday", User{EmailDigest: digestDaily, DigestSentAt: now.Add(-24 * time.Hour).Unix()}, true},
+		{"daily before the hour in the user's timezone", User{EmailDigest: digestDaily, Timezone: "America/Los_Angeles"}, false},
+		{"daily after the hour in the user's timezone", User{EmailDigest: digestDaily, Timezone: "Asia/Tokyo"}, true},
+		{"daily sent on the same day last year", User{EmailDigest: digestDaily, DigestSentAt: now.AddDate(-1, 0, 0).Unix()}, true},
+		{"unknown frequency", User{EmailDigest: "weekly"}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := digestDue(test.user, now); got != test.want {
+				t.Errorf("digestDue() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSendDigest(t *testing.T) {
+	useTestDatabase(t)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	event := func(kind string, message string, age time.Duration) NotificationEvent {
+		return NotificationEvent{Type: kind, Message: message, CreatedAt: now.Add(-age).Unix()}
+	}
+	updated := event(notificationUpdated, "WEB-1 was updated", time.Minute)
+	newUser := func(t *testing.T, events ...NotificationEvent) User {
+		t.Helper()
+		user := User{Id: bson.NewObjectID(), Name: "Ada", Email: "ada@example.com", EmailDigest: digestImmediate}
+		if _, err := usersDb.InsertOne(context.TODO(), user); err != nil {
+			t.Fatal(err)
+		}
+		// Like notify, the notification takes type and message of its latest event
+		latest := events[len(events)-1]
+		_, err := notificationsDb.InsertOne(context.TODO(), Notification{
+			User: user.Id, Task: bson.NewObjectID(), Type: latest.Type, Message: latest.Message, Count: len(events), Events: events,
+			CreatedAt: events[0].CreatedAt, UpdatedAt: latest.CreatedAt,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return user
+	}
+
+	t.Run("claims and sends", func(t *testing.T) {
+		fake := useFakeMailer(t, nil)
+		user := newUser(t, updated)
+		if err := sendDigest(user, now); err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.sent) != 1 || fake.sent[0].To != user.Email || fake.sent[0].Subject != "WEB-1 was updated" {
+			t.Fatalf("sent %+v, want one digest to %s", fake.sent, user.Email)
+		}
+		if got := digestSentAt(t, user.Id); got != now.Unix()-1 {
+			t.Errorf("digest_sent_at = %d, want %d", got, now.Unix()-1)
+		}
+	})
+
+	t.Run("skips a digest claimed by another replica", func(t *testing.T) {
+		fake := useFakeMailer(t, nil)
+		user := newUser(t, updated)
+		claimed := now.Unix() - 30
+		if _, err := usersDb.UpdateOne(context.TODO(), bson.D{{"_id", user.Id}}, bson.D{{"$set", bson.D{{"digest_sent_at", claimed}}}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sendDigest(user, now); err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.sent) != 0 {
+			t.Errorf("sent %d emails, want none", len(fake.sent))
+		}
+		if got := digestSentAt(t, user.Id); got != claimed {
+			t.Errorf("digest_sent_at = %d, want %d", got, claimed)
+		}
+	})
+
+	t.Run("leaves reminders out", func(t *testing.T) {
+		fake := useFakeMailer(t, nil)
+		user := newUser(t, event(notificationDeadline, "WEB-1 is due soon", time.Minute))
+		if err := sendDigest(user, now); err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.sent) != 0 {
+			t.Errorf("sent %d emails, want none", len(fake.sent))
+		}
+	})
+
+	t.Run("keeps updates a reminder was merged into", func(t *testing.T) {
+		fake := useFakeMailer(t, nil)
+		user := newUser(t, event(notificationUpdated, "WEB-1 was updated", 2*time.Minute), event(notificationDeadline, "WEB-1 is due soon", time.Minute))
+		if err := sendDigest(user, now); err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.sent) != 1 || fake.sent[0].Subject != "WEB-1 was updated" {
+			t.Fatalf("sent %+v, want one digest about the update", fake.sent)
+		}
+	})
+
+	t.Run("skips a reminder merged into a digested update", func(t *testing.T) {
+		fake := useFakeMailer(t, nil)
+		user := newUser(t, event(notificationUpdated, "WEB-1 was updated", 2*time.Hour), event(notificationDeadline, "WEB-1 is due soon", time.Minute))
+		if _, err := usersDb.UpdateOne(context.TODO(), bson.D{{"_id", user.Id}}, bson.D{{"$set", bson.D{{"digest_sent_at", now.Add(-time.Hour).Unix()}}}}); err != nil {
+			t.Fatal(err)
+		}
+		user.DigestSentAt = now.Add(-time.Hour).Unix()
+		if err := sendDigest(user, now); err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.sent) != 0 {
+			t.Errorf("sent %d emails, want none", len(fake.sent))
+		}
+	})
+
+	t.Run("releases the claim when sending fails", func(t *testing.T) {
+		errSmtp := errors.New("smtp unavailable")
+		fake := useFakeMailer(t, errSmtp)
+		user := newUser(t, updated)
+		if err := sendDigest(user, now); !errors.Is(err, errSmtp) {
+			t.Fatalf("sendDigest() = %v, want %v", err, errSmtp)
+		}
+		if len(fake.sent) != 1 {
+			t.Errorf("tried %d emails, want 1", len(fake.sent))
+		}
+		if got := digestSentAt(t, user.Id); got != 0 {
+			t.Errorf("digest_sent_at = %d, want it restored to 0", got)
+		}
+	})
+}
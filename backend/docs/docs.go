@@ -15,30 +15,108 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/boards": {
+        "/atom/{token}": {
+            "get": {
+                "description": "Returns the latest task creations, edits, moves between boards and deletions of a workspace or board as an Atom feed. The secret token in the URL replaces the bearer header, see POST /feeds. Supports conditional requests with If-None-Match and If-Modified-Since.",
+                "produces": [
+                    "application/atom+xml"
+                ],
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Get an Atom feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed token, optionally followed by .xml",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified since the last request"
+                    },
+                    "404": {
+                        "description": "Not Found - unknown or revoked feed",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/caldav/{path}": {
+            "get": {
+                "description": "Serves every board you can access as a CalDAV calendar of VTODO items, at /caldav/calendars/{boardId}/. Creating, completing, renaming, re-dating or deleting a todo in the client changes the task. Supports OPTIONS, PROPFIND, REPORT (calendar-query and calendar-multiget), GET, PUT and DELETE with ETags. Sign in with your email and an app password, or the app password as bearer token.",
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "CalDAV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resource path",
+                        "name": "path",
+                        "in": "path"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The requested resource",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "An app password is required",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    }
+                }
+            }
+        },
+        "/feeds": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns all boards owned by the current user, or all boards for a given workspace.",
+                "description": "Returns the secret feed URLs you created, without their tokens.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Boards"
+                    "Feeds"
                 ],
-                "summary": "Get all boards",
+                "summary": "Get your feeds",
                 "responses": {
                     "200": {
-                        "description": "A list of boards",
+                        "description": "A list of feeds",
                         "schema": {
-                            "$ref": "#/definitions/main.AllBoardsResponse"
+                            "$ref": "#/definitions/main.AllFeedTokensResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -51,7 +129,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new board for the current user or a workspace.",
+                "description": "Creates a secret URL that calendar apps and feed readers can subscribe to without a bearer header. An iCal feed covers the tasks assigned to you in all your workspaces, a whole workspace or one board, with a VEVENT or a VTODO for every task with a deadline. An Atom feed lists the latest task activity of a workspace or one board. The URL is only returned here and when regenerating the token.",
                 "consumes": [
                     "application/json"
                 ],
@@ -59,29 +137,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Boards"
+                    "Feeds"
                 ],
-                "summary": "Create a new board",
+                "summary": "Create a feed",
                 "parameters": [
                     {
-                        "description": "Board creation data",
+                        "description": "Kind ical or atom, scope assigned (ical only), workspace or board, the workspace and board it covers and the component, event or todo",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/main.CreateBoard"
+                            "$ref": "#/definitions/main.CreateFeedToken"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The created board",
+                        "description": "The created feed including its URL",
                         "schema": {
-                            "$ref": "#/definitions/main.Board"
+                            "$ref": "#/definitions/main.FeedToken"
                         }
                     },
                     "400": {
-                        "description": "Bad request - no name given",
+                        "description": "Bad request - invalid kind, scope, workspace or board",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -95,51 +173,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/boards/{boardId}": {
-            "get": {
+        "/feeds/{feedId}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a specific board by its ID.",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Deletes a feed, its URL stops working.",
                 "tags": [
-                    "Boards"
+                    "Feeds"
                 ],
-                "summary": "Get a board by ID",
+                "summary": "Revoke a feed",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Board ID",
-                        "name": "boardId",
+                        "description": "Feed ID",
+                        "name": "feedId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The requested board",
-                        "schema": {
-                            "$ref": "#/definitions/main.Board"
-                        }
+                        "description": "Feed revoked"
                     },
                     "400": {
-                        "description": "Bad request - invalid board ID",
-                        "schema": {
-                            "$ref": "#/definitions/main.ErrorSwagger"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden - you do not have access to this board",
+                        "description": "Bad request - invalid feed id",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "404": {
-                        "description": "Not Found - board not found",
+                        "description": "Not Found - feed not found",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -151,42 +217,47 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/feeds/{feedId}/token": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes a specific board.",
+                "description": "Replaces the secret of a feed. The old URL stops working right away, subscribers need the returned one.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Boards"
+                    "Feeds"
                 ],
-                "summary": "Delete a board",
+                "summary": "Regenerate a feed token",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Board ID",
-                        "name": "boardId",
+                        "description": "Feed ID",
+                        "name": "feedId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Board deleted successfully"
+                        "description": "The feed including its new URL",
+                        "schema": {
+                            "$ref": "#/definitions/main.FeedToken"
+                        }
                     },
-                    "403": {
-                        "description": "Forbidden - you do not own this board",
+                    "400": {
+                        "description": "Bad request - invalid feed id",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "404": {
-                        "description": "Not Found - board not found",
+                        "description": "Not Found - feed not found",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -198,63 +269,87 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Edits the details of a specific board.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/ical/{token}": {
+            "get": {
+                "description": "Returns the tasks with a deadline covered by a feed as an RFC 5545 calendar. The secret token in the URL replaces the bearer header, see POST /feeds. Completed tasks are left out once their deadline is 90 days past.",
                 "produces": [
-                    "application/json"
+                    "text/calendar"
                 ],
                 "tags": [
-                    "Boards"
+                    "Feeds"
                 ],
-                "summary": "Edit a board",
+                "summary": "Get an iCal feed",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Board ID",
-                        "name": "boardId",
+                        "description": "Feed token, optionally followed by .ics",
+                        "name": "token",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Fields to edit in the board",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/main.CreateBoard"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The updated board",
+                        "description": "The calendar",
                         "schema": {
-                            "$ref": "#/definitions/main.Board"
+                            "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad request - invalid input",
+                    "404": {
+                        "description": "Not Found - unknown or revoked feed",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - you do not own this board",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
+                    }
+                }
+            }
+        },
+        "/jobs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the background jobs you started, like imports, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Jobs"
+                ],
+                "summary": "Get your jobs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1 to 500, default 100",
+                        "name": "limit",
+                        "in": "query"
                     },
-                    "404": {
-                        "description": "Not Found - board not found",
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page from a previous response",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A page of jobs",
+                        "schema": {
+                            "$ref": "#/definitions/main.AllJobsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid cursor or limit",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -268,76 +363,129 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks": {
+        "/jobs/{jobId}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns all tasks owned by the current user, or all tasks for a given workspace.",
+                "description": "Returns the status, progress, warnings and result of a background job you started.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks"
+                    "Jobs"
+                ],
+                "summary": "Get a job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "jobId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all tasks",
                 "responses": {
                     "200": {
-                        "description": "A list of tasks",
+                        "description": "The job",
                         "schema": {
-                            "$ref": "#/definitions/main.AllTasksResponse"
+                            "$ref": "#/definitions/main.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid job id",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - job not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/notifications": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new task for the current user or a workspace.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns your notifications, newest activity first, together with the number of unread ones.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks"
+                    "Notifications"
                 ],
-                "summary": "Create a new task",
+                "summary": "Get notifications",
                 "parameters": [
                     {
-                        "description": "Task creation data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/main.CreateTask"
-                        }
+                        "type": "boolean",
+                        "description": "Only return unread notifications",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1 to 500, default 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page from a previous response",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The created task",
+                        "description": "A page of notifications",
                         "schema": {
-                            "$ref": "#/definitions/main.Task"
+                            "$ref": "#/definitions/main.AllNotificationsResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request - check your input",
+                        "description": "Bad request - invalid cursor or limit",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
+                    }
+                }
+            }
+        },
+        "/notifications/read_all": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Mark all notifications as read",
+                "responses": {
+                    "200": {
+                        "description": "Notifications marked as read"
                     },
                     "500": {
                         "description": "Internal server error",
@@ -348,39 +496,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks/{taskId}": {
+        "/notifications/{notificationId}": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes a specific task.",
                 "tags": [
-                    "Tasks"
+                    "Notifications"
                 ],
-                "summary": "Delete an existing task",
+                "summary": "Delete a notification",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task ID",
-                        "name": "taskId",
+                        "description": "Notification ID",
+                        "name": "notificationId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Task deleted successfully"
+                        "description": "Notification deleted successfully"
                     },
-                    "403": {
-                        "description": "Forbidden - you do not have access to this task",
+                    "400": {
+                        "description": "Bad request - invalid notification id",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "404": {
-                        "description": "Not Found - task or workspace not found",
+                        "description": "Not Found - notification not found",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -399,50 +546,233 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Edits the details of a specific task.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Tasks"
+                    "Notifications"
                 ],
-                "summary": "Edit an existing task",
+                "summary": "Mark a notification as read or unread",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task ID",
-                        "name": "taskId",
+                        "description": "Notification ID",
+                        "name": "notificationId",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Fields to edit in the task",
+                        "description": "New read state",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/main.EditTask"
+                            "$ref": "#/definitions/main.EditNotification"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Task updated successfully"
-                    },
-                    "400": {
-                        "description": "Bad request - invalid input or deadline in the past",
+                        "description": "The updated notification",
                         "schema": {
-                            "$ref": "#/definitions/main.ErrorSwagger"
+                            "$ref": "#/definitions/main.Notification"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - you do not have access to this task",
+                    "400": {
+                        "description": "Bad request - invalid notification id",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "404": {
-                        "description": "Not Found - task or workspace not found",
+                        "description": "Not Found - notification not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "409": {
+                        "description": "There already is an unread notification for this task",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Searches task names, descriptions and labels across every workspace the current user is a member of. Tasks have no comments, imported comments are part of the description and are searched with it.\nSupports the filters board:\u003cname or id\u003e, label:\u003cname\u003e, assignee:me, assignee:\u003cuserId\u003e, due:\u003c7d, due:\u003e2w, due:overdue, is:open and is:done. Use quotes for phrases.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Search tasks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ranked search results",
+                        "schema": {
+                            "$ref": "#/definitions/main.SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/app_passwords": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the app passwords you created, without the passwords.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get your app passwords",
+                "responses": {
+                    "200": {
+                        "description": "A list of app passwords",
+                        "schema": {
+                            "$ref": "#/definitions/main.AllAppPasswordsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a password for apps like CalDAV clients, used with your email for basic auth or as a personal bearer token. The password is only returned here.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create an app password",
+                "parameters": [
+                    {
+                        "description": "A name to recognize the app by",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.CreateAppPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The created app password including the password",
+                        "schema": {
+                            "$ref": "#/definitions/main.AppPassword"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing name",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/app_passwords/{passwordId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes an app password, apps using it can no longer sign in.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Revoke an app password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "App password ID",
+                        "name": "passwordId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "App password revoked"
+                    },
+                    "400": {
+                        "description": "Bad request - invalid id",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - app password not found",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -642,6 +972,20 @@ const docTemplate = `{
                 }
             }
         },
+        "/users/logout": {
+            "post": {
+                "description": "Logs out the current user by clearing the refresh token cookie.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Logout user",
+                "responses": {
+                    "200": {
+                        "description": "Successfully logged out"
+                    }
+                }
+            }
+        },
         "/users/refresh": {
             "get": {
                 "description": "Generates a new access token using the refresh token stored in an http-only cookie.",
@@ -680,45 +1024,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/upload_avatar": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Uploads an avatar image for the current user or a specified workspace.",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+        "/users/unsubscribe": {
+            "get": {
+                "description": "Target of the unsubscribe link in digest emails, works without logging in. GET shows a confirmation page, POST turns digests off, which is also what mail clients do for one-click unsubscribe.",
                 "produces": [
-                    "application/json"
+                    "text/html"
                 ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Upload avatar for user or workspace",
+                "summary": "Unsubscribe from email digests",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Avatar image file (jpg or png)",
-                        "name": "img",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "Unsubscribe token from the email",
+                        "name": "token",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Avatar uploaded successfully"
+                        "description": "Digests turned off"
                     },
                     "400": {
-                        "description": "Bad request - no file or wrong format",
-                        "schema": {
-                            "$ref": "#/definitions/main.ErrorSwagger"
-                        }
-                    },
-                    "404": {
-                        "description": "Workspace not found",
+                        "description": "Bad request - invalid token",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -730,32 +1060,37 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/users/workspaces": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Returns a list of all workspaces the current user is a member of.",
+            },
+            "post": {
+                "description": "Target of the unsubscribe link in digest emails, works without logging in. GET shows a confirmation page, POST turns digests off, which is also what mail clients do for one-click unsubscribe.",
                 "produces": [
-                    "application/json"
+                    "text/html"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Users"
+                ],
+                "summary": "Unsubscribe from email digests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unsubscribe token from the email",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all workspaces for the current user",
                 "responses": {
                     "200": {
-                        "description": "A list of workspaces",
+                        "description": "Digests turned off"
+                    },
+                    "400": {
+                        "description": "Bad request - invalid token",
                         "schema": {
-                            "$ref": "#/definitions/main.AllWorkspacesResponse"
+                            "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -763,42 +1098,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaces/add/{joinToken}": {
+        "/users/upload_avatar": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Adds the current user to a workspace using an invite token.",
+                "description": "Uploads an avatar image for the current user or a specified workspace.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Users"
                 ],
-                "summary": "Join a workspace",
+                "summary": "Upload avatar for user or workspace",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Join Token",
-                        "name": "joinToken",
-                        "in": "path",
+                        "type": "file",
+                        "description": "Avatar image file (jpg or png)",
+                        "name": "img",
+                        "in": "formData",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully joined the workspace"
+                        "description": "Avatar path",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
                     },
                     "400": {
-                        "description": "Bad request - invalid token",
+                        "description": "Bad request - no file or wrong format",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "404": {
-                        "description": "Not Found - workspace not found",
+                        "description": "Workspace not found",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -812,44 +1153,60 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaces/create": {
-            "post": {
+        "/users/watched_tasks": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new workspace for the current user.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns tasks you watch directly or through a watched board, across all workspaces you are part of.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Watchers"
                 ],
-                "summary": "Create a new workspace",
+                "summary": "Get watched tasks",
                 "parameters": [
                     {
-                        "description": "Workspace creation data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/main.CreateWorkspace"
-                        }
+                        "enum": [
+                            "open",
+                            "done"
+                        ],
+                        "type": "string",
+                        "description": "Only return open or done tasks",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort key: created, name, priority, deadline, prefix with - to reverse",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1 to 500, default 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page from a previous response",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The created workspace",
+                        "description": "A page of watched tasks",
                         "schema": {
-                            "$ref": "#/definitions/main.Workspace"
+                            "$ref": "#/definitions/main.WatchedTasksResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request - no name specified",
+                        "description": "Bad request - invalid filter, sort or cursor",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -863,92 +1220,87 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaces/{workspaceId}": {
+        "/users/workspaces": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a specific workspace by its ID.",
+                "description": "Returns a list of all workspaces the current user is a member of.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Get a workspace by ID",
+                "summary": "Get all workspaces for the current user",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size, max 500. Without limit and cursor all items are returned",
+                        "name": "limit",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Workspace ID",
-                        "name": "workspaceId",
-                        "in": "path",
-                        "required": true
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The requested workspace",
+                        "description": "A page of workspaces",
                         "schema": {
-                            "$ref": "#/definitions/main.Workspace"
+                            "$ref": "#/definitions/main.AllWorkspacesResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request - invalid workspace ID",
-                        "schema": {
-                            "$ref": "#/definitions/main.ErrorSwagger"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden - you are not a member of this workspace",
-                        "schema": {
-                            "$ref": "#/definitions/main.ErrorSwagger"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found - workspace not found",
+                        "description": "Bad request - invalid limit or cursor",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/workspaces/add/{joinToken}": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes a specific workspace.",
+                "description": "Adds the current user to a workspace using an invite token.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Delete a workspace",
+                "summary": "Join a workspace",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace ID",
-                        "name": "workspaceId",
+                        "description": "Join Token",
+                        "name": "joinToken",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Workspace deleted successfully"
+                        "description": "Successfully joined the workspace"
                     },
-                    "403": {
-                        "description": "Forbidden - you are not the owner of this workspace",
+                    "400": {
+                        "description": "Bad request - invalid token",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -966,14 +1318,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/workspaces/create": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Edits the details of a specific workspace.",
+                "description": "Creates a new workspace for the current user, optionally with the boards, labels, custom fields and seed tasks of one of your templates.",
                 "consumes": [
                     "application/json"
                 ],
@@ -983,46 +1337,27 @@ const docTemplate = `{
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Edit a workspace",
+                "summary": "Create a new workspace",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Workspace ID",
-                        "name": "workspaceId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Fields to edit in the workspace",
+                        "description": "Workspace creation data",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/main.EditWorkspace"
+                            "$ref": "#/definitions/main.CreateWorkspace"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The updated workspace",
+                        "description": "The created workspace",
                         "schema": {
                             "$ref": "#/definitions/main.Workspace"
                         }
                     },
                     "400": {
-                        "description": "Bad request - invalid input",
-                        "schema": {
-                            "$ref": "#/definitions/main.ErrorSwagger"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden - you are not the owner of this workspace",
-                        "schema": {
-                            "$ref": "#/definitions/main.ErrorSwagger"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found - workspace not found",
+                        "description": "Bad request - no name specified, invalid key or unknown template",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -1036,57 +1371,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaces/{workspaceId}/assign": {
+        "/workspaces/import/trello": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Assigns a task within a workspace to a specific user.",
+                "description": "Starts a background job that creates a workspace named after the Trello board and imports it like the import into an existing workspace. Follow the job with GET /jobs/{jobId}, its workspace is set once created.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks"
+                    "Import and export"
                 ],
-                "summary": "Assign a task to a user",
+                "summary": "Import a Trello board as a new workspace",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Workspace ID",
-                        "name": "workspaceId",
-                        "in": "path",
+                        "type": "file",
+                        "description": "Trello board JSON export",
+                        "name": "file",
+                        "in": "formData",
                         "required": true
                     },
                     {
-                        "description": "Task and User IDs",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/main.AssignTask"
-                        }
+                        "enum": [
+                            "boards",
+                            "field"
+                        ],
+                        "type": "string",
+                        "description": "Map lists to boards or to the options of a List field on one board",
+                        "name": "lists",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "The updated task",
+                    "202": {
+                        "description": "The started import job",
                         "schema": {
-                            "$ref": "#/definitions/main.Task"
+                            "$ref": "#/definitions/main.Job"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - you are not the owner or the user to be assigned",
+                    "400": {
+                        "description": "Bad request - missing file or not a Trello export",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
-                    "404": {
-                        "description": "Not Found - workspace or task not found",
+                    "413": {
+                        "description": "The file is too large",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -1100,87 +1436,101 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaces/{workspaceId}/boards": {
+        "/workspaces/invite/{joinToken}": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Returns all boards owned by the current user, or all boards for a given workspace.",
+                "description": "Retrieves workspace details using an invite token.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Boards"
+                    "Workspaces"
                 ],
-                "summary": "Get all boards",
+                "summary": "Get workspace details from invite token",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace ID (optional)",
-                        "name": "workspaceId",
-                        "in": "path"
+                        "description": "Join Token",
+                        "name": "joinToken",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "A list of boards",
+                        "description": "The workspace details",
                         "schema": {
-                            "$ref": "#/definitions/main.AllBoardsResponse"
+                            "$ref": "#/definitions/main.Workspace"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/workspaces/restore": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new board for the current user or a workspace.",
+                "description": "Starts a background job creating a new workspace you own from a backup archive, possibly made on another server. All documents get new ids. You become the only member, the other members are listed in the missing_members result of the job so they can be invited again, and their assignments are dropped. Avatars of members are not restored.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Boards"
+                    "Import and export"
                 ],
-                "summary": "Create a new board",
+                "summary": "Restore a workspace backup",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Workspace ID (optional)",
-                        "name": "workspaceId",
-                        "in": "path"
+                        "type": "file",
+                        "description": "Backup archive",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
                     },
                     {
-                        "description": "Board creation data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/main.CreateBoard"
-                        }
+                        "type": "string",
+                        "description": "Name of the restored workspace instead of the original one",
+                        "name": "name",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "The created board",
+                    "202": {
+                        "description": "The started restore job",
                         "schema": {
-                            "$ref": "#/definitions/main.Board"
+                            "$ref": "#/definitions/main.Job"
                         }
                     },
                     "400": {
-                        "description": "Bad request - no name given",
+                        "description": "Bad request - missing file, not a backup or an unsupported version",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "413": {
+                        "description": "The file is too large",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -1194,59 +1544,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaces/{workspaceId}/boards/{boardId}": {
+        "/workspaces/templates": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a specific board by its ID.",
+                "description": "Returns the workspace templates you saved, including their boards, labels, custom fields and seed tasks.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Boards"
-                ],
-                "summary": "Get a board by ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Board ID",
-                        "name": "boardId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Workspace ID (optional, for authorization context)",
-                        "name": "workspaceId",
-                        "in": "path"
-                    }
+                    "Workspaces"
                 ],
+                "summary": "Get your workspace templates",
                 "responses": {
                     "200": {
-                        "description": "The requested board",
-                        "schema": {
-                            "$ref": "#/definitions/main.Board"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request - invalid board ID",
-                        "schema": {
-                            "$ref": "#/definitions/main.ErrorSwagger"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden - you do not have access to this board",
-                        "schema": {
-                            "$ref": "#/definitions/main.ErrorSwagger"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found - board not found",
+                        "description": "A list of templates",
                         "schema": {
-                            "$ref": "#/definitions/main.ErrorSwagger"
+                            "$ref": "#/definitions/main.AllWorkspaceTemplatesResponse"
                         }
                     },
                     "500": {
@@ -1256,48 +1573,41 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/workspaces/templates/{templateId}": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes a specific board.",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Deletes one of your workspace templates. Workspaces created from it are not affected.",
                 "tags": [
-                    "Boards"
+                    "Workspaces"
                 ],
-                "summary": "Delete a board",
+                "summary": "Delete a workspace template",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Board ID",
-                        "name": "boardId",
+                        "description": "Template ID",
+                        "name": "templateId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Workspace ID (optional)",
-                        "name": "workspaceId",
-                        "in": "path"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Board deleted successfully"
+                        "description": "Template deleted"
                     },
-                    "403": {
-                        "description": "Forbidden - you do not own this board",
+                    "400": {
+                        "description": "Bad request - invalid id",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "404": {
-                        "description": "Not Found - board not found",
+                        "description": "Not Found - template not found",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -1309,69 +1619,53 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/workspaces/{workspaceId}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Edits the details of a specific board.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves a specific workspace by its ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Boards"
+                    "Workspaces"
                 ],
-                "summary": "Edit a board",
+                "summary": "Get a workspace by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Board ID",
-                        "name": "boardId",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Workspace ID (optional)",
-                        "name": "workspaceId",
-                        "in": "path"
-                    },
-                    {
-                        "description": "Fields to edit in the board",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/main.CreateBoard"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The updated board",
+                        "description": "The requested workspace",
                         "schema": {
-                            "$ref": "#/definitions/main.Board"
+                            "$ref": "#/definitions/main.Workspace"
                         }
                     },
                     "400": {
-                        "description": "Bad request - invalid input",
+                        "description": "Bad request - invalid workspace ID",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "403": {
-                        "description": "Forbidden - you do not own this board",
+                        "description": "Forbidden - you are not a member of this workspace",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "404": {
-                        "description": "Not Found - board not found",
+                        "description": "Not Found - workspace not found",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -1383,23 +1677,21 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/workspaces/{workspaceId}/info": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves detailed information about a workspace, including members and boards.",
+                "description": "Deletes a specific workspace.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Get workspace info",
+                "summary": "Delete a workspace",
                 "parameters": [
                     {
                         "type": "string",
@@ -1411,19 +1703,10 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Detailed workspace information",
-                        "schema": {
-                            "$ref": "#/definitions/main.WorkspaceInfo"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request - invalid workspace ID",
-                        "schema": {
-                            "$ref": "#/definitions/main.ErrorSwagger"
-                        }
+                        "description": "Workspace deleted successfully"
                     },
                     "403": {
-                        "description": "Forbidden - you are not a member of this workspace",
+                        "description": "Forbidden - you are not the owner of this workspace",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -1441,23 +1724,24 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/workspaces/{workspaceId}/kick": {
-            "delete": {
+            },
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Removes a member from a specific workspace.",
+                "description": "Edits the details of a specific workspace. Changing the key re-keys all tasks, and keys with an earlier prefix keep resolving to the same tasks.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Kick a member from a workspace",
+                "summary": "Edit a workspace",
                 "parameters": [
                     {
                         "type": "string",
@@ -1467,21 +1751,24 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "User ID to kick",
+                        "description": "Fields to edit in the workspace",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/main.KickUser"
+                            "$ref": "#/definitions/main.EditWorkspace"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Member kicked successfully"
+                        "description": "The updated workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.Workspace"
+                        }
                     },
                     "400": {
-                        "description": "Bad request - workspace ID not specified",
+                        "description": "Bad request - invalid input",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -1493,7 +1780,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Not Found - workspace or user not found",
+                        "description": "Not Found - workspace not found",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -1507,21 +1794,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaces/{workspaceId}/members": {
-            "get": {
+        "/workspaces/{workspaceId}/assign": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns a list of all members in a specific workspace.",
+                "description": "Assigns a task within a workspace to a specific user, who then watches the task and gets an assigned notification unless they assigned themselves. Members can assign themselves, the owner can assign anyone.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Tasks"
                 ],
-                "summary": "Get all members of a workspace",
+                "summary": "Assign a task to a user",
                 "parameters": [
                     {
                         "type": "string",
@@ -1529,29 +1819,38 @@ const docTemplate = `{
                         "name": "workspaceId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Task and User IDs",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.AssignTask"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "A list of members",
+                        "description": "The updated task",
                         "schema": {
-                            "$ref": "#/definitions/main.AllMembersResponse"
+                            "$ref": "#/definitions/main.Task"
                         }
                     },
                     "400": {
-                        "description": "Bad request - invalid workspace ID",
+                        "description": "Bad request - user is not part of this workspace",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "403": {
-                        "description": "Forbidden - you are not a member of this workspace",
+                        "description": "Forbidden - you are not the owner or the user to be assigned",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "404": {
-                        "description": "Not Found - workspace not found",
+                        "description": "Not Found - workspace or task not found",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -1565,21 +1864,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaces/{workspaceId}/new_invite": {
+        "/workspaces/{workspaceId}/backup": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Generates a new invite token for a workspace.",
+                "description": "Streams a zip archive with the workspace, its boards, tasks, labels, custom fields, saved views, references to its members and their avatars. Documents are JSON lines of canonical extended JSON next to a versioned manifest.json. Invite links and webhooks are not part of the backup. Only the owner can back up a workspace.",
                 "produces": [
-                    "application/json"
+                    "application/zip"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Import and export"
                 ],
-                "summary": "Create a workspace invite",
+                "summary": "Back up a workspace",
                 "parameters": [
                     {
                         "type": "string",
@@ -1591,15 +1890,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "The invite token",
-                        "schema": {
-                            "$ref": "#/definitions/main.TokenSwagger"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request - workspace ID not specified",
+                        "description": "The backup archive",
                         "schema": {
-                            "$ref": "#/definitions/main.ErrorSwagger"
+                            "type": "file"
                         }
                     },
                     "403": {
@@ -1613,22 +1906,31 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
                     }
                 }
             }
         },
-        "/workspaces/{workspaceId}/promote/{userId}": {
-            "patch": {
+        "/workspaces/{workspaceId}/boards": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Promotes a member of a workspace to be the new owner.",
+                "description": "Returns all boards for a given workspace together with the estimate totals of their tasks.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Workspaces"
+                    "Boards"
                 ],
-                "summary": "Promote a member to owner",
+                "summary": "Get all boards",
                 "parameters": [
                     {
                         "type": "string",
@@ -1637,74 +1939,37 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "integer",
+                        "description": "Page size, max 500. Without limit and cursor all items are returned",
+                        "name": "limit",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "User ID to promote",
-                        "name": "userId",
-                        "in": "path",
-                        "required": true
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Member promoted successfully"
-                    },
-                    "400": {
-                        "description": "Bad request - user is not part of this workspace",
-                        "schema": {
-                            "$ref": "#/definitions/main.ErrorSwagger"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden - you are not the owner of this workspace",
+                        "description": "A page of boards",
                         "schema": {
-                            "$ref": "#/definitions/main.ErrorSwagger"
+                            "$ref": "#/definitions/main.AllBoardsResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found - workspace or user not found",
+                    "400": {
+                        "description": "Bad request - invalid limit or cursor",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
-                    }
-                }
-            }
-        },
-        "/workspaces/{workspaceId}/tasks": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Returns all tasks owned by the current user, or all tasks for a given workspace.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Tasks"
-                ],
-                "summary": "Get all tasks",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Workspace ID (optional)",
-                        "name": "workspaceId",
-                        "in": "path"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "A list of tasks",
-                        "schema": {
-                            "$ref": "#/definitions/main.AllTasksResponse"
-                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -1720,7 +1985,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new task for the current user or a workspace.",
+                "description": "Creates a new board for a workspace.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1728,35 +1993,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks"
+                    "Boards"
                 ],
-                "summary": "Create a new task",
+                "summary": "Create a new board",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace ID (optional)",
+                        "description": "Workspace ID",
                         "name": "workspaceId",
-                        "in": "path"
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "description": "Task creation data",
+                        "description": "Board creation data",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/main.CreateTask"
+                            "$ref": "#/definitions/main.CreateBoard"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The created task",
+                        "description": "The created board",
                         "schema": {
-                            "$ref": "#/definitions/main.Task"
+                            "$ref": "#/definitions/main.Board"
                         }
                     },
                     "400": {
-                        "description": "Bad request - check your input",
+                        "description": "Bad request - no name given",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -1770,45 +2042,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaces/{workspaceId}/tasks/{taskId}": {
+        "/workspaces/{workspaceId}/boards/{boardId}": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes a specific task.",
+                "description": "Deletes a specific board from a workspace.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Tasks"
+                    "Boards"
                 ],
-                "summary": "Delete an existing task",
+                "summary": "Delete a board",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task ID",
-                        "name": "taskId",
+                        "description": "Board ID",
+                        "name": "boardId",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Workspace ID (optional)",
+                        "description": "Workspace ID",
                         "name": "workspaceId",
-                        "in": "path"
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Task deleted successfully"
+                        "description": "Board deleted successfully"
                     },
                     "403": {
-                        "description": "Forbidden - you do not have access to this task",
+                        "description": "Forbidden - you do not have permission",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "404": {
-                        "description": "Not Found - task or workspace not found",
+                        "description": "Not Found - board or workspace not found",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -1827,56 +2103,63 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Edits the details of a specific task.",
+                "description": "Edits the details of a specific board in a workspace.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Tasks"
+                    "Boards"
                 ],
-                "summary": "Edit an existing task",
+                "summary": "Edit a board",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task ID",
-                        "name": "taskId",
+                        "description": "Board ID",
+                        "name": "boardId",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Workspace ID (optional)",
+                        "description": "Workspace ID",
                         "name": "workspaceId",
-                        "in": "path"
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "description": "Fields to edit in the task",
+                        "description": "Fields to edit in the board",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/main.EditTask"
+                            "$ref": "#/definitions/main.CreateBoard"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Task updated successfully"
+                        "description": "The updated board",
+                        "schema": {
+                            "$ref": "#/definitions/main.Board"
+                        }
                     },
                     "400": {
-                        "description": "Bad request - invalid input or deadline in the past",
+                        "description": "Bad request - invalid input",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "403": {
-                        "description": "Forbidden - you do not have access to this task",
+                        "description": "Forbidden - you do not have permission",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "404": {
-                        "description": "Not Found - task or workspace not found",
+                        "description": "Not Found - board or workspace not found",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -1890,51 +2173,134 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaces/{workspaceId}/upload_avatar": {
+        "/workspaces/{workspaceId}/boards/{boardId}/duplicate": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Uploads an avatar image for the current user or a specified workspace.",
+                "description": "Creates a copy of a board in the same workspace, optionally with copies of its tasks. Copied tasks get new keys and keep their labels, custom fields, assignees and the links between them. Links to tasks on other boards are not copied.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Boards"
                 ],
-                "summary": "Upload avatar for user or workspace",
+                "summary": "Duplicate a board",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Avatar image file (jpg or png)",
-                        "name": "img",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Workspace ID (if uploading for a workspace)",
+                        "description": "Board ID",
+                        "name": "boardId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Name of the copy instead of the original name with (copy) and whether to copy tasks",
+                        "name": "data",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/main.DuplicateBoard"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The created board and the number of copied tasks",
+                        "schema": {
+                            "$ref": "#/definitions/main.DuplicateBoardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid board id",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - board not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/boards/{boardId}/export.csv": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Streams all tasks of a board with all their fields. List columns like labels and assignees are separated by semicolons, custom fields are cf:\u003cname\u003e columns. Cells starting with =, +, -, @, a tab or a carriage return get a leading quote so spreadsheets do not run them as formulas, imports remove it again.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Import and export"
+                ],
+                "summary": "Export a board as CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
                         "name": "workspaceId",
-                        "in": "path"
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Board ID",
+                        "name": "boardId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Avatar uploaded successfully"
+                        "description": "CSV file",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
                     "400": {
-                        "description": "Bad request - no file or wrong format",
+                        "description": "Bad request - invalid board id",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
                     },
                     "404": {
-                        "description": "Workspace not found",
+                        "description": "Not Found - board or workspace not found",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorSwagger"
                         }
@@ -1947,232 +2313,5027 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "main.AllBoardsResponse": {
-            "type": "object",
-            "properties": {
-                "boards": {
+        },
+        "/workspaces/{workspaceId}/boards/{boardId}/export.md": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renders the tasks of a board grouped by status or label, with keys, assignees, deadlines and the state of checklists in descriptions. Takes the same filters as the task list and an optional uploaded template.",
+                "produces": [
+                    "text/markdown"
+                ],
+                "tags": [
+                    "Import and export"
+                ],
+                "summary": "Export a board as Markdown",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Board ID",
+                        "name": "boardId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "status",
+                            "label"
+                        ],
+                        "type": "string",
+                        "description": "Group tasks by status or label, default status",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of an export template of the workspace",
+                        "name": "template",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only open or done tasks",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only tasks with this label ID",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only tasks assigned to this user ID, or me",
+                        "name": "assignee",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The Markdown document",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid filter, grouping or a failing template",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - board or template not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/boards/{boardId}/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves a board with all its tasks to another workspace you own. Tasks keep their ids but get new keys of the target workspace. Labels and custom fields are matched by name in the target workspace and created there if missing. Links to tasks on other boards are removed, as are saved views of the board. Recurring tasks whose next occurrence stays in the source workspace stop repeating, and notifications of moved tasks move along. The board is moved first and its tasks afterwards, tasks added to it in the meantime that couldn't be moved are listed in unmoved_tasks; if moving tasks fails the 500 response lists the tasks left behind. If assignees aren't members of the target workspace the transfer is refused with a 409 listing them, unless force is set: then their assignments, watches and user field values are removed and they are listed in non_members. Only the owner of both workspaces can transfer boards.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Boards"
+                ],
+                "summary": "Transfer a board to another workspace",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Board ID",
+                        "name": "boardId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The target workspace",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.TransferBoard"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The moved board and what had to be removed",
+                        "schema": {
+                            "$ref": "#/definitions/main.TransferBoardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid board id or target workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - board not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - assignees aren't members of the target workspace, listed in non_members",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/boards/{boardId}/watch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Subscribes you to notifications about every task on a board.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Watchers"
+                ],
+                "summary": "Watch a board",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Board ID",
+                        "name": "boardId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The watched board",
+                        "schema": {
+                            "$ref": "#/definitions/main.Board"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid board id",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - board not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Watchers"
+                ],
+                "summary": "Stop watching a board",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Board ID",
+                        "name": "boardId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The board",
+                        "schema": {
+                            "$ref": "#/definitions/main.Board"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid board id",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - board not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/duplicate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Starts a background job copying a workspace with its boards, tasks, labels, custom fields and views into a new workspace you own. All documents get new ids, tasks keep their keys and the members of the workspace become members of the copy. A copy that fails halfway is removed again. Only the owner can duplicate a workspace.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Duplicate a workspace",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Name of the copy instead of the original name with (copy)",
+                        "name": "data",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/main.DuplicateWorkspace"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "The started duplicate job",
+                        "schema": {
+                            "$ref": "#/definitions/main.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events stream of task, board and membership changes in a workspace. Pass board to only receive changes of one board. Reconnecting clients send Last-Event-ID to receive the events they missed.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Stream workspace events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only stream events of this board",
+                        "name": "board",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only stream task and board events of what you watch",
+                        "name": "watching",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stream ticket, for clients that can not set the Authorization header",
+                        "name": "ticket",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of events",
+                        "schema": {
+                            "$ref": "#/definitions/main.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid board id",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/events/ticket": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a ticket that opens the event stream of a workspace for one minute. Clients that can not set the Authorization header, like the browser EventSource, pass it as the ticket query parameter instead of their access token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Create an event stream ticket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The ticket and when it expires",
+                        "schema": {
+                            "$ref": "#/definitions/main.StreamTicket"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/export.csv": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Streams all tasks of all boards of a workspace, in the same format as the board export.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Import and export"
+                ],
+                "summary": "Export a workspace as CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/export.md": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renders the tasks of a workspace matching the filters of the task list, grouped by status or label, with keys, assignees, deadlines and the state of checklists in descriptions. At most 5000 tasks.",
+                "produces": [
+                    "text/markdown"
+                ],
+                "tags": [
+                    "Import and export"
+                ],
+                "summary": "Export tasks as Markdown",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "status",
+                            "label"
+                        ],
+                        "type": "string",
+                        "description": "Group tasks by status or label, default status",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of an export template of the workspace",
+                        "name": "template",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only open or done tasks",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only tasks with this label ID",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only tasks assigned to this user ID, or me",
+                        "name": "assignee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Relative deadline like overdue, \u003c3d or \u003e1w",
+                        "name": "due",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text in the name or description",
+                        "name": "q",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The Markdown document",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid filter, grouping, too many tasks or a failing template",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - workspace or template not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/export_templates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the Markdown export templates of a workspace.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import and export"
+                ],
+                "summary": "Get all export templates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A list of export templates",
+                        "schema": {
+                            "$ref": "#/definitions/main.AllExportTemplatesResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a Go text/template for Markdown exports, as JSON or as an uploaded file. Templates get the Title, Workspace, Board, GroupBy, ExportedAt, Tasks and Groups of an export, every group has a Name and Tasks. Tasks have Key, Name, Description, Url, Board, Status, Completed, Priority, Labels, Assignees, Deadline, CreatedAt, CompletedAt and a Checklist with Done, Total and Items. The functions escape, join, date, datetime and indent are available. Templates can not define sub-templates or range over numbers, range is nested at most 3 levels and inner loops only go over fields of the current item. Only the owner can manage templates.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import and export"
+                ],
+                "summary": "Upload an export template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Name and template",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.CreateExportTemplate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The created template",
+                        "schema": {
+                            "$ref": "#/definitions/main.ExportTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing name or an invalid template",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "413": {
+                        "description": "The template is too large",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/export_templates/{templateId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a Markdown export template.",
+                "tags": [
+                    "Import and export"
+                ],
+                "summary": "Delete an export template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "templateId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Template deleted"
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - template not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renames a template or replaces its source, as JSON or as an uploaded file.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import and export"
+                ],
+                "summary": "Edit an export template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "templateId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.CreateExportTemplate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated template",
+                        "schema": {
+                            "$ref": "#/definitions/main.ExportTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - an invalid template",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - template not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "413": {
+                        "description": "The template is too large",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/fields": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the custom field schemas defined in a workspace.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Custom Fields"
+                ],
+                "summary": "Get all custom fields",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A list of custom fields",
+                        "schema": {
+                            "$ref": "#/definitions/main.AllCustomFieldsResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Defines a new custom field for tasks of a workspace. Only the workspace owner can do this.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Custom Fields"
+                ],
+                "summary": "Create a custom field",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Custom field schema",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.CreateCustomField"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The created custom field",
+                        "schema": {
+                            "$ref": "#/definitions/main.CustomField"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid name, type or options",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/fields/{fieldId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a custom field and removes its values from every task.",
+                "tags": [
+                    "Custom Fields"
+                ],
+                "summary": "Delete a custom field",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom field ID",
+                        "name": "fieldId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Custom field deleted successfully"
+                    },
+                    "400": {
+                        "description": "Bad request - invalid field id",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - custom field not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renames a custom field or changes its options. Options that are removed are also removed from every task. The type of a field can not be changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Custom Fields"
+                ],
+                "summary": "Edit a custom field",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom field ID",
+                        "name": "fieldId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to edit in the custom field",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.EditCustomField"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated custom field",
+                        "schema": {
+                            "$ref": "#/definitions/main.CustomField"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - custom field not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/import.csv": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a task for every valid row of a CSV file. Columns are matched to task fields by name, as in the CSV export, or through an explicit mapping. Invalid rows are skipped and reported with the reason. With dry_run nothing is written and the report shows what an import would do.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import and export"
+                ],
+                "summary": "Import tasks from CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV file, the first row is the header",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON object from column name to task field like name, board, deadline or cf:\u003cfield name\u003e, an empty field ignores the column",
+                        "name": "mapping",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate and report, do not import",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Create boards named in the board column that do not exist",
+                        "name": "create_boards",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Board for rows without a board column",
+                        "name": "board",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "What was or would be imported and the errors of every invalid row",
+                        "schema": {
+                            "$ref": "#/definitions/main.CsvImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing file, invalid mapping or malformed CSV",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/import/github": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Starts a background job importing a JSON list of GitHub issues, like the output of gh issue list --json number,title,body,state,url,labels,assignees,comments,milestone,createdAt,closedAt or issues from the REST API. Issues become tasks on a board per repository with labels, assignees matched by email or name, comments added to the description and the milestone due date as deadline. Importing a file again skips issues that were imported before.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import and export"
+                ],
+                "summary": "Import GitHub issues",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "JSON array of GitHub issues",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Put all issues on this board instead of a board per repository",
+                        "name": "board",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "owner/repo the issues belong to, required if they have no url",
+                        "name": "repository",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "The started import job",
+                        "schema": {
+                            "$ref": "#/definitions/main.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing file, invalid board or repository, or not a list of issues",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "413": {
+                        "description": "The file is too large",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/import/jira": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Starts a background job importing the issues of a Jira CSV export, with all fields. Issues become tasks on a board per status or per project, with priority, assignee matched by email or name, labels, due date, story points and comments added to the description. Importing a file again skips issues that were imported before.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import and export"
+                ],
+                "summary": "Import a Jira CSV export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Jira CSV export",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "status",
+                            "project"
+                        ],
+                        "type": "string",
+                        "description": "Create a board per status or per project, default status",
+                        "name": "boards",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "The started import job",
+                        "schema": {
+                            "$ref": "#/definitions/main.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing file or malformed CSV",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "413": {
+                        "description": "The file is too large",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/import/trello": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Starts a background job importing a Trello board export. Lists become boards, or options of a List field. Cards become tasks with their description, due date, labels and members matched by name. Checklists and comments are added to the description. Archived cards, attachments and unmatched members are listed in the warnings of the job.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import and export"
+                ],
+                "summary": "Import a Trello board into a workspace",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Trello board JSON export",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "boards",
+                            "field"
+                        ],
+                        "type": "string",
+                        "description": "Map lists to boards or to the options of a List field on one board",
+                        "name": "lists",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "The started import job",
+                        "schema": {
+                            "$ref": "#/definitions/main.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing file or not a Trello export",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "413": {
+                        "description": "The file is too large",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/info": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves detailed information about a workspace, including members and boards.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace info",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Detailed workspace information",
+                        "schema": {
+                            "$ref": "#/definitions/main.WorkspaceInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid workspace ID",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/kick": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a member from a specific workspace.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Kick a member from a workspace",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User ID to kick",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.KickUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Member kicked successfully"
+                    },
+                    "400": {
+                        "description": "Bad request - workspace ID not specified",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - workspace or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/labels": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns all labels defined in a workspace.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Labels"
+                ],
+                "summary": "Get all labels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A list of labels",
+                        "schema": {
+                            "$ref": "#/definitions/main.AllLabelsResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new label in a workspace.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Labels"
+                ],
+                "summary": "Create a label",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Label creation data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.CreateLabel"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The created label",
+                        "schema": {
+                            "$ref": "#/definitions/main.Label"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - no name given or bad color",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/labels/{labelId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a label and removes it from every task it was attached to.",
+                "tags": [
+                    "Labels"
+                ],
+                "summary": "Delete a label",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Label ID",
+                        "name": "labelId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Label deleted successfully"
+                    },
+                    "400": {
+                        "description": "Bad request - invalid label id",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - label not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the name or color of a label.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Labels"
+                ],
+                "summary": "Edit a label",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Label ID",
+                        "name": "labelId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to edit in the label",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.CreateLabel"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated label",
+                        "schema": {
+                            "$ref": "#/definitions/main.Label"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - label not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/new_invite": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generates a new invite token for a workspace.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Create a workspace invite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The invite token",
+                        "schema": {
+                            "$ref": "#/definitions/main.TokenSwagger"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - workspace ID not specified",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/promote/{userId}": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Promotes a member of a workspace to be the new owner.",
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Promote a member to owner",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID to promote",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Member promoted successfully"
+                    },
+                    "400": {
+                        "description": "Bad request - user is not part of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - workspace or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/tasks": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new task for a workspace. With a recurrence rule the task becomes the first occurrence of a series and needs a deadline.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Create a new task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Task creation data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.CreateTask"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The created task",
+                        "schema": {
+                            "$ref": "#/definitions/main.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - check your input or unknown label",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/tasks/{boardId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a page of tasks on a board. Filters can be combined, use next_cursor to fetch the following page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Get all tasks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Board ID",
+                        "name": "boardId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return tasks with this label",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return tasks assigned to this user id, or me",
+                        "name": "assignee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return tasks whose name or description contains this text",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return tasks with a deadline at or after this unix time",
+                        "name": "deadline_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return tasks with a deadline at or before this unix time",
+                        "name": "deadline_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return tasks created at or after this unix time",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return tasks created at or before this unix time",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "open",
+                            "done"
+                        ],
+                        "type": "string",
+                        "description": "Only return open or done tasks",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Relative deadline filter: overdue, \u003c7d, \u003e2w, \u003c24h",
+                        "name": "due",
+                        "in": "query"
+                    },
+                    {
+                        "type": "object",
+                        "description": "Custom field filters as cf[\u003cfieldId\u003e]=\u003cvalue\u003e",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort by created (default), name, priority (urgent first), deadline (soonest first) or cf.\u003cfieldId\u003e. Prefix with - to reverse",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, max 500. Without limit and cursor all items are returned",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A page of tasks and the estimate totals of the board",
+                        "schema": {
+                            "$ref": "#/definitions/main.AllTasksResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid filter, sort key or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/tasks/{taskId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a specific task.",
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Delete an existing task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID or key like WEB-42",
+                        "name": "taskId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task deleted successfully"
+                    },
+                    "403": {
+                        "description": "Forbidden - you do not have access to this task",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - task or workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Edits the details of a specific task. A completed_at of 0 reopens a completed task. Setting a recurrence rule makes the task the first occurrence of a series, an empty rule stops the series.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Edit an existing task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID or key like WEB-42",
+                        "name": "taskId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to edit in the task",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.EditTask"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Complete the task even if it is blocked by unfinished tasks",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task updated successfully"
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input or deadline in the past",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you do not have access to this task",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - task or workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "409": {
+                        "description": "Task is blocked by unfinished tasks",
+                        "schema": {
+                            "$ref": "#/definitions/main.BlockedTaskSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/tasks/{taskId}/links": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a typed link (blocks, relates_to, duplicates) between two tasks of the same workspace. Blocking links that would form a cycle are rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Link two tasks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task ID or key like WEB-42",
+                        "name": "taskId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Link type and target task",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.CreateTaskLink"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The created link",
+                        "schema": {
+                            "$ref": "#/definitions/main.TaskLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid link",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you do not have access to this task",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - task not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "409": {
+                        "description": "Link already exists or would create a cycle",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/tasks/{taskId}/links/{linkedTaskId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes every link between two tasks, on both sides.",
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Remove a task link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task ID or key like WEB-42",
+                        "name": "taskId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Linked task ID or key",
+                        "name": "linkedTaskId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Link removed successfully"
+                    },
+                    "400": {
+                        "description": "Bad request - invalid linked task id",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you do not have access to this task",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - task or linked task not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/tasks/{taskId}/watch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Subscribes you to notifications about changes of a task.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Watchers"
+                ],
+                "summary": "Watch a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task ID or key like WEB-42",
+                        "name": "taskId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The watched task",
+                        "schema": {
+                            "$ref": "#/definitions/main.Task"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you do not have access to this task",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - task not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Watchers"
+                ],
+                "summary": "Stop watching a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task ID or key like WEB-42",
+                        "name": "taskId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The task",
+                        "schema": {
+                            "$ref": "#/definitions/main.Task"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you do not have access to this task",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - task not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/template": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Saves the boards, labels and custom fields of a workspace as a template to create new workspaces from. With include_tasks the open tasks become seed tasks, without their assignees, watchers, dates and recurrence. Only the owner can save a workspace as template.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Save a workspace as template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Name of the template and whether to include tasks",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.CreateWorkspaceTemplate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The saved template",
+                        "schema": {
+                            "$ref": "#/definitions/main.WorkspaceTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid JSON or too many seed tasks",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/unassign": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a user from the assignees of a task. Members can unassign themselves, the owner can unassign anyone.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Unassign a user from a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Task and User IDs",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.AssignTask"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated task",
+                        "schema": {
+                            "$ref": "#/definitions/main.Task"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner or the user to be unassigned",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - workspace or task not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/upload_avatar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Uploads an avatar image for the current user or a specified workspace.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Upload avatar for user or workspace",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Avatar image file (jpg or png)",
+                        "name": "img",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace ID (if uploading for a workspace)",
+                        "name": "workspaceId",
+                        "in": "path"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Avatar path",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - no file or wrong format",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/views": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns your own views and the views shared with the workspace. Pass boardId to only get views of one board.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Views"
+                ],
+                "summary": "Get all saved views",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return views of this board",
+                        "name": "boardId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A list of saved views",
+                        "schema": {
+                            "$ref": "#/definitions/main.AllSavedViewsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid board id",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Saves a named task filter with sort, grouping and visible fields. Views are private unless shared with the workspace.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Views"
+                ],
+                "summary": "Create a saved view",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "View definition",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.CreateSavedView"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The created view",
+                        "schema": {
+                            "$ref": "#/definitions/main.SavedView"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid filter, grouping or board",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/views/{viewId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a view. The creator of a view and the workspace owner can delete it.",
+                "tags": [
+                    "Views"
+                ],
+                "summary": "Delete a saved view",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "View ID",
+                        "name": "viewId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "View deleted successfully"
+                    },
+                    "400": {
+                        "description": "Bad request - invalid view id",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you can not delete this view",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - view not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes a view. Only the user who created the view can edit it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Views"
+                ],
+                "summary": "Edit a saved view",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "View ID",
+                        "name": "viewId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to edit in the view",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.EditSavedView"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated view",
+                        "schema": {
+                            "$ref": "#/definitions/main.SavedView"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you did not create this view",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - view not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/views/{viewId}/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Runs the filter and sort of a view with the same pagination as task listings. \"me\" in a view always means the current user. Groups describe how the tasks of the returned page are split by the view grouping.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Views"
+                ],
+                "summary": "Get tasks of a saved view",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "View ID",
+                        "name": "viewId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1 to 500, default 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page from a previous response",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The view and a page of its tasks",
+                        "schema": {
+                            "$ref": "#/definitions/main.SavedViewTasksResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid view id, cursor or outdated view filter",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not a member of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - view not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the webhooks of a workspace. Secrets are not included. Only the workspace owner can do this.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get all webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A list of webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/main.AllWebhooksResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Subscribes a URL to workspace events. Every request carries an X-Rela-Signature header with the HMAC-SHA256 of the body keyed with the secret. A secret is generated when none is given, it is only returned here.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Create a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "URL, secret and events like task.created, board.* or *",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.CreateWebhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The created webhook including its secret",
+                        "schema": {
+                            "$ref": "#/definitions/main.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid url or events",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/webhooks/{webhookId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a webhook together with its delivery log.",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook deleted successfully"
+                    },
+                    "400": {
+                        "description": "Bad request - invalid webhook id",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the URL, secret or events of a webhook, or pauses it with active set to false.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Edit a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to edit in the webhook",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.EditWebhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated webhook",
+                        "schema": {
+                            "$ref": "#/definitions/main.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/webhooks/{webhookId}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the delivery log of a webhook, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "delivered",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Only return deliveries with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1 to 500, default 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page from a previous response",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A page of deliveries",
+                        "schema": {
+                            "$ref": "#/definitions/main.AllWebhookDeliveriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid webhook id or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queues a new delivery with the same payload as an earlier one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Redeliver a webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The queued delivery",
+                        "schema": {
+                            "$ref": "#/definitions/main.WebhookDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid id",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - webhook or delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspaceId}/webhooks/{webhookId}/ping": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sends a ping event to the webhook right away and returns the logged delivery. Failed pings are retried like other deliveries.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Ping a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "workspaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The ping delivery",
+                        "schema": {
+                            "$ref": "#/definitions/main.WebhookDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid webhook id",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - you are not the owner of this workspace",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorSwagger"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "gin.H": {
+            "type": "object",
+            "additionalProperties": {}
+        },
+        "main.AllAppPasswordsResponse": {
+            "type": "object",
+            "properties": {
+                "app_passwords": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.AppPassword"
+                    }
+                }
+            }
+        },
+        "main.AllBoardsResponse": {
+            "type": "object",
+            "properties": {
+                "boards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.Board"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.AllCustomFieldsResponse": {
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.CustomField"
+                    }
+                }
+            }
+        },
+        "main.AllExportTemplatesResponse": {
+            "type": "object",
+            "properties": {
+                "templates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.ExportTemplate"
+                    }
+                }
+            }
+        },
+        "main.AllFeedTokensResponse": {
+            "type": "object",
+            "properties": {
+                "feeds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.FeedToken"
+                    }
+                }
+            }
+        },
+        "main.AllJobsResponse": {
+            "type": "object",
+            "properties": {
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.Job"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.AllLabelsResponse": {
+            "type": "object",
+            "properties": {
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.Label"
+                    }
+                }
+            }
+        },
+        "main.AllNotificationsResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "notifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.Notification"
+                    }
+                },
+                "unread": {
+                    "type": "integer"
+                }
+            }
+        },
+        "main.AllSavedViewsResponse": {
+            "type": "object",
+            "properties": {
+                "views": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.SavedView"
+                    }
+                }
+            }
+        },
+        "main.AllTasksResponse": {
+            "type": "object",
+            "properties": {
+                "estimate": {
+                    "$ref": "#/definitions/main.EstimateTotals"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.Task"
+                    }
+                }
+            }
+        },
+        "main.AllWebhookDeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.WebhookDelivery"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.AllWebhooksResponse": {
+            "type": "object",
+            "properties": {
+                "webhooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.Webhook"
+                    }
+                }
+            }
+        },
+        "main.AllWorkspaceTemplatesResponse": {
+            "type": "object",
+            "properties": {
+                "templates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.WorkspaceTemplate"
+                    }
+                }
+            }
+        },
+        "main.AllWorkspacesResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "workspaces": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.Workspace"
+                    }
+                }
+            }
+        },
+        "main.AppPassword": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.AssignTask": {
+            "type": "object",
+            "properties": {
+                "taskId": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.BlockedTaskSwagger": {
+            "type": "object",
+            "properties": {
+                "blocked_by": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.Board": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "estimate": {
+                    "$ref": "#/definitions/main.EstimateTotals"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owned_by": {
+                    "type": "string"
+                },
+                "watchers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "main.CreateAppPassword": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.CreateBoard": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.CreateCustomField": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.CreateExportTemplate": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "template": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.CreateFeedToken": {
+            "type": "object",
+            "properties": {
+                "board": {
+                    "type": "string"
+                },
+                "component": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "workspace": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.CreateLabel": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.CreateSavedView": {
+            "type": "object",
+            "properties": {
+                "board": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "query": {
+                    "$ref": "#/definitions/main.TaskQuery"
+                },
+                "shared": {
+                    "type": "boolean"
+                },
+                "visible_fields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "main.CreateTask": {
+            "type": "object",
+            "properties": {
+                "board": {
+                    "type": "string"
+                },
+                "custom_fields": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "deadline": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "estimate": {
+                    "$ref": "#/definitions/main.TaskEstimate"
+                },
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "recurrence": {
+                    "$ref": "#/definitions/main.TaskRecurrenceInput"
+                }
+            }
+        },
+        "main.CreateTaskLink": {
+            "type": "object",
+            "properties": {
+                "task": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.CreateUser": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.CreateWebhook": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.CreateWorkspace": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "template": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.CreateWorkspaceTemplate": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "include_tasks": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.CsvImportReport": {
+            "type": "object",
+            "properties": {
+                "created_boards": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.CsvRowError"
+                    }
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "ignored_columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "integer"
+                }
+            }
+        },
+        "main.CsvRowError": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
+        "main.CustomField": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                },
+                "workspace": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.DuplicateBoard": {
+            "type": "object",
+            "properties": {
+                "include_tasks": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.DuplicateBoardResponse": {
+            "type": "object",
+            "properties": {
+                "board": {
+                    "$ref": "#/definitions/main.Board"
+                },
+                "tasks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "main.DuplicateWorkspace": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.EditCustomField": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "main.EditNotification": {
+            "type": "object",
+            "properties": {
+                "read": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "main.EditSavedView": {
+            "type": "object",
+            "properties": {
+                "group_by": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "query": {
+                    "$ref": "#/definitions/main.TaskQuery"
+                },
+                "shared": {
+                    "type": "boolean"
+                },
+                "visible_fields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "main.EditTask": {
+            "type": "object",
+            "properties": {
+                "add_labels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "board": {
+                    "type": "string"
+                },
+                "completed_at": {
+                    "type": "integer"
+                },
+                "custom_fields": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "deadline": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "estimate": {
+                    "$ref": "#/definitions/main.TaskEstimate"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "recurrence": {
+                    "$ref": "#/definitions/main.TaskRecurrenceInput"
+                },
+                "remove_labels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "main.EditWebhook": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.EditWorkspace": {
+            "type": "object",
+            "properties": {
+                "avatar": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.ErrorSwagger": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.EstimateTotals": {
+            "type": "object",
+            "properties": {
+                "minutes": {
+                    "type": "number"
+                },
+                "open_minutes": {
+                    "type": "number"
+                },
+                "open_points": {
+                    "type": "number"
+                },
+                "points": {
+                    "type": "number"
+                }
+            }
+        },
+        "main.Event": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "board": {
+                    "$ref": "#/definitions/main.Board"
+                },
+                "board_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "member": {
+                    "$ref": "#/definitions/main.Member"
+                },
+                "task": {
+                    "$ref": "#/definitions/main.Task"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "workspace": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.ExportTemplate": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "template": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "integer"
+                },
+                "workspace": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.FeedToken": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "board": {
+                    "type": "string"
+                },
+                "component": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "integer"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                },
+                "workspace": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.Job": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "integer"
+                },
+                "progress": {
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "integer"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "workspace": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.KickUser": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.Label": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "workspace": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.LoginUser": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.Member": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "avatar": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.NonMemberAssignee": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "main.Notification": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.NotificationEvent"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "read": {
+                    "type": "boolean"
+                },
+                "task": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "integer"
+                },
+                "user": {
+                    "type": "string"
+                },
+                "workspace": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.NotificationEvent": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.SavedView": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "board": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "query": {
+                    "$ref": "#/definitions/main.TaskQuery"
+                },
+                "shared": {
+                    "type": "boolean"
+                },
+                "visible_fields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "workspace": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.SavedViewTasksResponse": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.TaskGroup"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.Task"
+                    }
+                },
+                "view": {
+                    "$ref": "#/definitions/main.SavedView"
+                }
+            }
+        },
+        "main.SearchHighlight": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "fragment": {
+                    "type": "string"
+                },
+                "matches": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        }
+                    }
+                }
+            }
+        },
+        "main.SearchResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.SearchResult"
+                    }
+                }
+            }
+        },
+        "main.SearchResult": {
+            "type": "object",
+            "properties": {
+                "highlights": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.SearchHighlight"
+                    }
+                },
+                "score": {
+                    "type": "number"
+                },
+                "task": {
+                    "$ref": "#/definitions/main.Task"
+                }
+            }
+        },
+        "main.StreamTicket": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "integer"
+                },
+                "ticket": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.Task": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "assignees": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "board": {
+                    "type": "string"
+                },
+                "completed_at": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "custom_fields": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "deadline": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "estimate": {
+                    "$ref": "#/definitions/main.TaskEstimate"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "labels": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/main.Board"
+                        "type": "string"
                     }
-                }
-            }
-        },
-        "main.AllMembersResponse": {
-            "type": "object",
-            "properties": {
-                "members": {
+                },
+                "links": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/main.Member"
+                        "$ref": "#/definitions/main.TaskLink"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "recurrence": {
+                    "$ref": "#/definitions/main.TaskRecurrence"
+                },
+                "watchers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 }
             }
         },
-        "main.AllTasksResponse": {
+        "main.TaskEstimate": {
             "type": "object",
             "properties": {
-                "tasks": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/main.Task"
-                    }
+                "unit": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
                 }
             }
         },
-        "main.AllWorkspacesResponse": {
+        "main.TaskGroup": {
             "type": "object",
             "properties": {
-                "workspaces": {
+                "key": {
+                    "type": "string"
+                },
+                "tasks": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/main.Workspace"
+                        "type": "string"
                     }
                 }
             }
         },
-        "main.AssignTask": {
+        "main.TaskLink": {
             "type": "object",
             "properties": {
-                "taskId": {
+                "task": {
                     "type": "string"
                 },
-                "userId": {
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "main.Board": {
+        "main.TaskQuery": {
             "type": "object",
             "properties": {
-                "_id": {
+                "assignee": {
                     "type": "string"
                 },
-                "name": {
+                "created_from": {
+                    "type": "integer"
+                },
+                "created_to": {
+                    "type": "integer"
+                },
+                "custom_fields": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "deadline_from": {
+                    "type": "integer"
+                },
+                "deadline_to": {
+                    "type": "integer"
+                },
+                "due": {
                     "type": "string"
                 },
-                "owned_by": {
+                "label": {
+                    "type": "string"
+                },
+                "sort": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "text": {
                     "type": "string"
                 }
             }
         },
-        "main.CreateBoard": {
+        "main.TaskRecurrence": {
             "type": "object",
             "properties": {
-                "name": {
+                "ended": {
+                    "type": "boolean"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "next_task": {
+                    "type": "string"
+                },
+                "occurrence": {
+                    "type": "integer"
+                },
+                "rule": {
+                    "type": "string"
+                },
+                "series": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "integer"
+                },
+                "timezone": {
                     "type": "string"
                 }
             }
         },
-        "main.CreateTask": {
+        "main.TaskRecurrenceInput": {
             "type": "object",
             "properties": {
-                "board": {
+                "mode": {
                     "type": "string"
                 },
-                "description": {
+                "rule": {
                     "type": "string"
                 },
-                "name": {
+                "timezone": {
                     "type": "string"
                 }
             }
         },
-        "main.CreateUser": {
+        "main.TokenSwagger": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "password": {
+                "token": {
                     "type": "string"
                 }
             }
         },
-        "main.CreateWorkspace": {
+        "main.TransferBoard": {
             "type": "object",
             "properties": {
-                "name": {
+                "force": {
+                    "description": "Transfer even if assignees aren't members of the target workspace, their assignments are removed",
+                    "type": "boolean"
+                },
+                "workspace": {
                     "type": "string"
                 }
             }
         },
-        "main.EditTask": {
+        "main.TransferBoardResponse": {
             "type": "object",
             "properties": {
                 "board": {
-                    "type": "string"
+                    "$ref": "#/definitions/main.Board"
                 },
-                "completed_at": {
+                "non_members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.NonMemberAssignee"
+                    }
+                },
+                "removed_links": {
                     "type": "integer"
                 },
-                "deadline": {
+                "removed_views": {
                     "type": "integer"
                 },
-                "description": {
-                    "type": "string"
+                "tasks": {
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "unmoved_tasks": {
+                    "description": "Tasks added to the board in the source workspace faster than they could be moved",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "main.EditWorkspace": {
+        "main.User": {
             "type": "object",
             "properties": {
+                "_id": {
+                    "type": "string"
+                },
                 "avatar": {
                     "type": "string"
                 },
-                "name": {
+                "email": {
                     "type": "string"
-                }
-            }
-        },
-        "main.ErrorSwagger": {
-            "type": "object",
-            "properties": {
-                "error": {
+                },
+                "email_digest": {
                     "type": "string"
-                }
-            }
-        },
-        "main.KickUser": {
-            "type": "object",
-            "properties": {
-                "id": {
+                },
+                "name": {
+                    "type": "string"
+                },
+                "timezone": {
                     "type": "string"
                 }
             }
         },
-        "main.LoginUser": {
+        "main.WatchedTasksResponse": {
             "type": "object",
             "properties": {
-                "email": {
+                "next_cursor": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string"
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.Task"
+                    }
                 }
             }
         },
-        "main.Member": {
+        "main.Webhook": {
             "type": "object",
             "properties": {
                 "_id": {
                     "type": "string"
                 },
-                "avatar": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
                     "type": "string"
                 },
-                "name": {
+                "url": {
+                    "type": "string"
+                },
+                "workspace": {
                     "type": "string"
                 }
             }
         },
-        "main.Task": {
+        "main.WebhookDelivery": {
             "type": "object",
             "properties": {
                 "_id": {
                     "type": "string"
                 },
-                "assigned_to": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "delivered_at": {
+                    "type": "integer"
+                },
+                "error": {
                     "type": "string"
                 },
-                "board": {
+                "event": {
                     "type": "string"
                 },
-                "created_at": {
+                "next_attempt_at": {
                     "type": "integer"
                 },
-                "created_by": {
+                "payload": {
                     "type": "string"
                 },
-                "deadline": {
+                "response_code": {
                     "type": "integer"
                 },
-                "description": {
+                "status": {
                     "type": "string"
                 },
-                "name": {
+                "webhook": {
                     "type": "string"
-                }
-            }
-        },
-        "main.TokenSwagger": {
-            "type": "object",
-            "properties": {
-                "token": {
+                },
+                "workspace": {
                     "type": "string"
                 }
             }
         },
-        "main.User": {
+        "main.Workspace": {
             "type": "object",
             "properties": {
                 "_id": {
@@ -2181,15 +7342,37 @@ const docTemplate = `{
                 "avatar": {
                     "type": "string"
                 },
-                "email": {
+                "key": {
                     "type": "string"
                 },
+                "key_aliases": {
+                    "description": "Earlier key prefixes, so task keys issued before a key change keep resolving",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "name": {
                     "type": "string"
+                },
+                "owned_by": {
+                    "type": "string"
+                },
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.WorkspaceTokens"
+                    }
                 }
             }
         },
-        "main.Workspace": {
+        "main.WorkspaceInfo": {
             "type": "object",
             "properties": {
                 "_id": {
@@ -2198,10 +7381,19 @@ const docTemplate = `{
                 "avatar": {
                     "type": "string"
                 },
-                "members": {
+                "boards": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/main.Board"
+                    }
+                },
+                "key": {
+                    "type": "string"
+                },
+                "memberDetails": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.Member"
                     }
                 },
                 "name": {
@@ -2212,32 +7404,64 @@ const docTemplate = `{
                 }
             }
         },
-        "main.WorkspaceInfo": {
+        "main.WorkspaceTemplate": {
             "type": "object",
             "properties": {
                 "_id": {
                     "type": "string"
                 },
-                "avatar": {
-                    "type": "string"
-                },
                 "boards": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/main.Board"
                     }
                 },
-                "memberDetails": {
+                "created_at": {
+                    "type": "integer"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "custom_fields": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/main.Member"
+                        "$ref": "#/definitions/main.CustomField"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.Label"
                     }
                 },
                 "name": {
                     "type": "string"
                 },
-                "owned_by": {
+                "source": {
+                    "type": "string"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.Task"
+                    }
+                }
+            }
+        },
+        "main.WorkspaceTokens": {
+            "type": "object",
+            "properties": {
+                "sqid": {
+                    "type": "string"
+                },
+                "token": {
                     "type": "string"
+                },
+                "uses": {
+                    "type": "integer"
                 }
             }
         }
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"math"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	estimatePoints  = "points"
+	estimateMinutes = "minutes"
+)
+
+// Priorities are stored as strings for the API and as a rank so MongoDB can sort on them.
+var priorityRanks = map[string]int{
+	"none":   0,
+	"low":    1,
+	"medium": 2,
+	"high":   3,
+	"urgent": 4,
+}
+
+// setTaskPriority validates the priority and stores it together with its rank.
+// An empty priority means "none".
+func setTaskPriority(task *Task, priority string) bool {
+	if priority == "" {
+		priority = "none"
+	}
+	rank, ok := priorityRanks[priority]
+	if !ok {
+		return false
+	}
+	task.Priority = priority
+	task.PriorityRank = rank
+	return true
+}
+
+// validateEstimate accepts an empty estimate, story points or a whole number of minutes.
+func validateEstimate(estimate TaskEstimate) bool {
+	if estimate.Unit == "" {
+		return estimate.Value == 0
+	}
+	if estimate.Value < 0 || math.IsNaN(estimate.Value) || math.IsInf(estimate.Value, 0) {
+		return false
+	}
+	switch estimate.Unit {
+	case estimatePoints:
+		return true
+	case estimateMinutes:
+		return estimate.Value == math.Trunc(estimate.Value)
+	}
+	return false
+}
+
+// estimateTotals sums task estimates per board for tasks matching the filter.
+func estimateTotals(filter bson.D) (map[bson.ObjectID]EstimateTotals, error) {
+	sumIf := func(unit string, onlyOpen bool) bson.D {
+		condition := bson.A{bson.D{{"$eq", bson.A{"$estimate.unit", unit}}}}
+		if onlyOpen {
+			condition = append(condition, bson.D{{"$eq", bson.A{bson.D{{"$ifNull", bson.A{"$completed_at", 0}}}, 0}}})
+		}
+		return bson.D{{"$sum", bson.D{{"$cond", bson.A{bson.D{{"$and", condition}}, "$estimate.value", 0}}}}}
+	}
+	cursor, err := tasksDb.Aggregate(context.TODO(), bson.A{
+		bson.D{{"$match", filter}},
+		bson.D{{"$group", bson.D{
+			{"_id", "$board"},
+			{"points", sumIf(estimatePoints, false)},
+			{"minutes", sumIf(estimateMinutes, false)},
+			{"open_points", sumIf(estimatePoints, true)},
+			{"open_minutes", sumIf(estimateMinutes, true)},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var results []struct {
+		Board          bson.ObjectID `bson:"_id"`
+		EstimateTotals `bson:",inline"`
+	}
+	if err := cursor.All(context.TODO(), &results); err != nil {
+		return nil, err
+	}
+	totals := map[bson.ObjectID]EstimateTotals{}
+	for _, result := range results {
+		totals[result.Board] = result.EstimateTotals
+	}
+	return totals, nil
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	eventTaskCreated    = "task.created"
+	eventTaskUpdated    = "task.updated"
+	eventTaskDeleted    = "task.deleted"
+	eventBoardCreated   = "board.created"
+	eventBoardUpdated   = "board.updated"
+	eventBoardDeleted   = "board.deleted"
+	eventMemberJoined   = "member.joined"
+	eventMemberKicked   = "member.kicked"
+	eventMemberPromoted = "member.promoted"
+)
+
+const (
+	eventsCollectionSize = 64 << 20
+	eventHeartbeat       = 25 * time.Second
+	eventBufferSize      = 64
+	maxEventReplay       = 500
+	streamTicketLifetime = time.Minute
+)
+
+// eventSubscriber is one open event stream of a client.
+type eventSubscriber struct {
+	Workspace bson.ObjectID
+	Board     bson.ObjectID // zero for the whole workspace
+	User      bson.ObjectID
+	Watching  bool // only task and board events of what the user watches
+	Events    chan Event
+	Done      chan struct{} // closed when the stream has to end, e.g. the user was kicked
+}
+
+// eventHub fans events of this instance out to the subscribers connected to it.
+type eventHub struct {
+	mu          sync.Mutex
+	subscribers map[*eventSubscriber]struct{}
+}
+
+var events = &eventHub{subscribers: map[*eventSubscriber]struct{}{}}
+
+// publishEvent stores an event in the capped events collection and queues its webhook deliveries.
+// Every instance tails that collection, so subscribers receive the event no matter which instance
+// they are connected to.
+func publishEvent(event Event) {
+	event.CreatedAt = time.Now().UTC().Unix()
+	if event.Task != nil {
+		if event.BoardId.IsZero() {
+			event.BoardId = event.Task.Board
+		}
+		if watchers, err := taskWatchers(*event.Task); err == nil {
+			event.Watchers = watchers
+		}
+	} else if event.Board != nil {
+		event.Watchers = event.Board.Watchers
+	}
+	result, err := eventsDb.InsertOne(context.TODO(), event)
+	if err != nil {
+		println("WARNING Failed to publish event: ", err.Error())
+		return
+	}
+	event.Id = result.InsertedID.(bson.ObjectID)
+	if err := enqueueWebhookDeliveries(event); err != nil {
+		println("WARNING Failed to queue webhook deliveries: ", err.Error())
+	}
+}
+
+// ensureEventsCollection creates the capped collection events are tailed from.
+func ensureEventsCollection(ctx context.Context) error {
+	err := dbClient.Database("rela").CreateCollection(ctx, "events",
+		options.CreateCollection().SetCapped(true).SetSizeInBytes(eventsCollectionSize))
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorCode(48) {
+		// NamespaceExists
+		return nil
+	}
+	return err
+}
+
+// matches reports whether a subscriber should receive an event. Membership events concern the
+// whole workspace, task and board events only the board they happened on.
+func (s *eventSubscriber) matches(event Event) bool {
+	if event.Workspace != s.Workspace {
+		return false
+	}
+	if strings.HasPrefix(event.Type, "member.") {
+		return true
+	}
+	if s.Watching && !slices.Contains(event.Watchers, s.User) {
+		return false
+	}
+	return s.Board.IsZero() || event.BoardId == s.Board || event.PreviousBoard == s.Board
+}
+
+func (h *eventHub) subscribe(subscriber *eventSubscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subscribers[subscriber] = struct{}{}
+}
+
+func (h *eventHub) unsubscribe(subscriber *eventSubscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.drop(subscriber)
+}
+
+// drop removes a subscriber and ends its stream. The caller holds the lock.
+func (h *eventHub) drop(subscriber *eventSubscriber) {
+	if _, ok := h.subscribers[subscriber]; ok {
+		delete(h.subscribers, subscriber)
+		close(subscriber.Done)
+	}
+}
+
+func (h *eventHub) dispatch(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for subscriber := range h.subscribers {
+		if !subscriber.matches(event) {
+			continue
+		}
+		if event.Type == eventMemberKicked && event.Member != nil && event.Member.Id == subscriber.User {
+			h.drop(subscriber)
+			continue
+		}
+		select {
+		case subscriber.Events <- event:
+		default:
+			// Slow clients are disconnected and can resume with Last-Event-ID
+			h.drop(subscriber)
+		}
+	}
+}
+
+// runEventHub tails the events collection and dispatches new events to local subscribers.
+// A tailable cursor on a capped collection also works on standalone MongoDB, unlike change streams.
+func runEventHub() {
+	var lastId bson.ObjectID
+	var latest Event
+	if err := eventsDb.FindOne(context.TODO(), bson.D{}, options.FindOne().SetSort(bson.D{{"$natural", -1}})).Decode(&latest); err == nil {
+		lastId = latest.Id
+	}
+	for {
+		filter := bson.D{}
+		if !lastId.IsZero() {
+			filter = bson.D{{"_id", bson.D{{"$gt", lastId}}}}
+		}
+		cursor, err := eventsDb.Find(context.TODO(), filter, options.Find().SetCursorType(options.TailableAwait))
+		if err != nil {
+			println("WARNING Failed to tail events: ", err.Error())
+			time.Sleep(time.Second)
+			continue
+		}
+		for cursor.Next(context.TODO()) {
+			var event Event
+			if err := cursor.Decode(&event); err != nil {
+				continue
+			}
+			lastId = event.Id
+			events.dispatch(event)
+		}
+		// The cursor dies when the collection is empty or the connection drops
+		cursor.Close(context.TODO())
+		time.Sleep(time.Second)
+	}
+}
+
+func writeEvent(c *gin.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", event.Id.Hex(), event.Type, data)
+	return err
+}
+
+// streamTicketMiddleware lets clients that can not set headers, like the browser EventSource, open
+// the stream with a ticket from createStreamTicket in the query string. Access tokens never go into
+// the URL, where proxies and server logs would keep them. Without a ticket authMiddleware decides.
+func streamTicketMiddleware() gin.HandlerFunc {
+	auth := authMiddleware()
+	return func(c *gin.Context) {
+		ticket := c.Query("ticket")
+		if ticket == "" || c.GetHeader("Authorization") != "" {
+			auth(c)
+			return
+		}
+		token, err := jwt.ParseWithClaims(ticket, &Token{}, func(token *jwt.Token) (any, error) {
+			if token.Method != jwt.SigningMethodHS256 {
+				return nil, fmt.Errorf("unknown signing method: %s", token.Method)
+			}
+			return []byte(pepper), nil
+		}, jwt.WithExpirationRequired())
+		if err != nil {
+			c.AbortWithStatusJSON(403, gin.H{"error": "invalid or expired stream ticket"})
+			return
+		}
+		// A ticket only opens the stream of the workspace it was issued for
+		claims, ok := token.Claims.(*Token)
+		if !ok || !token.Valid || claims.Type != "stream" || claims.Subject != c.Param("workspaceId") {
+			c.AbortWithStatusJSON(403, gin.H{"error": "invalid or expired stream ticket"})
+			return
+		}
+		c.Set("id", claims.Id)
+		c.Next()
+	}
+}
+
+// @Summary 		Create an event stream ticket
+// @Description 	Returns a ticket that opens the event stream of a workspace for one minute. Clients that can not set the Authorization header, like the browser EventSource, pass it as the ticket query parameter instead of their access token.
+// @Router 			/workspaces/{workspaceId}/events/ticket [post]
+// @Tags 			Events
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Success 		200 {object} StreamTicket "The ticket and when it expires"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - workspace not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func createStreamTicket(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	expiresAt := time.Now().UTC().Add(streamTicketLifetime)
+	claims := Token{
+		Id:   userId.(bson.ObjectID),
+		Type: "stream",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   workspace.Id.Hex(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	ticket, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(pepper))
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(200, StreamTicket{Ticket: ticket, ExpiresAt: expiresAt.Unix()})
+}
+
+// @Summary 		Stream workspace events
+// @Description 	Server-Sent Events stream of task, board and membership changes in a workspace. Pass board to only receive changes of one board. Reconnecting clients send Last-Event-ID to receive the events they missed.
+// @Router 			/workspaces/{workspaceId}/events [get]
+// @Tags 			Events
+// @Security 		BearerAuth
+// @Produce 		text/event-stream
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			board query string false "Only stream events of this board"
+// @Param 			watching query bool false "Only stream task and board events of what you watch"
+// @Param 			ticket query string false "Stream ticket, for clients that can not set the Authorization header"
+// @Success 		200 {object} Event "Stream of events"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid board id"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - workspace not found"
+func streamEvents(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	subscriber := &eventSubscriber{
+		Workspace: workspace.Id,
+		User:      userId.(bson.ObjectID),
+		Watching:  c.Query("watching") == "true",
+		Events:    make(chan Event, eventBufferSize),
+		Done:      make(chan struct{}),
+	}
+	if b := c.Query("board"); b != "" {
+		boardId, err := bson.ObjectIDFromHex(b)
+		if err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": "invalid board"})
+			return
+		}
+		subscriber.Board = boardId
+	}
+	events.subscribe(subscriber)
+	defer events.unsubscribe(subscriber)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Keeps nginx from buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(200)
+
+	// Replay what a reconnecting client missed. Live events that were replayed already are skipped.
+	replayed := map[bson.ObjectID]bool{}
+	if lastEventId, err := bson.ObjectIDFromHex(c.GetHeader("Last-Event-ID")); err == nil {
+		cursor, err := eventsDb.Find(context.TODO(),
+			bson.D{{"workspace", workspace.Id}, {"_id", bson.D{{"$gt", lastEventId}}}},
+			options.Find().SetSort(bson.D{{"_id", 1}}).SetLimit(maxEventReplay),
+		)
+		if err == nil {
+			missed := make([]Event, 0)
+			if err := cursor.All(context.TODO(), &missed); err == nil {
+				for _, event := range missed {
+					if subscriber.matches(event) && writeEvent(c, event) == nil {
+						replayed[event.Id] = true
+					}
+				}
+			}
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(eventHeartbeat)
+	defer heartbeat.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event := <-subscriber.Events:
+			if replayed[event.Id] {
+				return true
+			}
+			return writeEvent(c, event) == nil
+		case <-heartbeat.C:
+			_, err := fmt.Fprint(w, ": ping\n\n")
+			return err == nil
+		case <-subscriber.Done:
+			return false
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
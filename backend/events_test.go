@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func signTestToken(t *testing.T, claims Token) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(pepper))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestStreamTicketMiddleware(t *testing.T) {
+	previous := pepper
+	pepper = "test-pepper"
+	t.Cleanup(func() { pepper = previous })
+	gin.SetMode(gin.TestMode)
+
+	userId := bson.NewObjectID()
+	workspaceId := bson.NewObjectID()
+	ticket := func(workspace bson.ObjectID, kind string, expiresIn time.Duration) string {
+		return signTestToken(t, Token{Id: userId, Type: kind, RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   workspace.Hex(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+		}})
+	}
+	accessToken, err := generateAccessToken(userId.Hex(), "access")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := gin.New()
+	r.GET("/workspaces/:workspaceId/events", streamTicketMiddleware(), func(c *gin.Context) {
+		id, _ := c.Get("id")
+		c.String(200, id.(bson.ObjectID).Hex())
+	})
+
+	tests := []struct {
+		name   string
+		query  string
+		header string
+		want   int
+	}{
+		{"ticket of the workspace", "?ticket=" + ticket(workspaceId, "stream", time.Minute), "", 200},
+		{"ticket of another workspace", "?ticket=" + ticket(bson.NewObjectID(), "stream", time.Minute), "", 403},
+		{"expired ticket", "?ticket=" + ticket(workspaceId, "stream", -time.Minute), "", 403},
+		{"access token as ticket", "?ticket=" + accessToken, "", 403},
+		{"access token in the query", "?access_token=" + accessToken, "", 403},
+		{"access token in the header", "", "Bearer " + accessToken, 200},
+		{"ticket in the header", "", "Bearer " + ticket(workspaceId, "stream", time.Minute), 400},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/workspaces/"+workspaceId.Hex()+"/events"+test.query, nil)
+			if test.header != "" {
+				request.Header.Set("Authorization", test.header)
+			}
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, request)
+			if recorder.Code != test.want {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, test.want, recorder.Body.String())
+			}
+			if test.want == 200 && recorder.Body.String() != userId.Hex() {
+				t.Errorf("id = %s, want %s", recorder.Body.String(), userId.Hex())
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	feedIcal = "ical"
+	feedAtom = "atom"
+)
+
+const (
+	feedScopeAssigned  = "assigned"
+	feedScopeWorkspace = "workspace"
+	feedScopeBoard     = "board"
+)
+
+const (
+	icalEvent = "event"
+	icalTodo  = "todo"
+)
+
+var feedScopes = map[string][]string{
+	feedIcal: {feedScopeAssigned, feedScopeWorkspace, feedScopeBoard},
+	feedAtom: {feedScopeWorkspace, feedScopeBoard},
+}
+
+// generateToken returns a new secret for a feed URL or app password and the hash that is stored.
+func generateToken() (string, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func feedUrl(feed FeedToken, token string) string {
+	switch feed.Kind {
+	case feedIcal:
+		return apiUrl("/ical/" + token + ".ics")
+	case feedAtom:
+		return apiUrl("/atom/" + token + ".xml")
+	}
+	return ""
+}
+
+// memberWorkspace returns a workspace if the user is one of its members.
+func memberWorkspace(workspaceId bson.ObjectID, userId bson.ObjectID) (Workspace, error) {
+	var workspace Workspace
+	if err := workspacesDb.FindOne(context.TODO(), bson.D{{"_id", workspaceId}}).Decode(&workspace); err != nil {
+		return workspace, err
+	}
+	if !slices.Contains(workspace.Members, userId) && workspace.OwnedBy != userId {
+		return workspace, mongo.ErrNoDocuments
+	}
+	return workspace, nil
+}
+
+// resolveFeed finds the feed of a token and checks its owner can still read what it covers.
+func resolveFeed(token string, kind string) (FeedToken, Workspace, bool) {
+	var feed FeedToken
+	var workspace Workspace
+	if err := feedsDb.FindOne(context.TODO(), bson.D{{"token_hash", hashToken(token)}, {"kind", kind}}).Decode(&feed); err != nil {
+		return feed, workspace, false
+	}
+	if feed.Scope != feedScopeAssigned {
+		var err error
+		if workspace, err = memberWorkspace(feed.Workspace, feed.User); err != nil {
+			return feed, workspace, false
+		}
+	}
+	if feed.Scope == feedScopeBoard {
+		if err := boardsDb.FindOne(context.TODO(), bson.D{{"_id", feed.Board}, {"owned_by", feed.Workspace}}).Err(); err != nil {
+			return feed, workspace, false
+		}
+	}
+	if _, err := feedsDb.UpdateOne(context.TODO(), bson.D{{"_id", feed.Id}}, bson.D{{"$set", bson.D{{"last_used_at", time.Now().UTC().Unix()}}}}); err != nil {
+		println("WARNING Failed to update feed ", feed.Id.Hex(), ": ", err.Error())
+	}
+	return feed, workspace, true
+}
+
+func findFeed(c *gin.Context) (FeedToken, bool) {
+	userId, _ := c.Get("id")
+	var feed FeedToken
+	feedId, err := bson.ObjectIDFromHex(c.Param("feedId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid feedId"})
+		return feed, false
+	}
+	err = feedsDb.FindOne(context.TODO(), bson.D{{"_id", feedId}, {"user", userId}}).Decode(&feed)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Feed does not exist"})
+		return feed, false
+	} else if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return feed, false
+	}
+	return feed, true
+}
+
+// @Summary 		Get your feeds
+// @Description 	Returns the secret feed URLs you created, without their tokens.
+// @Router 			/feeds [get]
+// @Tags 			Feeds
+// @Security 		BearerAuth
+// @Produce 		json
+// @Success 		200 {object} AllFeedTokensResponse "A list of feeds"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func getAllFeeds(c *gin.Context) {
+	userId, _ := c.Get("id")
+	cursor, err := feedsDb.Find(context.TODO(), bson.D{{"user", userId}})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	feeds := make([]FeedToken, 0)
+	if err := cursor.All(context.TODO(), &feeds); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(200, gin.H{"feeds": feeds})
+}
+
+// @Summary 		Create a feed
+// @Description 	Creates a secret URL that calendar apps and feed readers can subscribe to without a bearer header. An iCal feed covers the tasks assigned to you in all your workspaces, a whole workspace or one board, with a VEVENT or a VTODO for every task with a deadline. An Atom feed lists the latest task activity of a workspace or one board. The URL is only returned here and when regenerating the token.
+// @Router 			/feeds [post]
+// @Tags 			Feeds
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			data body CreateFeedToken true "Kind ical or atom, scope assigned (ical only), workspace or board, the workspace and board it covers and the component, event or todo"
+// @Success 		200 {object} FeedToken "The created feed including its URL"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid kind, scope, workspace or board"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func createFeed(c *gin.Context) {
+	userId, _ := c.Get("id")
+	var input CreateFeedToken
+	if err := json.NewDecoder(c.Request.Body).Decode(&input); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return
+	}
+	if input.Kind == "" {
+		input.Kind = feedIcal
+	}
+	scopes, ok := feedScopes[input.Kind]
+	if !ok {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'kind' must be ical or atom"})
+		return
+	} else if !slices.Contains(scopes, input.Scope) {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'scope' is not supported by this kind of feed"})
+		return
+	}
+	feed := FeedToken{
+		User:      userId.(bson.ObjectID),
+		Kind:      input.Kind,
+		Scope:     input.Scope,
+		CreatedAt: time.Now().UTC().Unix(),
+	}
+	if input.Kind == feedIcal {
+		feed.Component = icalEvent
+		if input.Component == icalTodo {
+			feed.Component = icalTodo
+		} else if input.Component != "" && input.Component != icalEvent {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Field 'component' must be event or todo"})
+			return
+		}
+	}
+	if input.Scope != feedScopeAssigned {
+		_, err := memberWorkspace(input.Workspace, feed.User)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Field 'workspace' must be a workspace you are a member of"})
+			return
+		} else if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		feed.Workspace = input.Workspace
+	}
+	if input.Scope == feedScopeBoard {
+		err := boardsDb.FindOne(context.TODO(), bson.D{{"_id", input.Board}, {"owned_by", input.Workspace}}).Err()
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Field 'board' must be a board of the workspace"})
+			return
+		} else if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		feed.Board = input.Board
+	}
+
+	token, hash, err := generateToken()
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	feed.TokenHash = hash
+	result, err := feedsDb.InsertOne(context.TODO(), feed)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to create feed"})
+		return
+	}
+	feed.Id = result.InsertedID.(bson.ObjectID)
+	feed.Url = feedUrl(feed, token)
+	c.JSON(200, feed)
+}
+
+// @Summary 		Regenerate a feed token
+// @Description 	Replaces the secret of a feed. The old URL stops working right away, subscribers need the returned one.
+// @Router 			/feeds/{feedId}/token [post]
+// @Tags 			Feeds
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			feedId path string true "Feed ID"
+// @Success 		200 {object} FeedToken "The feed including its new URL"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid feed id"
+// @Failure 		404 {object} ErrorSwagger "Not Found - feed not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func regenerateFeedToken(c *gin.Context) {
+	feed, ok := findFeed(c)
+	if !ok {
+		return
+	}
+	token, hash, err := generateToken()
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	if _, err := feedsDb.UpdateOne(context.TODO(), bson.D{{"_id", feed.Id}}, bson.D{{"$set", bson.D{{"token_hash", hash}}}}); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to regenerate token"})
+		return
+	}
+	feed.TokenHash = hash
+	feed.Url = feedUrl(feed, token)
+	c.JSON(200, feed)
+}
+
+// @Summary 		Revoke a feed
+// @Description 	Deletes a feed, its URL stops working.
+// @Router 			/feeds/{feedId} [delete]
+// @Tags 			Feeds
+// @Security 		BearerAuth
+// @Param 			feedId path string true "Feed ID"
+// @Success 		200 "Feed revoked"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid feed id"
+// @Failure 		404 {object} ErrorSwagger "Not Found - feed not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func deleteFeed(c *gin.Context) {
+	feed, ok := findFeed(c)
+	if !ok {
+		return
+	}
+	if _, err := feedsDb.DeleteOne(context.TODO(), bson.D{{"_id", feed.Id}}); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to delete feed"})
+		return
+	}
+	c.AbortWithStatus(200)
+}
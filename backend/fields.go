@@ -0,0 +1,411 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	fieldText         = "text"
+	fieldNumber       = "number"
+	fieldDate         = "date"
+	fieldSingleSelect = "single_select"
+	fieldMultiSelect  = "multi_select"
+	fieldUser         = "user"
+	fieldUrl          = "url"
+)
+
+var customFieldTypes = []string{fieldText, fieldNumber, fieldDate, fieldSingleSelect, fieldMultiSelect, fieldUser, fieldUrl}
+
+// errInvalidCustomField marks validation errors that should be reported to the client as 400.
+var errInvalidCustomField = errors.New("invalid custom field")
+
+func invalidField(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", errInvalidCustomField, fmt.Sprintf(format, args...))
+}
+
+func loadCustomFields(workspaceId bson.ObjectID) (map[string]CustomField, error) {
+	cursor, err := customFieldsDb.Find(context.TODO(), bson.D{{"workspace", workspaceId}})
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]CustomField, 0)
+	if err := cursor.All(context.TODO(), &fields); err != nil {
+		return nil, err
+	}
+	byId := make(map[string]CustomField, len(fields))
+	for _, field := range fields {
+		byId[field.Id.Hex()] = field
+	}
+	return byId, nil
+}
+
+// normalizeCustomFieldValue validates a decoded JSON value against the field schema
+// and converts it to the representation stored in MongoDB.
+func normalizeCustomFieldValue(field CustomField, value any, members []bson.ObjectID) (any, error) {
+	switch field.Type {
+	case fieldText:
+		text, ok := value.(string)
+		if !ok {
+			return nil, invalidField("field '%s' must be a string", field.Name)
+		}
+		return text, nil
+	case fieldNumber:
+		number, ok := value.(float64)
+		if !ok || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, invalidField("field '%s' must be a number", field.Name)
+		}
+		return number, nil
+	case fieldDate:
+		date, ok := value.(float64)
+		if !ok || date != math.Trunc(date) {
+			return nil, invalidField("field '%s' must be a unix timestamp", field.Name)
+		}
+		return int64(date), nil
+	case fieldSingleSelect:
+		option, ok := value.(string)
+		if !ok || !slices.Contains(field.Options, option) {
+			return nil, invalidField("field '%s' must be one of its options", field.Name)
+		}
+		return option, nil
+	case fieldMultiSelect:
+		values, ok := value.([]any)
+		if !ok {
+			return nil, invalidField("field '%s' must be a list of options", field.Name)
+		}
+		options := make([]string, 0, len(values))
+		for _, v := range values {
+			option, ok := v.(string)
+			if !ok || !slices.Contains(field.Options, option) {
+				return nil, invalidField("field '%s' must only contain its options", field.Name)
+			}
+			if !slices.Contains(options, option) {
+				options = append(options, option)
+			}
+		}
+		return options, nil
+	case fieldUser:
+		hex, ok := value.(string)
+		if !ok {
+			return nil, invalidField("field '%s' must be a user id", field.Name)
+		}
+		userId, err := bson.ObjectIDFromHex(hex)
+		if err != nil || !slices.Contains(members, userId) {
+			return nil, invalidField("field '%s' must be a member of this workspace", field.Name)
+		}
+		return userId, nil
+	case fieldUrl:
+		raw, ok := value.(string)
+		if !ok {
+			return nil, invalidField("field '%s' must be a URL", field.Name)
+		}
+		parsed, err := url.Parse(raw)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, invalidField("field '%s' must be an http or https URL", field.Name)
+		}
+		return raw, nil
+	}
+	return nil, invalidField("field '%s' has unknown type", field.Name)
+}
+
+// applyCustomFields validates values against the workspace schema and writes them into the task.
+// A null value removes the field from the task.
+func applyCustomFields(task *Task, values map[string]any) error {
+	if len(values) == 0 {
+		return nil
+	}
+	fields, err := loadCustomFields(task.CreatedBy)
+	if err != nil {
+		return err
+	}
+	var workspace Workspace
+	if err := workspacesDb.FindOne(context.TODO(), bson.D{{"_id", task.CreatedBy}}).Decode(&workspace); err != nil {
+		return err
+	}
+	if task.CustomFields == nil {
+		task.CustomFields = map[string]any{}
+	}
+	for fieldId, value := range values {
+		field, ok := fields[fieldId]
+		if !ok {
+			return invalidField("unknown custom field %s", fieldId)
+		}
+		if value == nil {
+			delete(task.CustomFields, fieldId)
+			continue
+		}
+		normalized, err := normalizeCustomFieldValue(field, value, workspace.Members)
+		if err != nil {
+			return err
+		}
+		task.CustomFields[fieldId] = normalized
+	}
+	return nil
+}
+
+// customFieldFilter turns cf[<fieldId>]=<value> query parameters into a MongoDB filter.
+// Multi-select fields match tasks that contain the value.
+func customFieldFilter(workspaceId bson.ObjectID, raw map[string]string) (bson.D, error) {
+	filter := bson.D{}
+	if len(raw) == 0 {
+		return filter, nil
+	}
+	fields, err := loadCustomFields(workspaceId)
+	if err != nil {
+		return nil, err
+	}
+	for fieldId, rawValue := range raw {
+		field, ok := fields[fieldId]
+		if !ok {
+			return nil, invalidField("unknown custom field %s", fieldId)
+		}
+		var value any = rawValue
+		switch field.Type {
+		case fieldNumber:
+			number, err := strconv.ParseFloat(rawValue, 64)
+			if err != nil {
+				return nil, invalidField("field '%s' must be a number", field.Name)
+			}
+			value = number
+		case fieldDate:
+			date, err := strconv.ParseInt(rawValue, 10, 64)
+			if err != nil {
+				return nil, invalidField("field '%s' must be a unix timestamp", field.Name)
+			}
+			value = date
+		case fieldUser:
+			userId, err := bson.ObjectIDFromHex(rawValue)
+			if err != nil {
+				return nil, invalidField("field '%s' must be a user id", field.Name)
+			}
+			value = userId
+		}
+		filter = append(filter, bson.E{"custom_fields." + fieldId, value})
+	}
+	return filter, nil
+}
+
+func validateFieldOptions(fieldType string, options []string) bool {
+	if fieldType != fieldSingleSelect && fieldType != fieldMultiSelect {
+		return len(options) == 0
+	}
+	if len(options) == 0 {
+		return false
+	}
+	for i, option := range options {
+		if option == "" || slices.Index(options, option) != i {
+			return false
+		}
+	}
+	return true
+}
+
+func authorizeWorkspaceOwner(c *gin.Context) (Workspace, bool) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return workspace, false
+	}
+	userId, _ := c.Get("id")
+	if workspace.OwnedBy != userId.(bson.ObjectID) {
+		c.AbortWithStatusJSON(403, gin.H{"error": "You are not an owner of this workplace"})
+		return workspace, false
+	}
+	return workspace, true
+}
+
+// @Summary 		Get all custom fields
+// @Description 	Returns the custom field schemas defined in a workspace.
+// @Router 			/workspaces/{workspaceId}/fields [get]
+// @Tags 			Custom Fields
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Success 		200 {object} AllCustomFieldsResponse "A list of custom fields"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		500 {object} ErrorSwagger "Internal Server Error"
+func getAllCustomFields(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	cursor, err := customFieldsDb.Find(context.TODO(), bson.D{{"workspace", workspace.Id}})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	fields := make([]CustomField, 0)
+	if err := cursor.All(context.TODO(), &fields); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "failed to decode custom fields"})
+		return
+	}
+	c.JSON(200, gin.H{"fields": fields})
+}
+
+// @Summary 		Create a custom field
+// @Description 	Defines a new custom field for tasks of a workspace. Only the workspace owner can do this.
+// @Router 			/workspaces/{workspaceId}/fields [post]
+// @Tags 			Custom Fields
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			data body CreateCustomField true "Custom field schema"
+// @Success 		200 {object} CustomField "The created custom field"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid name, type or options"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not the owner of this workspace"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func createCustomField(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceOwner(c)
+	if !ok {
+		return
+	}
+	var input CreateCustomField
+	if err := json.NewDecoder(c.Request.Body).Decode(&input); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return
+	} else if input.Name == "" {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'name' is not specified"})
+		return
+	} else if !slices.Contains(customFieldTypes, input.Type) {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'type' must be one of text, number, date, single_select, multi_select, user, url"})
+		return
+	} else if !validateFieldOptions(input.Type, input.Options) {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Select fields need unique non-empty options, other fields take none"})
+		return
+	}
+	field := CustomField{
+		Name:      input.Name,
+		Type:      input.Type,
+		Options:   input.Options,
+		Workspace: workspace.Id,
+	}
+	result, err := customFieldsDb.InsertOne(context.TODO(), field)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to create custom field"})
+		return
+	}
+	field.Id = result.InsertedID.(bson.ObjectID)
+	c.JSON(200, field)
+}
+
+// @Summary 		Edit a custom field
+// @Description 	Renames a custom field or changes its options. Options that are removed are also removed from every task. The type of a field can not be changed.
+// @Router 			/workspaces/{workspaceId}/fields/{fieldId} [patch]
+// @Tags 			Custom Fields
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			fieldId path string true "Custom field ID"
+// @Param 			data body EditCustomField true "Fields to edit in the custom field"
+// @Success 		200 {object} CustomField "The updated custom field"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid input"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not the owner of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - custom field not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func editCustomField(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceOwner(c)
+	if !ok {
+		return
+	}
+	fieldId, err := bson.ObjectIDFromHex(c.Param("fieldId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid fieldId"})
+		return
+	}
+	var valuesToEdit EditCustomField
+	if err := json.NewDecoder(c.Request.Body).Decode(&valuesToEdit); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return
+	}
+	var field CustomField
+	if err := customFieldsDb.FindOne(context.TODO(), bson.D{{"_id", fieldId}, {"workspace", workspace.Id}}).Decode(&field); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.AbortWithStatusJSON(404, gin.H{"error": "Custom field does not exist"})
+		} else {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		}
+		return
+	}
+	if valuesToEdit.Name != "" {
+		field.Name = valuesToEdit.Name
+	}
+	removed := make([]string, 0)
+	if valuesToEdit.Options != nil {
+		if !validateFieldOptions(field.Type, valuesToEdit.Options) {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Select fields need unique non-empty options, other fields take none"})
+			return
+		}
+		for _, option := range field.Options {
+			if !slices.Contains(valuesToEdit.Options, option) {
+				removed = append(removed, option)
+			}
+		}
+		field.Options = valuesToEdit.Options
+	}
+	if _, err := customFieldsDb.ReplaceOne(context.TODO(), bson.D{{"_id", fieldId}}, field); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to update custom field"})
+		return
+	}
+	if len(removed) > 0 {
+		key := "custom_fields." + fieldId.Hex()
+		filter := bson.D{{"created_by", workspace.Id}, {key, bson.D{{"$in", removed}}}}
+		update := bson.D{{"$unset", bson.D{{key, ""}}}}
+		if field.Type == fieldMultiSelect {
+			update = bson.D{{"$pull", bson.D{{key, bson.D{{"$in", removed}}}}}}
+		}
+		if _, err := tasksDb.UpdateMany(context.TODO(), filter, update); err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Failed to remove options from tasks"})
+			return
+		}
+	}
+	c.JSON(200, field)
+}
+
+// @Summary 		Delete a custom field
+// @Description 	Deletes a custom field and removes its values from every task.
+// @Router 			/workspaces/{workspaceId}/fields/{fieldId} [delete]
+// @Tags 			Custom Fields
+// @Security 		BearerAuth
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			fieldId path string true "Custom field ID"
+// @Success 		200 "Custom field deleted successfully"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid field id"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not the owner of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - custom field not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func deleteCustomField(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceOwner(c)
+	if !ok {
+		return
+	}
+	fieldId, err := bson.ObjectIDFromHex(c.Param("fieldId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid fieldId"})
+		return
+	}
+	result, err := customFieldsDb.DeleteOne(context.TODO(), bson.D{{"_id", fieldId}, {"workspace", workspace.Id}})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to delete custom field"})
+		return
+	} else if result.DeletedCount == 0 {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Custom field does not exist"})
+		return
+	}
+	key := "custom_fields." + fieldId.Hex()
+	if _, err := tasksDb.UpdateMany(context.TODO(), bson.D{{"created_by", workspace.Id}, {key, bson.D{{"$exists", true}}}}, bson.D{{"$unset", bson.D{{key, ""}}}}); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to remove custom field from tasks"})
+		return
+	}
+	c.AbortWithStatus(200)
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var githubIssueUrlRegex = regexp.MustCompile(`github\.com/(?:repos/)?([^/]+/[^/]+)/issues/(\d+)`)
+var githubRepositoryUrlRegex = regexp.MustCompile(`github\.com/repos/([^/]+/[^/]+)/?$`)
+var githubRepositoryRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
+type githubUser struct {
+	Login string `json:"login"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// githubIssue accepts both the output of "gh issue list --json" and issues from the REST API,
+// which name some fields differently.
+type githubIssue struct {
+	Number        int              `json:"number"`
+	Title         string           `json:"title"`
+	Body          string           `json:"body"`
+	State         string           `json:"state"`
+	Url           string           `json:"url"`
+	HtmlUrl       string           `json:"html_url"`
+	RepositoryUrl string           `json:"repository_url"`
+	Labels        []githubLabel    `json:"labels"`
+	Assignees     []githubUser     `json:"assignees"`
+	Comments      json.RawMessage  `json:"comments"`
+	Milestone     *githubMilestone `json:"milestone"`
+	CreatedAt     string           `json:"createdAt"`
+	CreatedAtRest string           `json:"created_at"`
+	ClosedAt      string           `json:"closedAt"`
+	ClosedAtRest  string           `json:"closed_at"`
+	PullRequest   json.RawMessage  `json:"pull_request"`
+}
+
+type githubLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type githubMilestone struct {
+	Title     string `json:"title"`
+	DueOn     string `json:"dueOn"`
+	DueOnRest string `json:"due_on"`
+}
+
+type githubComment struct {
+	Author        githubUser `json:"author"`
+	User          githubUser `json:"user"`
+	Body          string     `json:"body"`
+	CreatedAt     string     `json:"createdAt"`
+	CreatedAtRest string     `json:"created_at"`
+}
+
+func parseGithubTime(values ...string) int64 {
+	for _, value := range values {
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			return parsed.Unix()
+		}
+	}
+	return 0
+}
+
+// source returns "owner/repo" of an issue and its external id. Issues without a url belong to the
+// fallback repository, without one they have no source, since numbers are only unique per repository.
+func (issue githubIssue) source(fallback string) (string, string, bool) {
+	for _, url := range []string{issue.HtmlUrl, issue.Url} {
+		if match := githubIssueUrlRegex.FindStringSubmatch(url); match != nil {
+			return match[1], "github:" + match[1] + "#" + match[2], true
+		}
+	}
+	repository := fallback
+	if match := githubRepositoryUrlRegex.FindStringSubmatch(issue.RepositoryUrl); match != nil {
+		repository = match[1]
+	}
+	if repository == "" {
+		return "", "", false
+	}
+	return repository, fmt.Sprintf("github:%s#%d", repository, issue.Number), true
+}
+
+// importGithub maps GitHub issues onto tasks, on the given board or on a board per repository.
+func importGithub(issues []githubIssue, fallbackRepository string, target *importTarget, boardId bson.ObjectID, runner *jobRunner) error {
+	runner.setTotal(len(issues))
+	now := time.Now().UTC().Unix()
+	tasks := make([]Task, 0, len(issues))
+	pullRequests, commentCounts := 0, 0
+	for _, issue := range issues {
+		if len(issue.PullRequest) != 0 && string(issue.PullRequest) != "null" {
+			pullRequests++
+			runner.advance(1)
+			continue
+		}
+		repository, externalId, _ := issue.source(fallbackRepository)
+		board := boardId
+		if board.IsZero() {
+			name := "GitHub issues"
+			if _, repo, found := strings.Cut(repository, "/"); found {
+				name = repo
+			}
+			board = target.pendingBoard(name)
+		}
+
+		comments := make([]importedComment, 0)
+		if bytes.HasPrefix(bytes.TrimSpace(issue.Comments), []byte("[")) {
+			var githubComments []githubComment
+			if err := json.Unmarshal(issue.Comments, &githubComments); err == nil {
+				for _, comment := range githubComments {
+					author := comment.Author.Login
+					if author == "" {
+						author = comment.User.Login
+					}
+					comments = append(comments, importedComment{Author: author, Date: parseGithubTime(comment.CreatedAt, comment.CreatedAtRest), Text: comment.Body})
+				}
+			}
+		} else if len(issue.Comments) != 0 && string(issue.Comments) != "0" && string(issue.Comments) != "null" {
+			// The REST API only has the number of comments
+			commentCounts++
+		}
+		sections := make([]string, 0)
+		if issue.Body != "" {
+			sections = append(sections, issue.Body)
+		}
+		link := issue.HtmlUrl
+		if link == "" {
+			link = issue.Url
+		}
+
+		task := Task{
+			Name:        issue.Title,
+			Description: importedDescription(sections, comments, link),
+			Board:       board,
+			CreatedAt:   parseGithubTime(issue.CreatedAt, issue.CreatedAtRest),
+			ExternalId:  externalId,
+		}
+		if task.CreatedAt == 0 {
+			task.CreatedAt = now
+		}
+		if strings.EqualFold(issue.State, "closed") {
+			task.CompletedAt = parseGithubTime(issue.ClosedAt, issue.ClosedAtRest)
+			if task.CompletedAt == 0 {
+				task.CompletedAt = now
+			}
+		}
+		labels := slices.Clone(issue.Labels)
+		if issue.Milestone != nil {
+			// Milestones carry the due date and are kept as a label
+			task.Deadline = parseGithubTime(issue.Milestone.DueOn, issue.Milestone.DueOnRest)
+			if issue.Milestone.Title != "" {
+				labels = append(labels, githubLabel{Name: issue.Milestone.Title})
+			}
+		}
+		for _, label := range labels {
+			if label.Name == "" {
+				continue
+			}
+			color := ""
+			if label.Color != "" {
+				color = "#" + strings.ToLower(label.Color)
+			}
+			labelId := target.pendingLabel(label.Name, color)
+			if !slices.Contains(task.Labels, labelId) {
+				task.Labels = append(task.Labels, labelId)
+			}
+		}
+		for _, assignee := range issue.Assignees {
+			if userId, ok := target.assignee(runner, assignee.Email, assignee.Name, assignee.Login); ok && !slices.Contains(task.Assignees, userId) {
+				task.Assignees = append(task.Assignees, userId)
+			}
+		}
+		tasks = append(tasks, task)
+	}
+	if pullRequests != 0 {
+		runner.warn("%d pull requests were not imported", pullRequests)
+	}
+	if commentCounts != 0 {
+		runner.warn("Comments of %d issues were not imported, the dump only has their number", commentCounts)
+	}
+	created, skipped, err := target.insertTasks(tasks, runner)
+	if err != nil {
+		return err
+	}
+	runner.setResult("tasks_created", created)
+	runner.setResult("tasks_skipped", skipped)
+	return nil
+}
+
+// @Summary 		Import GitHub issues
+// @Description 	Starts a background job importing a JSON list of GitHub issues, like the output of gh issue list --json number,title,body,state,url,labels,assignees,comments,milestone,createdAt,closedAt or issues from the REST API. Issues become tasks on a board per repository with labels, assignees matched by email or name, comments added to the description and the milestone due date as deadline. Importing a file again skips issues that were imported before.
+// @Router 			/workspaces/{workspaceId}/import/github [post]
+// @Tags 			Import and export
+// @Security 		BearerAuth
+// @Accept 			multipart/form-data
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			file formData file true "JSON array of GitHub issues"
+// @Param 			board query string false "Put all issues on this board instead of a board per repository"
+// @Param 			repository query string false "owner/repo the issues belong to, required if they have no url"
+// @Success 		202 {object} Job "The started import job"
+// @Failure 		400 {object} ErrorSwagger "Bad request - missing file, invalid board or repository, or not a list of issues"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - workspace not found"
+// @Failure 		413 {object} ErrorSwagger "The file is too large"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func importGithubIssues(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	var boardId bson.ObjectID
+	if b := c.Query("board"); b != "" {
+		var err error
+		if boardId, err = bson.ObjectIDFromHex(b); err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": "invalid board"})
+			return
+		}
+		err = boardsDb.FindOne(context.TODO(), bson.D{{"_id", boardId}, {"owned_by", workspace.Id}}).Err()
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Board does not exist"})
+			return
+		} else if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		}
+	}
+	repository := c.Query("repository")
+	if repository != "" && !githubRepositoryRegex.MatchString(repository) {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'repository' must look like owner/repo"})
+		return
+	}
+	data, ok := readImportFile(c)
+	if !ok {
+		return
+	}
+	var issues []githubIssue
+	if err := json.Unmarshal(data, &issues); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "The file is not a JSON list of GitHub issues"})
+		return
+	}
+	for _, issue := range issues {
+		if _, _, ok := issue.source(repository); !ok {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Issues without a url need the 'repository' query parameter"})
+			return
+		}
+	}
+
+	job, err := startJob(Job{Type: "import.github", Workspace: workspace.Id, CreatedBy: userId.(bson.ObjectID)}, func(runner *jobRunner) error {
+		target, err := newImportTarget(workspace, userId.(bson.ObjectID))
+		if err != nil {
+			return err
+		}
+		return importGithub(issues, repository, target, boardId, runner)
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to start import"})
+		return
+	}
+	c.JSON(202, job)
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	// Completed tasks drop out of feeds once their deadline is this far in the past
+	icalCompletedLookback = 90 * 24 * time.Hour
+	maxIcalTasks          = 2000
+	icalLineLength        = 75
+)
+
+// Priorities are 1 (highest) to 9 (lowest) in iCalendar, 0 is undefined
+var icalPriorities = map[string]int{"urgent": 1, "high": 3, "medium": 5, "low": 9}
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "")
+
+func icalText(value string) string {
+	return icalEscaper.Replace(value)
+}
+
+func icalTime(timestamp int64) string {
+	return time.Unix(timestamp, 0).UTC().Format("20060102T150405Z")
+}
+
+// icalWriter writes content lines, folded after 75 octets without splitting characters.
+type icalWriter struct {
+	strings.Builder
+}
+
+func (w *icalWriter) line(name string, value string) {
+	line := name + ":" + value
+	limit := icalLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space of a continuation line counts too
+		limit = icalLineLength - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+func newIcalCalendar(name string) *icalWriter {
+	w := &icalWriter{}
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", "-//Rela//Rela//EN")
+	w.line("CALSCALE", "GREGORIAN")
+	if name != "" {
+		w.line("X-WR-CALNAME", icalText(name))
+	}
+	return w
+}
+
+// task writes a task as a VEVENT at its deadline or as a VTODO due then.
+func (w *icalWriter) task(task Task, component string, boardName string, stamp int64) {
+	link := taskWebUrl(task)
+	summary := task.Name
+	if task.Key != "" {
+		summary = task.Key + " " + task.Name
+	}
+	description := link
+	if task.Description != "" {
+		description = task.Description + "\n\n" + link
+	}
+
+	name := "VEVENT"
+	if component == icalTodo {
+		name = "VTODO"
+	}
+	w.line("BEGIN", name)
+	w.line("UID", task.Id.Hex()+"@rela")
+	w.line("DTSTAMP", icalTime(stamp))
+	if task.CreatedAt != 0 {
+		w.line("CREATED", icalTime(task.CreatedAt))
+	}
+	w.line("SUMMARY", icalText(summary))
+	w.line("DESCRIPTION", icalText(description))
+	w.line("URL;VALUE=URI", link)
+	if boardName != "" {
+		w.line("CATEGORIES", icalText(boardName))
+	}
+	if component == icalTodo {
+		w.todoState(task)
+	} else {
+		w.line("DTSTART", icalTime(task.Deadline))
+		w.line("TRANSP", "TRANSPARENT")
+	}
+	w.line("END", name)
+}
+
+// todoState writes the due date, priority and status of a VTODO.
+func (w *icalWriter) todoState(task Task) {
+	if task.Deadline != 0 {
+		w.line("DUE", icalTime(task.Deadline))
+	}
+	if priority, ok := icalPriorities[task.Priority]; ok {
+		w.line("PRIORITY", strconv.Itoa(priority))
+	}
+	if task.CompletedAt != 0 {
+		w.line("STATUS", "COMPLETED")
+		w.line("COMPLETED", icalTime(task.CompletedAt))
+	} else {
+		w.line("STATUS", "NEEDS-ACTION")
+	}
+}
+
+// todo writes a task as the VTODO CalDAV clients edit. Unlike feeds the summary is only the name and
+// the description has no link appended, so they round-trip unchanged.
+func (w *icalWriter) todo(task Task) {
+	w.line("BEGIN", "VTODO")
+	w.line("UID", icalUid(task))
+	// Tasks have no modification time, a fixed stamp keeps the ETag stable
+	w.line("DTSTAMP", icalTime(task.CreatedAt))
+	w.line("CREATED", icalTime(task.CreatedAt))
+	w.line("SUMMARY", icalText(task.Name))
+	if task.Description != "" {
+		w.line("DESCRIPTION", icalText(task.Description))
+	}
+	w.line("URL;VALUE=URI", taskWebUrl(task))
+	w.todoState(task)
+	w.line("END", "VTODO")
+}
+
+func icalUid(task Task) string {
+	if task.IcalUid != "" {
+		return task.IcalUid
+	}
+	return task.Id.Hex() + "@rela"
+}
+
+func (w *icalWriter) end() string {
+	w.line("END", "VCALENDAR")
+	return w.String()
+}
+
+func boardNames(boardIds []bson.ObjectID) (map[bson.ObjectID]string, error) {
+	cursor, err := boardsDb.Find(context.TODO(), bson.D{{"_id", bson.D{{"$in", boardIds}}}}, options.Find().SetProjection(bson.D{{"_id", 1}, {"name", 1}}))
+	if err != nil {
+		return nil, err
+	}
+	boards := make([]Board, 0)
+	if err := cursor.All(context.TODO(), &boards); err != nil {
+		return nil, err
+	}
+	names := make(map[bson.ObjectID]string, len(boards))
+	for _, board := range boards {
+		names[board.Id] = board.Name
+	}
+	return names, nil
+}
+
+// @Summary 		Get an iCal feed
+// @Description 	Returns the tasks with a deadline covered by a feed as an RFC 5545 calendar. The secret token in the URL replaces the bearer header, see POST /feeds. Completed tasks are left out once their deadline is 90 days past.
+// @Router 			/ical/{token} [get]
+// @Tags 			Feeds
+// @Produce 		text/calendar
+// @Param 			token path string true "Feed token, optionally followed by .ics"
+// @Success 		200 {string} string "The calendar"
+// @Failure 		404 {object} ErrorSwagger "Not Found - unknown or revoked feed"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func getIcalFeed(c *gin.Context) {
+	feed, workspace, ok := resolveFeed(strings.TrimSuffix(c.Param("token"), ".ics"), feedIcal)
+	if !ok {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Feed does not exist"})
+		return
+	}
+	now := time.Now().UTC()
+	filter := bson.D{
+		{"deadline", bson.D{{"$ne", 0}}},
+		{"$or", bson.A{
+			bson.D{{"completed_at", bson.D{{"$in", bson.A{0, nil}}}}},
+			bson.D{{"deadline", bson.D{{"$gte", now.Add(-icalCompletedLookback).Unix()}}}},
+		}},
+	}
+	name := workspace.Name
+	switch feed.Scope {
+	case feedScopeAssigned:
+		workspaceIds, err := distinctIds(workspacesDb, bson.D{{"$or", bson.A{bson.D{{"members", feed.User}}, bson.D{{"owned_by", feed.User}}}}})
+		if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		filter = append(filter, bson.E{"created_by", bson.D{{"$in", workspaceIds}}}, bson.E{"assignees", feed.User})
+		name = "Assigned to me"
+	case feedScopeWorkspace:
+		filter = append(filter, bson.E{"created_by", workspace.Id})
+	case feedScopeBoard:
+		filter = append(filter, bson.E{"created_by", workspace.Id}, bson.E{"board", feed.Board})
+	}
+
+	cursor, err := tasksDb.Find(context.TODO(), filter, options.Find().SetSort(bson.D{{"deadline", 1}, {"_id", 1}}).SetLimit(maxIcalTasks))
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	tasks := make([]Task, 0)
+	if err := cursor.All(context.TODO(), &tasks); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	boardIds := []bson.ObjectID{feed.Board}
+	for _, task := range tasks {
+		boardIds = append(boardIds, task.Board)
+	}
+	boards, err := boardNames(boardIds)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	if feed.Scope == feedScopeBoard {
+		name = workspace.Name + " / " + boards[feed.Board]
+	}
+
+	calendar := newIcalCalendar("Rela: " + name)
+	for _, task := range tasks {
+		calendar.task(task, feed.Component, boards[task.Board], now.Unix())
+	}
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(200, "text/calendar; charset=utf-8", []byte(calendar.end()))
+}
+
+var icalUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+// icalProperty is a content line of an iCalendar object.
+type icalProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+func (p icalProperty) text() string {
+	return icalUnescaper.Replace(p.Value)
+}
+
+// time parses DATE-TIME values in UTC, with a TZID or floating in UTC, and DATE values at midnight UTC.
+func (p icalProperty) time() (int64, bool) {
+	location := time.UTC
+	if tzid := p.Params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(strings.Trim(tzid, `"`)); err == nil {
+			location = loaded
+		}
+	}
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if parsed, err := time.ParseInLocation(layout, p.Value, location); err == nil {
+			if layout == "20060102" {
+				parsed = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, time.UTC)
+			}
+			return parsed.Unix(), true
+		}
+	}
+	return 0, false
+}
+
+// splitIcalParams splits a property name and its parameters, quoted parameter values may contain ";".
+func splitIcalParams(s string) []string {
+	parts := make([]string, 0, 1)
+	quoted := false
+	start := 0
+	for i, r := range s {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ';' && !quoted {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func parseIcalLine(line string) (icalProperty, bool) {
+	property := icalProperty{Params: map[string]string{}}
+	quoted := false
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			property.Value = line[i+1:]
+			parts := splitIcalParams(line[:i])
+			property.Name = strings.ToUpper(parts[0])
+			for _, param := range parts[1:] {
+				if name, value, found := strings.Cut(param, "="); found {
+					property.Params[strings.ToUpper(name)] = value
+				}
+			}
+			return property, true
+		}
+	}
+	return property, false
+}
+
+// parseIcalComponent returns the properties of the first component with the given name, like VTODO,
+// without those of components nested in it like VALARM.
+func parseIcalComponent(data string, component string) ([]icalProperty, bool) {
+	data = strings.NewReplacer("\r\n ", "", "\r\n\t", "", "\n ", "", "\n\t", "").Replace(data)
+	properties := make([]icalProperty, 0)
+	depth := 0
+	found := false
+	for _, line := range strings.Split(data, "\n") {
+		property, ok := parseIcalLine(strings.TrimRight(line, "\r"))
+		if !ok {
+			continue
+		}
+		switch {
+		case property.Name == "BEGIN" && depth == 0 && strings.EqualFold(property.Value, component) && !found:
+			depth, found = 1, true
+		case property.Name == "BEGIN" && depth > 0:
+			depth++
+		case property.Name == "END" && depth > 0:
+			depth--
+			if depth == 0 {
+				return properties, true
+			}
+		case depth == 1:
+			properties = append(properties, property)
+		}
+	}
+	return properties, false
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestIcalText(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Fix login", "Fix login"},
+		{"a,b;c", `a\,b\;c`},
+		{`C:\temp`, `C:\\temp`},
+		{"first\r\nsecond\nthird\r", `first\nsecond\nthird`},
+	}
+	for _, test := range tests {
+		if got := icalText(test.value); got != test.want {
+			t.Errorf("icalText(%q) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestIcalWriterLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"short", "Fix login"},
+		{"exactly one line", strings.Repeat("a", icalLineLength-len("SUMMARY:"))},
+		{"one octet over", strings.Repeat("a", icalLineLength-len("SUMMARY:")+1)},
+		{"several lines", strings.Repeat("abcdefghij", 30)},
+		{"multibyte characters", strings.Repeat("Grüße 🎉 ", 40)},
+	}
+	for _, test := range tests {
+		w := &icalWriter{}
+		w.line("SUMMARY", test.value)
+		output := w.String()
+		if !strings.HasSuffix(output, "\r\n") {
+			t.Errorf("%s: line does not end with CRLF", test.name)
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(output, "\r\n"), "\r\n") {
+			if len(line) > icalLineLength {
+				t.Errorf("%s: line of %d octets", test.name, len(line))
+			}
+			if !utf8.ValidString(line) {
+				t.Errorf("%s: line %q splits a character", test.name, line)
+			}
+		}
+		properties, _ := parseIcalComponent("BEGIN:VTODO\r\n"+output+"END:VTODO\r\n", "VTODO")
+		if len(properties) != 1 || properties[0].Value != test.value {
+			t.Errorf("%s: unfolded to %+v, want %q", test.name, properties, test.value)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	maxImportFileSize = 64 << 20
+	importBatchSize   = 200
+)
+
+// readImportFile reads the uploaded "file" form field.
+func readImportFile(c *gin.Context) ([]byte, bool) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'file' is required"})
+		return nil, false
+	} else if fileHeader.Size > maxImportFileSize {
+		c.AbortWithStatusJSON(413, gin.H{"error": "The file is too large"})
+		return nil, false
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return nil, false
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return nil, false
+	}
+	return data, true
+}
+
+// importedComment is a comment of an imported issue. Rela has no comments, so they are added to the description.
+type importedComment struct {
+	Author string
+	Date   int64
+	Text   string
+}
+
+// importedDescription joins the description sections of an imported issue with its comments and a
+// link back to where it came from.
+func importedDescription(sections []string, comments []importedComment, source string) string {
+	if len(comments) != 0 {
+		var section strings.Builder
+		section.WriteString("### Comments")
+		for _, comment := range comments {
+			author := comment.Author
+			if author == "" {
+				author = "Someone"
+			}
+			date := ""
+			if comment.Date != 0 {
+				date = " on " + time.Unix(comment.Date, 0).UTC().Format("2006-01-02")
+			}
+			fmt.Fprintf(&section, "\n\n**%s**%s:\n> %s", author, date, strings.ReplaceAll(strings.TrimSpace(comment.Text), "\n", "\n> "))
+		}
+		sections = append(sections, section.String())
+	}
+	if source != "" {
+		sections = append(sections, "Imported from "+source)
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// importTarget resolves names of boards and labels to existing documents of the workspace an importer
+// writes into, and creates the missing ones.
+type importTarget struct {
+	workspace Workspace
+	actor     bson.ObjectID
+	boards    map[string]Board
+	labels    map[string]bson.ObjectID
+	users     []User
+	unmatched map[string]bool
+	// Boards and labels that only get created once a task using them is inserted
+	pendingBoards map[bson.ObjectID]Board
+	pendingLabels map[bson.ObjectID]Label
+}
+
+func newImportTarget(workspace Workspace, actor bson.ObjectID) (*importTarget, error) {
+	target := &importTarget{
+		workspace: workspace,
+		actor:     actor,
+		boards:    map[string]Board{},
+		labels:    map[string]bson.ObjectID{},
+		unmatched: map[string]bool{},
+
+		pendingBoards: map[bson.ObjectID]Board{},
+		pendingLabels: map[bson.ObjectID]Label{},
+	}
+	boards, err := workspaceBoards(workspace.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, board := range boards {
+		target.boards[strings.ToLower(board.Name)] = board
+	}
+	labels, err := workspaceLabels(workspace.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, label := range labels {
+		target.labels[strings.ToLower(label.Name)] = label.Id
+	}
+	if target.users, err = workspaceUsers(workspace); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// board returns the board with the given name, creating it if there is none.
+func (t *importTarget) board(name string) (Board, bool, error) {
+	if board, ok := t.boards[strings.ToLower(name)]; ok {
+		return board, false, nil
+	}
+	board := Board{Name: name, OwnedBy: t.workspace.Id}
+	result, err := boardsDb.InsertOne(context.TODO(), board)
+	if err != nil {
+		return board, false, err
+	}
+	board.Id = result.InsertedID.(bson.ObjectID)
+	t.boards[strings.ToLower(name)] = board
+	publishEvent(Event{Type: eventBoardCreated, Workspace: t.workspace.Id, BoardId: board.Id, Actor: t.actor, Board: &board})
+	return board, true, nil
+}
+
+// label returns the label with the given name, creating it with the color if there is none.
+func (t *importTarget) label(name string, color string) (bson.ObjectID, bool, error) {
+	if labelId, ok := t.labels[strings.ToLower(name)]; ok {
+		return labelId, false, nil
+	}
+	if !colorRegex.MatchString(color) {
+		color = "#b3bac5"
+	}
+	result, err := labelsDb.InsertOne(context.TODO(), Label{Name: name, Color: color, Workspace: t.workspace.Id})
+	if err != nil {
+		return bson.ObjectID{}, false, err
+	}
+	labelId := result.InsertedID.(bson.ObjectID)
+	t.labels[strings.ToLower(name)] = labelId
+	return labelId, true, nil
+}
+
+// pendingBoard is board for importers that skip tasks imported before. A missing board only gets
+// an id here and is created by insertTasks if a task that is not skipped lands on it.
+func (t *importTarget) pendingBoard(name string) bson.ObjectID {
+	if board, ok := t.boards[strings.ToLower(name)]; ok {
+		return board.Id
+	}
+	board := Board{Id: bson.NewObjectID(), Name: name, OwnedBy: t.workspace.Id}
+	t.boards[strings.ToLower(name)] = board
+	t.pendingBoards[board.Id] = board
+	return board.Id
+}
+
+// pendingLabel is label for importers that skip tasks imported before, see pendingBoard.
+func (t *importTarget) pendingLabel(name string, color string) bson.ObjectID {
+	if labelId, ok := t.labels[strings.ToLower(name)]; ok {
+		return labelId
+	}
+	if !colorRegex.MatchString(color) {
+		color = "#b3bac5"
+	}
+	label := Label{Id: bson.NewObjectID(), Name: name, Color: color, Workspace: t.workspace.Id}
+	t.labels[strings.ToLower(name)] = label.Id
+	t.pendingLabels[label.Id] = label
+	return label.Id
+}
+
+// createPending creates the pending boards and labels the tasks use.
+func (t *importTarget) createPending(tasks []Task) error {
+	for _, task := range tasks {
+		if board, ok := t.pendingBoards[task.Board]; ok {
+			if _, err := boardsDb.InsertOne(context.TODO(), board); err != nil {
+				return err
+			}
+			delete(t.pendingBoards, board.Id)
+			publishEvent(Event{Type: eventBoardCreated, Workspace: t.workspace.Id, BoardId: board.Id, Actor: t.actor, Board: &board})
+		}
+		for _, labelId := range task.Labels {
+			if label, ok := t.pendingLabels[labelId]; ok {
+				if _, err := labelsDb.InsertOne(context.TODO(), label); err != nil {
+					return err
+				}
+				delete(t.pendingLabels, labelId)
+			}
+		}
+	}
+	return nil
+}
+
+// userByName finds a workspace member by display name or by the local part of their email.
+func (t *importTarget) userByName(names ...string) (bson.ObjectID, bool) {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		for _, user := range t.users {
+			local, _, _ := strings.Cut(user.Email, "@")
+			if strings.EqualFold(user.Name, name) || strings.EqualFold(local, name) {
+				return user.Id, true
+			}
+		}
+	}
+	return bson.ObjectID{}, false
+}
+
+// skipImported drops tasks whose external id was imported into the workspace before, or appears
+// twice in the same import, so importing a file again does not duplicate its tasks.
+func (t *importTarget) skipImported(tasks []Task) ([]Task, error) {
+	externalIds := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		if task.ExternalId != "" {
+			externalIds = append(externalIds, task.ExternalId)
+		}
+	}
+	if len(externalIds) == 0 {
+		return tasks, nil
+	}
+	var existing []string
+	err := tasksDb.Distinct(context.TODO(), "external_id",
+		bson.D{{"created_by", t.workspace.Id}, {"external_id", bson.D{{"$in", externalIds}}}},
+	).Decode(&existing)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, externalId := range existing {
+		seen[externalId] = true
+	}
+	remaining := make([]Task, 0, len(tasks))
+	for _, task := range tasks {
+		if task.ExternalId != "" && seen[task.ExternalId] {
+			continue
+		}
+		seen[task.ExternalId] = task.ExternalId != ""
+		remaining = append(remaining, task)
+	}
+	return remaining, nil
+}
+
+func (t *importTarget) userByEmail(email string) (bson.ObjectID, bool) {
+	for _, user := range t.users {
+		if email != "" && strings.EqualFold(user.Email, email) {
+			return user.Id, true
+		}
+	}
+	return bson.ObjectID{}, false
+}
+
+// assignee matches a user of another tool to a workspace member, by email if the tool exports it and
+// by name otherwise. Users without a match are reported once.
+func (t *importTarget) assignee(runner *jobRunner, email string, names ...string) (bson.ObjectID, bool) {
+	if userId, ok := t.userByEmail(email); ok {
+		return userId, true
+	}
+	if userId, ok := t.userByName(names...); ok {
+		return userId, true
+	}
+	who := email
+	if who == "" {
+		who = strings.Join(slices.DeleteFunc(slices.Clone(names), func(name string) bool { return name == "" }), " / ")
+	}
+	if who != "" && !t.unmatched[who] {
+		t.unmatched[who] = true
+		runner.warn("%s is not a member of this workspace, their issues are unassigned", who)
+	}
+	return bson.ObjectID{}, false
+}
+
+// insertTasks numbers and stores imported tasks in batches, reporting progress after every batch.
+// Tasks that were imported before are skipped, the number of created and skipped tasks is returned.
+func (t *importTarget) insertTasks(tasks []Task, runner *jobRunner) (int, int, error) {
+	keyPrefix, err := ensureWorkspaceKey(t.workspace)
+	if err != nil {
+		return 0, 0, err
+	}
+	remaining, err := t.skipImported(tasks)
+	if err != nil {
+		return 0, 0, err
+	}
+	skipped := len(tasks) - len(remaining)
+	runner.advance(skipped)
+	tasks = remaining
+	if err := t.createPending(tasks); err != nil {
+		return 0, skipped, err
+	}
+	created := 0
+	for start := 0; start < len(tasks); start += importBatchSize {
+		batch := tasks[start:min(start+importBatchSize, len(tasks))]
+		first, err := reserveTaskNumbers(t.workspace.Id, int64(len(batch)))
+		if err != nil {
+			return created, skipped, err
+		}
+		documents := make([]any, 0, len(batch))
+		for i := range batch {
+			batch[i].Id = bson.NewObjectID()
+			batch[i].CreatedBy = t.workspace.Id
+			batch[i].Number = first + int64(i)
+			batch[i].Key = formatTaskKey(keyPrefix, batch[i].Number)
+			if batch[i].Priority == "" {
+				setTaskPriority(&batch[i], "")
+			}
+			batch[i].Watchers = importedTaskWatchers(t.actor, batch[i].Assignees)
+			documents = append(documents, batch[i])
+		}
+		// A concurrent import of the same file loses on the unique external id, its tasks are skipped
+		duplicates := map[int]bool{}
+		if _, err := tasksDb.InsertMany(context.TODO(), documents, options.InsertMany().SetOrdered(false)); err != nil {
+			var bulkErr mongo.BulkWriteException
+			if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+				return created, skipped, err
+			}
+			for _, writeErr := range bulkErr.WriteErrors {
+				if !writeErr.HasErrorCode(11000) {
+					return created, skipped, err
+				}
+				duplicates[writeErr.Index] = true
+			}
+		}
+		for i := range batch {
+			if duplicates[i] {
+				skipped++
+				continue
+			}
+			created++
+			publishEvent(Event{Type: eventTaskCreated, Workspace: t.workspace.Id, Actor: t.actor, Task: &batch[i]})
+		}
+		runner.advance(len(batch))
+	}
+	return created, skipped, nil
+}
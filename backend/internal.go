@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 var (
@@ -86,7 +88,7 @@ func authMiddleware() gin.HandlerFunc {
 			if claims.ExpiresAt.Time.Before(time.Now().UTC()) {
 				c.AbortWithStatusJSON(403, "Authorization Required")
 				return
-			} else if claims.Type == "refresh" || claims.Type == "invite" {
+			} else if claims.Type == "refresh" || claims.Type == "invite" || claims.Type == "stream" {
 				c.AbortWithStatusJSON(400, "Invalid Token")
 			} else {
 				c.Set("id", claims.Id)
@@ -98,7 +100,7 @@ func authMiddleware() gin.HandlerFunc {
 
 func taskMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if !(c.Request.Method == "PATCH") && !(c.Request.Method == "DELETE") {
+		if !(c.Request.Method == "PATCH") && !(c.Request.Method == "DELETE") && !(c.Request.Method == "POST") {
 			c.Next()
 			return
 		}
@@ -109,14 +111,15 @@ func taskMiddleware() gin.HandlerFunc {
 			c.AbortWithStatusJSON(400, gin.H{"error": "Task id is required"})
 			return
 		}
-		// Convert the hex taskId from URL to bson.ObjectID
-		taskObjectID, err := bson.ObjectIDFromHex(taskId)
-		if err != nil {
+		// The task can be addressed by its ObjectID or by its key like WEB-42
+		workspaceId, _ := bson.ObjectIDFromHex(c.Param("workspaceId"))
+		filter, ok := taskRefFilter(workspaceId, taskId)
+		if !ok {
 			c.AbortWithStatusJSON(400, gin.H{"error": "Invalid task id"})
 			return
 		}
 		var output Task
-		if err := tasksDb.FindOne(context.TODO(), bson.D{{"_id", taskObjectID}}).Decode(&output); err != nil {
+		if err := tasksDb.FindOne(context.TODO(), filter).Decode(&output); err != nil {
 			if errors.Is(err, mongo.ErrNoDocuments) {
 				c.AbortWithStatusJSON(404, gin.H{"error": "Not Found"})
 				return
@@ -188,3 +191,130 @@ func userMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// authorizeWorkspaceAccess loads the workspace from the path and checks that the current user is a member or the owner.
+// On failure the request is aborted and false is returned.
+func authorizeWorkspaceAccess(c *gin.Context) (Workspace, bool) {
+	userId, _ := c.Get("id")
+	var workspace Workspace
+	workspaceId, err := bson.ObjectIDFromHex(c.Param("workspaceId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Invalid workspaceId"})
+		return workspace, false
+	}
+	if err := workspacesDb.FindOne(context.TODO(), bson.M{"_id": workspaceId}).Decode(&workspace); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.AbortWithStatusJSON(404, gin.H{"error": "Workspace not found"})
+		} else {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal server error"})
+		}
+		return workspace, false
+	}
+	if slices.Index(workspace.Members, userId.(bson.ObjectID)) == -1 && workspace.OwnedBy != userId.(bson.ObjectID) {
+		c.AbortWithStatusJSON(403, gin.H{"error": "You are not a member of this workspace"})
+		return workspace, false
+	}
+	return workspace, true
+}
+
+// ensureIndexes creates the indexes and collections the server relies on. Creating an existing index is a no-op.
+func ensureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := ensureEventsCollection(ctx); err != nil {
+		return err
+	}
+	if _, err := eventsDb.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"workspace", 1}, {"_id", -1}}}); err != nil {
+		return err
+	}
+	if _, err := tasksDb.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{"created_by", 1}, {"board", 1}}},
+		{Keys: bson.D{{"labels", 1}}},
+		{
+			// Importers match on the id a task has in the tool it was imported from
+			Keys:    bson.D{{"created_by", 1}, {"external_id", 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.D{{"external_id", bson.D{{"$exists", true}}}}),
+		},
+		{
+			Keys:    bson.D{{"created_by", 1}, {"key", 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.D{{"key", bson.D{{"$exists", true}}}}),
+		},
+		{Keys: bson.D{{"custom_fields.$**", 1}}},
+		{Keys: bson.D{{"deadline", 1}}},
+		{Keys: bson.D{{"watchers", 1}}},
+		{
+			Keys:    bson.D{{"recurrence.series", 1}, {"recurrence.occurrence", 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.D{{"recurrence.series", bson.D{{"$exists", true}}}}),
+		},
+		{
+			Keys:    bson.D{{"name", "text"}, {"description", "text"}},
+			Options: options.Index().SetWeights(bson.D{{"name", 10}, {"description", 1}}).SetDefaultLanguage("none"),
+		},
+	}); err != nil {
+		return err
+	}
+	if _, err := labelsDb.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"workspace", 1}}}); err != nil {
+		return err
+	}
+	if _, err := customFieldsDb.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"workspace", 1}}}); err != nil {
+		return err
+	}
+	if _, err := viewsDb.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"workspace", 1}, {"owner", 1}}}); err != nil {
+		return err
+	}
+	if _, err := notificationsDb.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{"user", 1}, {"updated_at", -1}}},
+		{Keys: bson.D{{"read", 1}, {"updated_at", 1}}},
+		{
+			// At most one unread notification per task and user, new events are merged into it
+			Keys:    bson.D{{"user", 1}, {"task", 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.D{{"read", false}, {"task", bson.D{{"$exists", true}}}}),
+		},
+	}); err != nil {
+		return err
+	}
+	if _, err := webhooksDb.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"workspace", 1}}}); err != nil {
+		return err
+	}
+	if _, err := webhookDeliveriesDb.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{"status", 1}, {"next_attempt_at", 1}}},
+		{Keys: bson.D{{"webhook", 1}, {"created_at", -1}}},
+	}); err != nil {
+		return err
+	}
+	if _, err := jobsDb.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"created_by", 1}, {"created_at", -1}}}); err != nil {
+		return err
+	}
+	if _, err := feedsDb.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{"token_hash", 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{"user", 1}}},
+	}); err != nil {
+		return err
+	}
+	if _, err := appPasswordsDb.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{"hash", 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{"user", 1}}},
+	}); err != nil {
+		return err
+	}
+	if _, err := exportTemplatesDb.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"workspace", 1}}}); err != nil {
+		return err
+	}
+	if _, err := workspaceTemplatesDb.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"created_by", 1}}}); err != nil {
+		return err
+	}
+	// Reminder records are how schedulers on every replica agree that a reminder was already sent
+	if _, err := remindersDb.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"task", 1}, {"user", 1}, {"kind", 1}, {"window", 1}, {"deadline", 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return err
+	}
+	if _, err := remindersDb.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"email_pending", 1}, {"email_claimed_until", 1}},
+		Options: options.Index().SetSparse(true),
+	}); err != nil {
+		return err
+	}
+	return nil
+}
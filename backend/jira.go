@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	jiraBoardsByStatus  = "status"
+	jiraBoardsByProject = "project"
+)
+
+var jiraPriorities = map[string]string{
+	"highest":  "urgent",
+	"blocker":  "urgent",
+	"high":     "high",
+	"critical": "high",
+	"medium":   "medium",
+	"major":    "medium",
+	"low":      "low",
+	"minor":    "low",
+	"lowest":   "low",
+	"trivial":  "low",
+}
+
+// Columns the importer reads. Everything else in the export is reported as not imported.
+var jiraColumns = []string{
+	"issue key", "issue id", "summary", "description", "status", "status category", "resolved", "updated",
+	"created", "priority", "assignee", "labels", "due date", "due", "comment", "project name", "project key",
+	"story points", "custom field (story points)",
+}
+
+var jiraDonePattern = []string{"done", "closed", "resolved", "complete", "completed"}
+
+// Jira writes dates in the format configured for the instance, these are the common ones.
+var jiraTimeLayouts = []string{
+	"02/Jan/06 3:04 PM", "02/Jan/06 15:04", "02/Jan/06", "2/Jan/06 3:04 PM", "2/Jan/06",
+	"2006-01-02 15:04", "2006-01-02", time.RFC3339, "2006/01/02 15:04",
+}
+
+func parseJiraTime(value string) (int64, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range jiraTimeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.Unix(), true
+		}
+	}
+	return 0, false
+}
+
+// jiraIssue holds the cells of one row by lowercase header. Jira repeats headers like Labels and
+// Comment for every value, so a header can have many cells.
+type jiraIssue map[string][]string
+
+// get returns the first value of the first of the columns that has one.
+func (issue jiraIssue) get(columns ...string) string {
+	for _, column := range columns {
+		for _, value := range issue[column] {
+			if value = strings.TrimSpace(value); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// jiraComment parses a comment cell, which Jira exports as "date;author account id;text".
+func jiraComment(cell string) importedComment {
+	parts := strings.SplitN(cell, ";", 3)
+	if len(parts) == 3 {
+		if date, ok := parseJiraTime(parts[0]); ok {
+			return importedComment{Date: date, Text: parts[2]}
+		}
+	}
+	return importedComment{Text: cell}
+}
+
+// importJira maps the issues of a Jira CSV export onto boards and tasks of the workspace.
+func importJira(records [][]string, target *importTarget, boardsBy string, runner *jobRunner) error {
+	header := make([]string, len(records[0]))
+	unknown := make([]string, 0)
+	for i, column := range records[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if !slices.Contains(jiraColumns, header[i]) && !slices.Contains(unknown, column) {
+			unknown = append(unknown, column)
+		}
+	}
+	if len(unknown) != 0 {
+		runner.warn("Columns not imported: %s", strings.Join(unknown, ", "))
+	}
+	rows := records[1:]
+	runner.setTotal(len(rows))
+
+	now := time.Now().UTC().Unix()
+	tasks := make([]Task, 0, len(rows))
+	badDates := 0
+	for _, record := range rows {
+		issue := jiraIssue{}
+		for i, cell := range record {
+			if i < len(header) && cell != "" {
+				issue[header[i]] = append(issue[header[i]], cell)
+			}
+		}
+		key := issue.get("issue key", "issue id")
+		if key == "" || issue.get("summary") == "" {
+			runner.warn("A row without issue key or summary was not imported")
+			runner.advance(1)
+			continue
+		}
+
+		boardName := issue.get("project name", "project key")
+		if boardsBy == jiraBoardsByStatus && issue.get("status") != "" {
+			boardName = issue.get("status")
+		}
+		if boardName == "" {
+			boardName = "Jira"
+		}
+		board := target.pendingBoard(boardName)
+
+		task := Task{
+			Name:       issue.get("summary"),
+			Board:      board,
+			CreatedAt:  now,
+			ExternalId: "jira:" + key,
+		}
+		comments := make([]importedComment, 0)
+		for _, cell := range issue["comment"] {
+			comments = append(comments, jiraComment(cell))
+		}
+		sections := make([]string, 0)
+		if description := issue.get("description"); description != "" {
+			sections = append(sections, description)
+		}
+		task.Description = importedDescription(sections, comments, key)
+
+		if created, ok := parseJiraTime(issue.get("created")); ok {
+			task.CreatedAt = created
+		}
+		if due := issue.get("due date", "due"); due != "" {
+			if deadline, ok := parseJiraTime(due); ok {
+				task.Deadline = deadline
+			} else {
+				badDates++
+			}
+		}
+		status := strings.ToLower(issue.get("status"))
+		if strings.EqualFold(issue.get("status category"), "done") || issue.get("resolved") != "" || slices.Contains(jiraDonePattern, status) {
+			task.CompletedAt = now
+			if resolved, ok := parseJiraTime(issue.get("resolved")); ok {
+				task.CompletedAt = resolved
+			} else if updated, ok := parseJiraTime(issue.get("updated")); ok {
+				task.CompletedAt = updated
+			}
+		}
+		setTaskPriority(&task, jiraPriorities[strings.ToLower(issue.get("priority"))])
+		if points := issue.get("story points", "custom field (story points)"); points != "" {
+			if value, err := strconv.ParseFloat(points, 64); err == nil && value >= 0 {
+				task.Estimate = TaskEstimate{Unit: estimatePoints, Value: value}
+			}
+		}
+		if assignee := issue.get("assignee"); assignee != "" {
+			email := ""
+			if strings.Contains(assignee, "@") {
+				email = assignee
+			}
+			if userId, ok := target.assignee(runner, email, assignee); ok {
+				task.Assignees = []bson.ObjectID{userId}
+			}
+		}
+		for _, name := range issue["labels"] {
+			for _, label := range strings.Fields(name) {
+				labelId := target.pendingLabel(label, "")
+				if !slices.Contains(task.Labels, labelId) {
+					task.Labels = append(task.Labels, labelId)
+				}
+			}
+		}
+		tasks = append(tasks, task)
+	}
+	if badDates != 0 {
+		runner.warn("%d due dates were not in a known date format and were not imported", badDates)
+	}
+	created, skipped, err := target.insertTasks(tasks, runner)
+	if err != nil {
+		return err
+	}
+	runner.setResult("tasks_created", created)
+	runner.setResult("tasks_skipped", skipped)
+	return nil
+}
+
+// @Summary 		Import a Jira CSV export
+// @Description 	Starts a background job importing the issues of a Jira CSV export, with all fields. Issues become tasks on a board per status or per project, with priority, assignee matched by email or name, labels, due date, story points and comments added to the description. Importing a file again skips issues that were imported before.
+// @Router 			/workspaces/{workspaceId}/import/jira [post]
+// @Tags 			Import and export
+// @Security 		BearerAuth
+// @Accept 			multipart/form-data
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			file formData file true "Jira CSV export"
+// @Param 			boards query string false "Create a board per status or per project, default status" Enums(status, project)
+// @Success 		202 {object} Job "The started import job"
+// @Failure 		400 {object} ErrorSwagger "Bad request - missing file or malformed CSV"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - workspace not found"
+// @Failure 		413 {object} ErrorSwagger "The file is too large"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func importJiraCsv(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	boardsBy := c.DefaultQuery("boards", jiraBoardsByStatus)
+	if boardsBy != jiraBoardsByStatus && boardsBy != jiraBoardsByProject {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'boards' must be status or project"})
+		return
+	}
+	data, ok := readImportFile(c)
+	if !ok {
+		return
+	}
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Malformed CSV: " + err.Error()})
+		return
+	} else if len(records) == 0 || !slices.ContainsFunc(records[0], func(column string) bool { return strings.EqualFold(strings.TrimSpace(column), "summary") }) {
+		c.AbortWithStatusJSON(400, gin.H{"error": "The file is not a Jira CSV export"})
+		return
+	}
+
+	job, err := startJob(Job{Type: "import.jira", Workspace: workspace.Id, CreatedBy: userId.(bson.ObjectID)}, func(runner *jobRunner) error {
+		target, err := newImportTarget(workspace, userId.(bson.ObjectID))
+		if err != nil {
+			return err
+		}
+		return importJira(records, target, boardsBy, runner)
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to start import"})
+		return
+	}
+	c.JSON(202, job)
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	jobRunning = "running"
+	jobDone    = "done"
+	jobFailed  = "failed"
+)
+
+const (
+	jobHeartbeat = 30 * time.Second
+	// Running jobs without a heartbeat for this long belong to an instance that went away
+	jobStaleAfter     = 10 * time.Minute
+	jobProgressEvery  = 2 * time.Second
+	maxJobWarnings    = 200
+	jobStaleCheckTick = time.Minute
+)
+
+// jobRunner is handed to the function doing the work of a job to report progress, warnings and results.
+type jobRunner struct {
+	mu        sync.Mutex
+	job       Job
+	lastWrite time.Time
+}
+
+// startJob stores a job and runs it in the background. The job document is how clients follow it.
+func startJob(job Job, run func(runner *jobRunner) error) (Job, error) {
+	now := time.Now().UTC().Unix()
+	job.Status = jobRunning
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	job.Warnings = make([]string, 0)
+	job.Result = map[string]any{}
+	result, err := jobsDb.InsertOne(context.TODO(), job)
+	if err != nil {
+		return job, err
+	}
+	job.Id = result.InsertedID.(bson.ObjectID)
+	runner := &jobRunner{job: job}
+	go runner.run(run)
+	return job, nil
+}
+
+func (r *jobRunner) run(run func(runner *jobRunner) error) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(jobHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.save(true)
+			case <-done:
+				return
+			}
+		}
+	}()
+	err := func() (err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = fmt.Errorf("job crashed: %v", recovered)
+			}
+		}()
+		return run(r)
+	}()
+	close(done)
+
+	r.mu.Lock()
+	r.job.Status = jobDone
+	if err != nil {
+		r.job.Status = jobFailed
+		r.job.Error = err.Error()
+		println("WARNING Job ", r.job.Id.Hex(), " failed: ", err.Error())
+	}
+	r.job.FinishedAt = time.Now().UTC().Unix()
+	r.mu.Unlock()
+	r.save(true)
+}
+
+// save writes the state of the job. Progress updates are throttled, forced writes are not.
+func (r *jobRunner) save(force bool) {
+	r.mu.Lock()
+	if !force && time.Since(r.lastWrite) < jobProgressEvery {
+		r.mu.Unlock()
+		return
+	}
+	r.lastWrite = time.Now()
+	r.job.UpdatedAt = time.Now().UTC().Unix()
+	update := bson.D{{"$set", bson.D{
+		{"status", r.job.Status},
+		{"progress", r.job.Progress},
+		{"total", r.job.Total},
+		{"workspace", r.job.Workspace},
+		{"warnings", r.job.Warnings},
+		{"result", r.job.Result},
+		{"error", r.job.Error},
+		{"updated_at", r.job.UpdatedAt},
+		{"finished_at", r.job.FinishedAt},
+	}}}
+	r.mu.Unlock()
+	if _, err := jobsDb.UpdateOne(context.TODO(), bson.D{{"_id", r.job.Id}}, update); err != nil {
+		println("WARNING Failed to save job ", r.job.Id.Hex(), ": ", err.Error())
+	}
+}
+
+func (r *jobRunner) setTotal(total int) {
+	r.mu.Lock()
+	r.job.Total = total
+	r.mu.Unlock()
+	r.save(true)
+}
+
+func (r *jobRunner) advance(steps int) {
+	r.mu.Lock()
+	r.job.Progress += steps
+	r.mu.Unlock()
+	r.save(false)
+}
+
+// warn records something the job could not do, like data an importer could not map.
+func (r *jobRunner) warn(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.job.Warnings) < maxJobWarnings {
+		r.job.Warnings = append(r.job.Warnings, fmt.Sprintf(format, args...))
+	}
+}
+
+func (r *jobRunner) setResult(key string, value any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.job.Result[key] = value
+}
+
+// setWorkspace records the workspace a job works on, for jobs that create one.
+func (r *jobRunner) setWorkspace(workspaceId bson.ObjectID) {
+	r.mu.Lock()
+	r.job.Workspace = workspaceId
+	r.mu.Unlock()
+	r.save(true)
+}
+
+// failStaleJobs marks jobs as failed whose instance stopped while running them.
+func failStaleJobs() error {
+	now := time.Now().UTC()
+	_, err := jobsDb.UpdateMany(context.TODO(),
+		bson.D{{"status", jobRunning}, {"updated_at", bson.D{{"$lt", now.Add(-jobStaleAfter).Unix()}}}},
+		bson.D{{"$set", bson.D{{"status", jobFailed}, {"error", "Interrupted by a server restart"}, {"finished_at", now.Unix()}}}},
+	)
+	return err
+}
+
+// runJobJanitor regularly fails jobs that were interrupted. Every replica can run it.
+func runJobJanitor() {
+	ticker := time.NewTicker(jobStaleCheckTick)
+	defer ticker.Stop()
+	for ; ; <-ticker.C {
+		if err := failStaleJobs(); err != nil {
+			println("WARNING Failed to clean up interrupted jobs: ", err.Error())
+		}
+	}
+}
+
+// @Summary 		Get your jobs
+// @Description 	Returns the background jobs you started, like imports, newest first.
+// @Router 			/jobs [get]
+// @Tags 			Jobs
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			limit query int false "Page size, 1 to 500, default 100"
+// @Param 			cursor query string false "Cursor of the next page from a previous response"
+// @Success 		200 {object} AllJobsResponse "A page of jobs"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid cursor or limit"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func getAllJobs(c *gin.Context) {
+	userId, _ := c.Get("id")
+	page, ok := parsePageRequest(c, "", "$created_at", true)
+	if !ok {
+		return
+	}
+	jobs, nextCursor, err := paginate[Job](jobsDb, bson.D{{"created_by", userId}}, page)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(200, gin.H{"jobs": jobs, "next_cursor": nextCursor})
+}
+
+// @Summary 		Get a job
+// @Description 	Returns the status, progress, warnings and result of a background job you started.
+// @Router 			/jobs/{jobId} [get]
+// @Tags 			Jobs
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			jobId path string true "Job ID"
+// @Success 		200 {object} Job "The job"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid job id"
+// @Failure 		404 {object} ErrorSwagger "Not Found - job not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func getJob(c *gin.Context) {
+	userId, _ := c.Get("id")
+	jobId, err := bson.ObjectIDFromHex(c.Param("jobId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid jobId"})
+		return
+	}
+	var job Job
+	err = jobsDb.FindOne(context.TODO(), bson.D{{"_id", jobId}, {"created_by", userId}}).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Job does not exist"})
+		return
+	} else if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(200, job)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var workspaceKeyRegex = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+var taskKeyRegex = regexp.MustCompile(`^([A-Z][A-Z0-9]{1,9})-([1-9][0-9]*)$`)
+
+// deriveWorkspaceKey builds a default key prefix from the first letters and digits of a workspace name.
+func deriveWorkspaceKey(name string) string {
+	var key strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			continue
+		}
+		if key.Len() == 0 && !unicode.IsLetter(r) {
+			continue
+		}
+		key.WriteRune(r)
+		if key.Len() == 3 {
+			break
+		}
+	}
+	if key.Len() < 2 {
+		return "TASK"
+	}
+	return key.String()
+}
+
+// ensureWorkspaceKey returns the key prefix of a workspace, assigning a derived one to workspaces created before keys existed.
+func ensureWorkspaceKey(workspace Workspace) (string, error) {
+	if workspace.Key != "" {
+		return workspace.Key, nil
+	}
+	key := deriveWorkspaceKey(workspace.Name)
+	if _, err := workspacesDb.UpdateOne(context.TODO(), bson.D{{"_id", workspace.Id}, {"key", bson.D{{"$in", bson.A{"", nil}}}}}, bson.D{{"$set", bson.D{{"key", key}}}}); err != nil {
+		return "", err
+	}
+	var updated Workspace
+	if err := workspacesDb.FindOne(context.TODO(), bson.D{{"_id", workspace.Id}}).Decode(&updated); err != nil {
+		return "", err
+	}
+	return updated.Key, nil
+}
+
+// nextTaskNumber atomically increments the task counter document of a workspace.
+func nextTaskNumber(workspaceId bson.ObjectID) (int64, error) {
+	return reserveTaskNumbers(workspaceId, 1)
+}
+
+// reserveTaskNumbers atomically reserves count consecutive task numbers and returns the first one.
+func reserveTaskNumbers(workspaceId bson.ObjectID, count int64) (int64, error) {
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	err := countersDb.FindOneAndUpdate(context.TODO(),
+		bson.D{{"_id", workspaceId}},
+		bson.D{{"$inc", bson.D{{"seq", count}}}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	if err != nil {
+		return 0, err
+	}
+	return counter.Seq - count + 1, nil
+}
+
+func formatTaskKey(prefix string, number int64) string {
+	return fmt.Sprintf("%s-%d", prefix, number)
+}
+
+// backfillTaskKeys assigns keys to tasks created before keys existed. They are numbered oldest first,
+// after the tasks that already have a key.
+func backfillTaskKeys() error {
+	missingKey := bson.E{"key", bson.D{{"$exists", false}}}
+	var workspaceIds []bson.ObjectID
+	if err := tasksDb.Distinct(context.TODO(), "created_by", bson.D{missingKey}).Decode(&workspaceIds); err != nil {
+		return err
+	}
+	for _, workspaceId := range workspaceIds {
+		var workspace Workspace
+		if err := workspacesDb.FindOne(context.TODO(), bson.D{{"_id", workspaceId}}).Decode(&workspace); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				continue
+			}
+			return err
+		}
+		prefix, err := ensureWorkspaceKey(workspace)
+		if err != nil {
+			return err
+		}
+		cursor, err := tasksDb.Find(context.TODO(), bson.D{{"created_by", workspaceId}, missingKey}, options.Find().SetSort(bson.D{{"_id", 1}}).SetProjection(bson.D{{"_id", 1}}))
+		if err != nil {
+			return err
+		}
+		tasks := make([]Task, 0)
+		if err := cursor.All(context.TODO(), &tasks); err != nil {
+			return err
+		}
+		first, err := reserveTaskNumbers(workspaceId, int64(len(tasks)))
+		if err != nil {
+			return err
+		}
+		for i, task := range tasks {
+			number := first + int64(i)
+			_, err := tasksDb.UpdateOne(context.TODO(),
+				bson.D{{"_id", task.Id}, missingKey},
+				bson.D{{"$set", bson.D{{"number", number}, {"key", formatTaskKey(prefix, number)}}}},
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// taskRefFilter builds a filter that finds a task by ObjectID or by its human-friendly key.
+func taskRefFilter(workspaceId bson.ObjectID, ref string) (bson.D, bool) {
+	if taskId, err := bson.ObjectIDFromHex(ref); err == nil {
+		return bson.D{{"_id", taskId}}, true
+	}
+	ref = strings.ToUpper(ref)
+	match := taskKeyRegex.FindStringSubmatch(ref)
+	if match == nil {
+		return nil, false
+	}
+	// A key with a previous prefix of the workspace still points at the same task number
+	if count, err := workspacesDb.CountDocuments(context.TODO(), bson.D{{"_id", workspaceId}, {"key_aliases", match[1]}}); err == nil && count > 0 {
+		number, err := strconv.ParseInt(match[2], 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		return bson.D{{"created_by", workspaceId}, {"number", number}}, true
+	}
+	return bson.D{{"created_by", workspaceId}, {"key", ref}}, true
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestBackfillTaskKeys(t *testing.T) {
+	useTestDatabase(t)
+	keyed := Workspace{Id: bson.NewObjectID(), Name: "Web", Key: "WEB"}
+	unkeyed := Workspace{Id: bson.NewObjectID(), Name: "Mobile app"}
+	if _, err := workspacesDb.InsertMany(context.TODO(), []any{keyed, unkeyed}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := countersDb.InsertOne(context.TODO(), bson.D{{"_id", keyed.Id}, {"seq", 1}}); err != nil {
+		t.Fatal(err)
+	}
+	tasks := []Task{
+		{Id: bson.NewObjectID(), Name: "Older", CreatedBy: keyed.Id},
+		{Id: bson.NewObjectID(), Name: "Keyed", CreatedBy: keyed.Id, Number: 1, Key: "WEB-1"},
+		{Id: bson.NewObjectID(), Name: "Newer", CreatedBy: keyed.Id},
+		{Id: bson.NewObjectID(), Name: "Only", CreatedBy: unkeyed.Id},
+	}
+	documents := make([]any, 0, len(tasks))
+	for _, task := range tasks {
+		documents = append(documents, task)
+	}
+	if _, err := tasksDb.InsertMany(context.TODO(), documents); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := backfillTaskKeys(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"WEB-2", "WEB-1", "WEB-3", "MOB-1"}
+	for i, task := range tasks {
+		var stored Task
+		if err := tasksDb.FindOne(context.TODO(), bson.D{{"_id", task.Id}}).Decode(&stored); err != nil {
+			t.Fatal(err)
+		}
+		if stored.Key != want[i] {
+			t.Errorf("%s: key = %q, want %q", task.Name, stored.Key, want[i])
+		}
+	}
+	number, err := nextTaskNumber(keyed.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if number != 4 {
+		t.Errorf("next number = %d, want 4", number)
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var colorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// uniqueLabels drops repeated label ids, keeping the first occurrence.
+func uniqueLabels(labelIds []bson.ObjectID) []bson.ObjectID {
+	unique := make([]bson.ObjectID, 0, len(labelIds))
+	for _, labelId := range labelIds {
+		if slices.Index(unique, labelId) == -1 {
+			unique = append(unique, labelId)
+		}
+	}
+	return unique
+}
+
+// validateLabels checks that every label id exists and belongs to the workspace.
+func validateLabels(workspaceId bson.ObjectID, labelIds []bson.ObjectID) (bool, error) {
+	unique := uniqueLabels(labelIds)
+	if len(unique) == 0 {
+		return true, nil
+	}
+	count, err := labelsDb.CountDocuments(context.TODO(), bson.D{
+		{"_id", bson.D{{"$in", unique}}},
+		{"workspace", workspaceId},
+	})
+	if err != nil {
+		return false, err
+	}
+	return count == int64(len(unique)), nil
+}
+
+// @Summary 		Get all labels
+// @Description 	Returns all labels defined in a workspace.
+// @Router 			/workspaces/{workspaceId}/labels [get]
+// @Tags 			Labels
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Success 		200 {object} AllLabelsResponse "A list of labels"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		500 {object} ErrorSwagger "Internal Server Error"
+func getAllLabels(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	cursor, err := labelsDb.Find(context.TODO(), bson.D{{"workspace", workspace.Id}})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	labels := make([]Label, 0)
+	if err := cursor.All(context.TODO(), &labels); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "failed to decode labels"})
+		return
+	}
+	c.JSON(200, gin.H{"labels": labels})
+}
+
+// @Summary 		Create a label
+// @Description 	Creates a new label in a workspace.
+// @Router 			/workspaces/{workspaceId}/labels [post]
+// @Tags 			Labels
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			data body CreateLabel true "Label creation data"
+// @Success 		200 {object} Label "The created label"
+// @Failure 		400 {object} ErrorSwagger "Bad request - no name given or bad color"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func createLabel(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	var input CreateLabel
+	if err := json.NewDecoder(c.Request.Body).Decode(&input); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return
+	} else if input.Name == "" {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'name' is not specified"})
+		return
+	} else if !colorRegex.MatchString(input.Color) {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'color' must be a hex color like #1e90ff"})
+		return
+	}
+	label := Label{
+		Name:      input.Name,
+		Color:     input.Color,
+		Workspace: workspace.Id,
+	}
+	result, err := labelsDb.InsertOne(context.TODO(), label)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to create label"})
+		return
+	}
+	label.Id = result.InsertedID.(bson.ObjectID)
+	c.JSON(200, label)
+}
+
+// @Summary 		Edit a label
+// @Description 	Changes the name or color of a label.
+// @Router 			/workspaces/{workspaceId}/labels/{labelId} [patch]
+// @Tags 			Labels
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			labelId path string true "Label ID"
+// @Param 			data body CreateLabel true "Fields to edit in the label"
+// @Success 		200 {object} Label "The updated label"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid input"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - label not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func editLabel(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	labelId, err := bson.ObjectIDFromHex(c.Param("labelId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid labelId"})
+		return
+	}
+	var valuesToEdit CreateLabel
+	if err := json.NewDecoder(c.Request.Body).Decode(&valuesToEdit); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return
+	}
+
+	var label Label
+	if err := labelsDb.FindOne(context.TODO(), bson.D{{"_id", labelId}, {"workspace", workspace.Id}}).Decode(&label); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.AbortWithStatusJSON(404, gin.H{"error": "Label does not exist"})
+		} else {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		}
+		return
+	}
+	if valuesToEdit.Name != "" {
+		label.Name = valuesToEdit.Name
+	}
+	if valuesToEdit.Color != "" {
+		if !colorRegex.MatchString(valuesToEdit.Color) {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Field 'color' must be a hex color like #1e90ff"})
+			return
+		}
+		label.Color = valuesToEdit.Color
+	}
+	if _, err := labelsDb.ReplaceOne(context.TODO(), bson.D{{"_id", labelId}}, label); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to update label"})
+		return
+	}
+	c.JSON(200, label)
+}
+
+// @Summary 		Delete a label
+// @Description 	Deletes a label and removes it from every task it was attached to.
+// @Router 			/workspaces/{workspaceId}/labels/{labelId} [delete]
+// @Tags 			Labels
+// @Security 		BearerAuth
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			labelId path string true "Label ID"
+// @Success 		200 "Label deleted successfully"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid label id"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - label not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func deleteLabel(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	labelId, err := bson.ObjectIDFromHex(c.Param("labelId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid labelId"})
+		return
+	}
+	result, err := labelsDb.DeleteOne(context.TODO(), bson.D{{"_id", labelId}, {"workspace", workspace.Id}})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to delete label"})
+		return
+	} else if result.DeletedCount == 0 {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Label does not exist"})
+		return
+	}
+	if _, err := tasksDb.UpdateMany(context.TODO(), bson.D{{"labels", labelId}}, bson.D{{"$pull", bson.D{{"labels", labelId}}}}); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to remove label from tasks"})
+		return
+	}
+	c.AbortWithStatus(200)
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	linkBlocks       = "blocks"
+	linkBlockedBy    = "blocked_by"
+	linkRelatesTo    = "relates_to"
+	linkDuplicates   = "duplicates"
+	linkDuplicatedBy = "duplicated_by"
+)
+
+// Every link is stored on both tasks, so each type maps to the type
+// that is written on the other side.
+var inverseLinkTypes = map[string]string{
+	linkBlocks:       linkBlockedBy,
+	linkBlockedBy:    linkBlocks,
+	linkRelatesTo:    linkRelatesTo,
+	linkDuplicates:   linkDuplicatedBy,
+	linkDuplicatedBy: linkDuplicates,
+}
+
+// MarshalJSON lists the links of tasks without any as an empty array instead of null. Tasks without
+// links don't store the field, so they decode with nil links.
+func (task Task) MarshalJSON() ([]byte, error) {
+	type plainTask Task
+	if task.Links == nil {
+		task.Links = make([]TaskLink, 0)
+	}
+	return json.Marshal(plainTask(task))
+}
+
+// hasBlockingPath reports whether "to" can be reached from "from" by following "blocks" edges.
+func hasBlockingPath(graph map[bson.ObjectID][]bson.ObjectID, from bson.ObjectID, to bson.ObjectID) bool {
+	visited := map[bson.ObjectID]bool{}
+	stack := []bson.ObjectID{from}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current == to {
+			return true
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		stack = append(stack, graph[current]...)
+	}
+	return false
+}
+
+func loadBlockingGraph(workspaceId bson.ObjectID) (map[bson.ObjectID][]bson.ObjectID, error) {
+	cursor, err := tasksDb.Find(context.TODO(),
+		bson.D{{"created_by", workspaceId}, {"links.type", linkBlocks}},
+		options.Find().SetProjection(bson.D{{"_id", 1}, {"links", 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]Task, 0)
+	if err := cursor.All(context.TODO(), &tasks); err != nil {
+		return nil, err
+	}
+	graph := map[bson.ObjectID][]bson.ObjectID{}
+	for _, task := range tasks {
+		for _, link := range task.Links {
+			if link.Type == linkBlocks {
+				graph[task.Id] = append(graph[task.Id], link.Task)
+			}
+		}
+	}
+	return graph, nil
+}
+
+// unfinishedBlockers returns ids of tasks that block the given task and are not completed yet.
+func unfinishedBlockers(task Task) ([]bson.ObjectID, error) {
+	blockerIds := make([]bson.ObjectID, 0)
+	for _, link := range task.Links {
+		if link.Type == linkBlockedBy {
+			blockerIds = append(blockerIds, link.Task)
+		}
+	}
+	if len(blockerIds) == 0 {
+		return blockerIds, nil
+	}
+	cursor, err := tasksDb.Find(context.TODO(),
+		bson.D{{"_id", bson.D{{"$in", blockerIds}}}, {"completed_at", bson.D{{"$in", bson.A{0, nil}}}}},
+		options.Find().SetProjection(bson.D{{"_id", 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	blockers := make([]Task, 0)
+	if err := cursor.All(context.TODO(), &blockers); err != nil {
+		return nil, err
+	}
+	unfinished := make([]bson.ObjectID, 0, len(blockers))
+	for _, blocker := range blockers {
+		unfinished = append(unfinished, blocker.Id)
+	}
+	return unfinished, nil
+}
+
+// @Summary 		Link two tasks
+// @Description 	Creates a typed link (blocks, relates_to, duplicates) between two tasks of the same workspace. Blocking links that would form a cycle are rejected.
+// @Router 			/workspaces/{workspaceId}/tasks/{taskId}/links [post]
+// @Tags 			Tasks
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			taskId path string true "Task ID or key like WEB-42"
+// @Param 			data body CreateTaskLink true "Link type and target task"
+// @Success 		200 {object} TaskLink "The created link"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid link"
+// @Failure 		403 {object} ErrorSwagger "Forbidden - you do not have access to this task"
+// @Failure 		404 {object} ErrorSwagger "Not Found - task not found"
+// @Failure 		409 {object} ErrorSwagger "Link already exists or would create a cycle"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func createTaskLink(c *gin.Context) {
+	taskInput, exists := c.Get("taskObj")
+	if !exists {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Task object not found in context"})
+		return
+	}
+	task := taskInput.(Task)
+
+	if !authorizeTaskAccess(c, task) {
+		return
+	}
+
+	var input CreateTaskLink
+	if err := json.NewDecoder(c.Request.Body).Decode(&input); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return
+	}
+	if input.Type != linkBlocks && input.Type != linkRelatesTo && input.Type != linkDuplicates {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'type' must be one of blocks, relates_to, duplicates"})
+		return
+	} else if input.Task.IsZero() {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'task' is not specified"})
+		return
+	} else if input.Task == task.Id {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Task can not be linked to itself"})
+		return
+	}
+
+	var target Task
+	if err := tasksDb.FindOne(context.TODO(), bson.D{{"_id", input.Task}}).Decode(&target); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.AbortWithStatusJSON(404, gin.H{"error": "Linked task does not exist"})
+		} else {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		}
+		return
+	}
+	if target.CreatedBy != task.CreatedBy {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Linked task does not belong to this workspace"})
+		return
+	}
+
+	link := TaskLink{Type: input.Type, Task: target.Id}
+	if slices.Contains(task.Links, link) {
+		c.AbortWithStatusJSON(409, gin.H{"error": "Link already exists"})
+		return
+	}
+
+	if input.Type == linkBlocks {
+		graph, err := loadBlockingGraph(task.CreatedBy)
+		if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		if hasBlockingPath(graph, target.Id, task.Id) {
+			c.AbortWithStatusJSON(409, gin.H{"error": "Link would create a blocking cycle"})
+			return
+		}
+	}
+
+	inverse := TaskLink{Type: inverseLinkTypes[input.Type], Task: task.Id}
+	if _, err := tasksDb.UpdateOne(context.TODO(), bson.D{{"_id", task.Id}}, bson.D{{"$addToSet", bson.D{{"links", link}}}}); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	if _, err := tasksDb.UpdateOne(context.TODO(), bson.D{{"_id", target.Id}}, bson.D{{"$addToSet", bson.D{{"links", inverse}}}}); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(200, link)
+}
+
+// @Summary 		Remove a task link
+// @Description 	Removes every link between two tasks, on both sides.
+// @Router 			/workspaces/{workspaceId}/tasks/{taskId}/links/{linkedTaskId} [delete]
+// @Tags 			Tasks
+// @Security 		BearerAuth
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			taskId path string true "Task ID or key like WEB-42"
+// @Param 			linkedTaskId path string true "Linked task ID or key"
+// @Success 		200 "Link removed successfully"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid linked task id"
+// @Failure 		403 {object} ErrorSwagger "Forbidden - you do not have access to this task"
+// @Failure 		404 {object} ErrorSwagger "Not Found - task or linked task not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func deleteTaskLink(c *gin.Context) {
+	taskInput, exists := c.Get("taskObj")
+	if !exists {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Task object not found in context"})
+		return
+	}
+	task := taskInput.(Task)
+
+	if !authorizeTaskAccess(c, task) {
+		return
+	}
+
+	linkedFilter, ok := taskRefFilter(task.CreatedBy, c.Param("linkedTaskId"))
+	if !ok {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Invalid linked task id"})
+		return
+	}
+	var linked Task
+	if err := tasksDb.FindOne(context.TODO(), linkedFilter).Decode(&linked); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.AbortWithStatusJSON(404, gin.H{"error": "Linked task does not exist"})
+		} else {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		}
+		return
+	}
+	linkedTaskId := linked.Id
+	if _, err := tasksDb.UpdateOne(context.TODO(), bson.D{{"_id", task.Id}}, bson.D{{"$pull", bson.D{{"links", bson.D{{"task", linkedTaskId}}}}}}); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	if _, err := tasksDb.UpdateOne(context.TODO(), bson.D{{"_id", linkedTaskId}}, bson.D{{"$pull", bson.D{{"links", bson.D{{"task", task.Id}}}}}}); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.AbortWithStatus(200)
+}
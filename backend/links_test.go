@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestHasBlockingPath(t *testing.T) {
+	a, b, c, d, e := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+	// a blocks b and c, b blocks d, d blocks b, e is on its own
+	graph := map[bson.ObjectID][]bson.ObjectID{
+		a: {b, c},
+		b: {d},
+		d: {b},
+	}
+	tests := []struct {
+		name string
+		from bson.ObjectID
+		to   bson.ObjectID
+		want bool
+	}{
+		{"direct edge", a, b, true},
+		{"through another task", a, d, true},
+		{"same task", e, e, true},
+		{"against the edges", d, a, false},
+		{"unrelated task", a, e, false},
+		{"leaf task", c, a, false},
+		{"cycle without the target", b, c, false},
+		{"inside a cycle", d, b, true},
+	}
+	for _, test := range tests {
+		if got := hasBlockingPath(graph, test.from, test.to); got != test.want {
+			t.Errorf("%s: hasBlockingPath = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestTaskLinksJson(t *testing.T) {
+	linked := bson.NewObjectID()
+	tests := []struct {
+		task Task
+		want string
+	}{
+		{Task{Name: "Ship it"}, `"links":[]`},
+		{Task{Name: "Ship it", Links: []TaskLink{}}, `"links":[]`},
+		{Task{Name: "Ship it", Links: []TaskLink{{Type: linkBlocks, Task: linked}}}, `"links":[{"type":"blocks","task":"` + linked.Hex() + `"}]`},
+	}
+	for _, test := range tests {
+		for _, value := range []any{test.task, &test.task, []Task{test.task}} {
+			data, err := json.Marshal(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), test.want) || !strings.Contains(string(data), `"name":"Ship it"`) {
+				t.Errorf("json.Marshal(%T) = %s, want %s", value, data, test.want)
+			}
+		}
+	}
+}
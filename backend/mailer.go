@@ -0,0 +1,96 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// Email is a message with a plain-text and an optional HTML body.
+type Email struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	Headers map[string]string // extra headers like List-Unsubscribe
+}
+
+// Mailer sends a single email.
+type Mailer interface {
+	Send(email Email) error
+}
+
+// smtpMailer delivers through any SMTP server, including local sinks like MailHog or smtp4dev.
+type smtpMailer struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+// logMailer is used when no SMTP server is configured, so emails are only logged.
+type logMailer struct{}
+
+var mailer = newMailer()
+
+func newMailer() Mailer {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return logMailer{}
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "25"
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = "rela@localhost"
+	}
+	return smtpMailer{
+		Addr:     net.JoinHostPort(host, port),
+		From:     from,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+	}
+}
+
+func (m smtpMailer) Send(email Email) error {
+	var auth smtp.Auth
+	if m.Username != "" {
+		host, _, _ := net.SplitHostPort(m.Addr)
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+	boundaryBytes := make([]byte, 12)
+	if _, err := rand.Read(boundaryBytes); err != nil {
+		return err
+	}
+	boundary := hex.EncodeToString(boundaryBytes)
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "From: %s\r\n", m.From)
+	stripNewlines := strings.NewReplacer("\r", "", "\n", "")
+	fmt.Fprintf(&message, "To: %s\r\n", email.To)
+	fmt.Fprintf(&message, "Subject: %s\r\n", stripNewlines.Replace(email.Subject))
+	for name, value := range email.Headers {
+		fmt.Fprintf(&message, "%s: %s\r\n", name, stripNewlines.Replace(value))
+	}
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().UTC().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", boundary)
+	fmt.Fprintf(&message, "--%s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n", boundary, email.Text)
+	if email.HTML != "" {
+		fmt.Fprintf(&message, "--%s\r\nContent-Type: text/html; charset=UTF-8\r\n\r\n%s\r\n", boundary, email.HTML)
+	}
+	fmt.Fprintf(&message, "--%s--\r\n", boundary)
+	return smtp.SendMail(m.Addr, auth, m.From, []string{email.To}, []byte(message.String()))
+}
+
+func (logMailer) Send(email Email) error {
+	println("Email to", email.To, "-", email.Subject)
+	return nil
+}
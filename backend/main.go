@@ -23,15 +23,39 @@ var port = os.Getenv("PORT")
 var pepper = os.Getenv("PEPPER")
 var mongodbCredentials = os.Getenv("MONGO_CREDS")
 var frontendOriginEnv = os.Getenv("FRONTEND_ORIGINS")
-var dbClient, _ = mongo.Connect(options.Client().ApplyURI(mongodbCredentials).SetServerAPIOptions(options.ServerAPI(options.ServerAPIVersion1)).SetMaxPoolSize(100).SetMinPoolSize(10).SetMaxConnIdleTime(30 * time.Second))
+var dbClient, _ = mongo.Connect(mongoClientOptions())
 
 var tasksDb = dbClient.Database("rela").Collection("tasks")
 var usersDb = dbClient.Database("rela").Collection("users")
 var boardsDb = dbClient.Database("rela").Collection("boards")
 var workspacesDb = dbClient.Database("rela").Collection("workspaces")
+var labelsDb = dbClient.Database("rela").Collection("labels")
+var customFieldsDb = dbClient.Database("rela").Collection("custom_fields")
+var countersDb = dbClient.Database("rela").Collection("counters")
+var viewsDb = dbClient.Database("rela").Collection("views")
+var notificationsDb = dbClient.Database("rela").Collection("notifications")
+var remindersDb = dbClient.Database("rela").Collection("reminders")
+var eventsDb = dbClient.Database("rela").Collection("events")
+var webhooksDb = dbClient.Database("rela").Collection("webhooks")
+var webhookDeliveriesDb = dbClient.Database("rela").Collection("webhook_deliveries")
+var jobsDb = dbClient.Database("rela").Collection("jobs")
+var feedsDb = dbClient.Database("rela").Collection("feeds")
+var appPasswordsDb = dbClient.Database("rela").Collection("app_passwords")
+var exportTemplatesDb = dbClient.Database("rela").Collection("export_templates")
+var workspaceTemplatesDb = dbClient.Database("rela").Collection("workspace_templates")
 
 var emailRegex = regexp.MustCompile(`^[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}$`)
 
+// mongoClientOptions configures the MongoDB client. Without credentials main refuses to start, the
+// client is still created so tests that don't need a database can run.
+func mongoClientOptions() *options.ClientOptions {
+	clientOptions := options.Client().SetServerAPIOptions(options.ServerAPI(options.ServerAPIVersion1)).SetMaxPoolSize(100).SetMinPoolSize(10).SetMaxConnIdleTime(30 * time.Second)
+	if mongodbCredentials != "" {
+		clientOptions.ApplyURI(mongodbCredentials)
+	}
+	return clientOptions
+}
+
 func getAllowedOrigins() []string {
 	if frontendOriginEnv == "" {
 		return []string{"http://localhost:5173", "http://localhost:8000", "http://localhost:5174"}
@@ -51,6 +75,9 @@ func getAllowedOrigins() []string {
 // @in header
 // @name Authorization
 func main() {
+	if mongodbCredentials == "" {
+		panic("FATAL MongoDB credentials are not present")
+	}
 	r := gin.Default()
 	r.RedirectTrailingSlash = false // Explicitly disable automatic redirects
 	corsConfig := cors.DefaultConfig()
@@ -87,6 +114,9 @@ func main() {
 			usersGroup.POST("/logout", logoutUser)
 		}
 		protected.PATCH("/users/update_info", updateUserInfo)
+		// Unsubscribe links in emails work without logging in
+		v1.GET("/users/unsubscribe", unsubscribeDigest)
+		v1.POST("/users/unsubscribe", unsubscribeDigest)
 		// Protected User Routes
 		protectedUsersGroup := protected.Group("/users")
 		{
@@ -94,13 +124,40 @@ func main() {
 			protectedUsersGroup.DELETE("/delete", deleteUser)
 			protectedUsersGroup.POST("/upload_avatar", uploadAvatar)
 			protectedUsersGroup.GET("/get_info", getUserDetails)
+			protectedUsersGroup.GET("/watched_tasks", getWatchedTasks)
+			protectedUsersGroup.GET("/app_passwords", getAllAppPasswords)
+			protectedUsersGroup.POST("/app_passwords", createAppPassword)
+			protectedUsersGroup.DELETE("/app_passwords/:passwordId", deleteAppPassword)
 		}
 
+		// Background jobs
+		protected.GET("/jobs", getAllJobs)
+		protected.GET("/jobs/:jobId", getJob)
+
+		// Feeds
+		protected.GET("/feeds", getAllFeeds)
+		protected.POST("/feeds", createFeed)
+		protected.POST("/feeds/:feedId/token", regenerateFeedToken)
+		protected.DELETE("/feeds/:feedId", deleteFeed)
+
+		// Notifications
+		protected.GET("/notifications", getAllNotifications)
+		protected.POST("/notifications/read_all", readAllNotifications)
+		protected.PATCH("/notifications/:notificationId", editNotification)
+		protected.DELETE("/notifications/:notificationId", deleteNotification)
+
+		// Search
+		protected.GET("/search", searchTasks)
+
 		// Workspaces
 		workspacesGroup := protected.Group("/workspaces")
 		workspaceByIdGroup := workspacesGroup.Group("/:workspaceId")
 		{
 			workspacesGroup.POST("/create", createWorkspace)
+			workspacesGroup.POST("/import/trello", importTrelloWorkspace)
+			workspacesGroup.POST("/restore", restoreWorkspaceBackup)
+			workspacesGroup.GET("/templates", getAllWorkspaceTemplates)
+			workspacesGroup.DELETE("/templates/:templateId", deleteWorkspaceTemplate)
 			workspacesGroup.POST("/invite/accept/:joinToken", addMember)
 			r.GET("/workspaces/invite/:joinToken", getWorkspaceByInviteToken)
 
@@ -112,18 +169,90 @@ func main() {
 			workspaceByIdGroup.PATCH("/", editWorkspace)
 			workspaceByIdGroup.DELETE("/", deleteWorkspace)
 			workspaceByIdGroup.POST("/upload_avatar", uploadAvatar)
+			workspaceByIdGroup.POST("/template", createWorkspaceTemplate)
+			workspaceByIdGroup.POST("/duplicate", duplicateWorkspaceJob)
 
 			// Workspace Tasks
 			workspaceByIdGroup.GET("/tasks/:boardId", getAllTasks)
 			workspaceByIdGroup.POST("/tasks", createNewTask)
 			workspaceByIdGroup.PATCH("/tasks/:taskId", taskMiddleware(), editExistingTask)
 			workspaceByIdGroup.DELETE("/delete/:taskId", taskMiddleware(), deleteExistingTask)
+			workspaceByIdGroup.POST("/assign", assignTask)
+			workspaceByIdGroup.POST("/unassign", unassignTask)
+			workspaceByIdGroup.POST("/tasks/:taskId/links", taskMiddleware(), createTaskLink)
+			workspaceByIdGroup.DELETE("/tasks/:taskId/links/:linkedTaskId", taskMiddleware(), deleteTaskLink)
+			workspaceByIdGroup.POST("/tasks/:taskId/watch", taskMiddleware(), watchTask)
+			workspaceByIdGroup.DELETE("/tasks/:taskId/watch", taskMiddleware(), unwatchTask)
 
 			// Workspace Boards
 			workspaceByIdGroup.GET("/boards", getAllBoards)
 			workspaceByIdGroup.POST("/boards", addBoard)
 			workspaceByIdGroup.DELETE("/boards/:boardId", deleteBoard)
 			workspaceByIdGroup.PATCH("/boards/:boardId", editBoard)
+			workspaceByIdGroup.POST("/boards/:boardId/watch", watchBoard)
+			workspaceByIdGroup.DELETE("/boards/:boardId/watch", unwatchBoard)
+			workspaceByIdGroup.POST("/boards/:boardId/duplicate", duplicateBoard)
+			workspaceByIdGroup.POST("/boards/:boardId/transfer", transferBoard)
+			workspaceByIdGroup.GET("/boards/:boardId/export.csv", exportBoardCsv)
+			workspaceByIdGroup.GET("/boards/:boardId/export.md", exportBoardMarkdown)
+
+			// Import and export
+			workspaceByIdGroup.GET("/export.csv", exportWorkspaceCsv)
+			workspaceByIdGroup.POST("/import.csv", importTasksCsv)
+			workspaceByIdGroup.POST("/import/trello", importTrelloBoard)
+			workspaceByIdGroup.POST("/import/jira", importJiraCsv)
+			workspaceByIdGroup.POST("/import/github", importGithubIssues)
+			workspaceByIdGroup.GET("/backup", exportWorkspaceBackup)
+			workspaceByIdGroup.GET("/export.md", exportTasksMarkdown)
+			workspaceByIdGroup.GET("/export_templates", getAllExportTemplates)
+			workspaceByIdGroup.POST("/export_templates", createExportTemplate)
+			workspaceByIdGroup.PATCH("/export_templates/:templateId", editExportTemplate)
+			workspaceByIdGroup.DELETE("/export_templates/:templateId", deleteExportTemplate)
+
+			// Workspace Labels
+			workspaceByIdGroup.GET("/labels", getAllLabels)
+			workspaceByIdGroup.POST("/labels", createLabel)
+			workspaceByIdGroup.PATCH("/labels/:labelId", editLabel)
+			workspaceByIdGroup.DELETE("/labels/:labelId", deleteLabel)
+
+			// Workspace Custom Fields
+			workspaceByIdGroup.GET("/fields", getAllCustomFields)
+			workspaceByIdGroup.POST("/fields", createCustomField)
+			workspaceByIdGroup.PATCH("/fields/:fieldId", editCustomField)
+			workspaceByIdGroup.DELETE("/fields/:fieldId", deleteCustomField)
+
+			// Workspace Saved Views
+			workspaceByIdGroup.GET("/views", getAllSavedViews)
+			workspaceByIdGroup.POST("/views", createSavedView)
+			workspaceByIdGroup.PATCH("/views/:viewId", editSavedView)
+			workspaceByIdGroup.DELETE("/views/:viewId", deleteSavedView)
+			workspaceByIdGroup.GET("/views/:viewId/tasks", getSavedViewTasks)
+
+			// Workspace Webhooks
+			workspaceByIdGroup.GET("/webhooks", getAllWebhooks)
+			workspaceByIdGroup.POST("/webhooks", createWebhook)
+			workspaceByIdGroup.PATCH("/webhooks/:webhookId", editWebhook)
+			workspaceByIdGroup.DELETE("/webhooks/:webhookId", deleteWebhook)
+			workspaceByIdGroup.POST("/webhooks/:webhookId/ping", pingWebhook)
+			workspaceByIdGroup.GET("/webhooks/:webhookId/deliveries", getWebhookDeliveries)
+			workspaceByIdGroup.POST("/webhooks/:webhookId/deliveries/:deliveryId/redeliver", redeliverWebhook)
+
+			// Event stream tickets
+			workspaceByIdGroup.POST("/events/ticket", createStreamTicket)
+		}
+
+		// Event stream, EventSource can only authenticate through a ticket in the query string
+		v1.GET("/workspaces/:workspaceId/events", streamTicketMiddleware(), streamEvents)
+
+		// Calendar apps can't send a bearer header, the secret feed token authenticates
+		v1.GET("/ical/:token", getIcalFeed)
+		v1.GET("/atom/:token", getAtomFeed)
+
+		// CalDAV, clients sign in with an app password
+		caldavGroup := v1.Group("/caldav", appPasswordMiddleware())
+		for _, method := range caldavMethods {
+			caldavGroup.Handle(method, "/*path", serveCaldav)
+			r.Handle(method, "/.well-known/caldav", redirectCaldav)
 		}
 
 		// Public invite route
@@ -133,10 +262,23 @@ func main() {
 		v1.GET("/docs/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 	}
 
+	if err := ensureIndexes(); err != nil {
+		println("WARNING Failed to create database indexes: ", err.Error())
+	}
+	go func() {
+		if err := backfillTaskKeys(); err != nil {
+			println("WARNING Failed to assign keys to older tasks: ", err.Error())
+		}
+	}()
+	go runRecurrenceScheduler()
+	go runReminderScheduler()
+	go runEventHub()
+	go runWebhookWorker()
+	go runDigestScheduler()
+	go runJobJanitor()
+
 	if pepper == "" {
 		print("WARNING Server-side secret is not present, this is a big security flaw")
-	} else if mongodbCredentials == "" {
-		panic("FATAL MongoDB credentials are not present")
 	} else if port == "" {
 		print("WARNING Port is not present, falling back to default")
 		if err := r.Run(":8080"); err != nil {
@@ -0,0 +1,758 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	maxMarkdownTasks  = 5000
+	maxExportTemplate = 64 << 10
+	maxMarkdownOutput = 16 << 20
+	// Uploaded templates are limited in size and nesting, and rendering is abandoned after the timeout
+	maxTemplateNodes      = 2000
+	maxTemplateDepth      = 12
+	maxTemplateRangeDepth = 3
+	markdownRenderTimeout = 10 * time.Second
+	markdownGroupStatus   = "status"
+	markdownGroupLabel    = "label"
+)
+
+// Checklists are Markdown task lists in descriptions, like the ones importers write
+var checklistItemRegex = regexp.MustCompile(`(?m)^\s*[-*+] \[([ xX])\] (.+)$`)
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "#", `\#`, "|", `\|`, "<", `\<`, ">", `\>`)
+
+var markdownFuncs = template.FuncMap{
+	// escape makes text safe to use inline in Markdown
+	"escape": func(value string) string {
+		return markdownEscaper.Replace(value)
+	},
+	"join": func(values []string, separator string) string {
+		return strings.Join(values, separator)
+	},
+	"date": func(timestamp int64) string {
+		if timestamp == 0 {
+			return ""
+		}
+		return time.Unix(timestamp, 0).UTC().Format("2006-01-02")
+	},
+	"datetime": func(timestamp int64) string {
+		if timestamp == 0 {
+			return ""
+		}
+		return time.Unix(timestamp, 0).UTC().Format("2006-01-02 15:04 UTC")
+	},
+	"indent": func(spaces int, value string) string {
+		return strings.ReplaceAll(value, "\n", "\n"+strings.Repeat(" ", spaces))
+	},
+}
+
+// The template used when no uploaded one is chosen
+const defaultMarkdownTemplate = `# {{escape .Title}}
+
+Exported {{datetime .ExportedAt}}, {{len .Tasks}} tasks grouped by {{.GroupBy}}.
+{{range .Groups}}
+## {{escape .Name}} ({{len .Tasks}})
+{{range .Tasks}}
+- [{{if .Completed}}x{{else}} {{end}}] **{{.Key}}** {{escape .Name}}
+{{- if .Assignees}} · {{escape (join .Assignees ", ")}}{{end}}
+{{- if .Deadline}} · due {{date .Deadline}}{{end}}
+{{- if .Checklist.Total}} · checklist {{.Checklist.Done}}/{{.Checklist.Total}}{{end}}
+{{- end}}
+{{end}}`
+
+type markdownChecklistItem struct {
+	Text string
+	Done bool
+}
+
+type markdownChecklist struct {
+	Done  int
+	Total int
+	Items []markdownChecklistItem
+}
+
+// markdownTask is a task as export templates see it, with names instead of ids.
+type markdownTask struct {
+	Key         string
+	Name        string
+	Description string
+	Url         string
+	Board       string
+	Status      string
+	Completed   bool
+	Priority    string
+	Labels      []string
+	Assignees   []string
+	Deadline    int64
+	CreatedAt   int64
+	CompletedAt int64
+	Checklist   markdownChecklist
+}
+
+type markdownGroup struct {
+	Name  string
+	Tasks []markdownTask
+}
+
+// markdownExport is the data export templates are executed with.
+type markdownExport struct {
+	Title      string
+	Workspace  string
+	Board      string
+	GroupBy    string
+	ExportedAt int64
+	Tasks      []markdownTask
+	Groups     []markdownGroup
+}
+
+func parseChecklist(description string) markdownChecklist {
+	checklist := markdownChecklist{Items: make([]markdownChecklistItem, 0)}
+	for _, match := range checklistItemRegex.FindAllStringSubmatch(description, -1) {
+		item := markdownChecklistItem{Text: strings.TrimSpace(match[2]), Done: match[1] != " "}
+		checklist.Items = append(checklist.Items, item)
+		checklist.Total++
+		if item.Done {
+			checklist.Done++
+		}
+	}
+	return checklist
+}
+
+func parseExportTemplate(source string) (*template.Template, error) {
+	tmpl, err := template.New("export").Funcs(markdownFuncs).Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTemplateTree(tmpl); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// checkTemplateTree refuses templates that could keep an export busy without writing anything:
+// large or deeply nested ones, loops nested more than a few levels or over number literals, inner
+// loops over variables, which can start over at all tasks, and sub-templates, which can call each
+// other recursively.
+func checkTemplateTree(tmpl *template.Template) error {
+	if len(tmpl.Templates()) > 1 {
+		return errors.New("templates can not define sub-templates")
+	}
+	nodes := 0
+	var walk func(node parse.Node, depth int, ranges int) error
+	walkList := func(list *parse.ListNode, depth int, ranges int) error {
+		if list == nil {
+			return nil
+		}
+		for _, node := range list.Nodes {
+			if err := walk(node, depth, ranges); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	walk = func(node parse.Node, depth int, ranges int) error {
+		if nodes++; nodes > maxTemplateNodes {
+			return fmt.Errorf("templates can have at most %d actions and text blocks", maxTemplateNodes)
+		} else if depth > maxTemplateDepth {
+			return fmt.Errorf("templates can be nested at most %d levels deep", maxTemplateDepth)
+		}
+		switch node := node.(type) {
+		case *parse.ListNode:
+			return walkList(node, depth+1, ranges)
+		case *parse.IfNode:
+			return errors.Join(walkList(node.List, depth+1, ranges), walkList(node.ElseList, depth+1, ranges))
+		case *parse.WithNode:
+			if ranges > 0 && usesVariable(node.Pipe) {
+				return errors.New("with inside range can only use fields of the current item")
+			}
+			return errors.Join(walkList(node.List, depth+1, ranges), walkList(node.ElseList, depth+1, ranges))
+		case *parse.RangeNode:
+			if ranges+1 > maxTemplateRangeDepth {
+				return fmt.Errorf("range can be nested at most %d levels deep", maxTemplateRangeDepth)
+			} else if ranges > 0 && usesVariable(node.Pipe) {
+				return errors.New("range inside range can only use fields of the current item")
+			}
+			for _, command := range node.Pipe.Cmds {
+				for _, arg := range command.Args {
+					if _, ok := arg.(*parse.NumberNode); ok {
+						return errors.New("range over a number is not supported")
+					}
+				}
+			}
+			return errors.Join(walkList(node.List, depth+1, ranges+1), walkList(node.ElseList, depth+1, ranges))
+		case *parse.TemplateNode:
+			return errors.New("templates can not call sub-templates")
+		}
+		return nil
+	}
+	if tmpl.Tree == nil {
+		return nil
+	}
+	return walk(tmpl.Tree.Root, 0, 0)
+}
+
+func usesVariable(pipe *parse.PipeNode) bool {
+	if pipe == nil {
+		return false
+	}
+	for _, command := range pipe.Cmds {
+		for _, arg := range command.Args {
+			switch arg := arg.(type) {
+			case *parse.VariableNode:
+				return true
+			case *parse.PipeNode:
+				if usesVariable(arg) {
+					return true
+				}
+			case *parse.ChainNode:
+				if _, ok := arg.Node.(*parse.VariableNode); ok {
+					return true
+				} else if inner, ok := arg.Node.(*parse.PipeNode); ok && usesVariable(inner) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// limitedWriter fails once a template wrote more than it may, or after rendering was abandoned.
+type limitedWriter struct {
+	buffer    bytes.Buffer
+	limit     int
+	abandoned atomic.Bool
+}
+
+func (w *limitedWriter) Write(data []byte) (int, error) {
+	if w.abandoned.Load() {
+		return 0, errors.New("the export took too long")
+	} else if w.buffer.Len()+len(data) > w.limit {
+		return 0, errors.New("the export is too large")
+	}
+	return w.buffer.Write(data)
+}
+
+// renderExportTemplate executes a template in the background and gives up after the timeout. The
+// template stops at its next write, the checks of checkTemplateTree keep it from running long without one.
+func renderExportTemplate(tmpl *template.Template, export markdownExport) ([]byte, error) {
+	output := &limitedWriter{limit: maxMarkdownOutput}
+	done := make(chan error, 1)
+	go func() {
+		done <- tmpl.Execute(output, export)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return nil, err
+		}
+		return output.buffer.Bytes(), nil
+	case <-time.After(markdownRenderTimeout):
+		output.abandoned.Store(true)
+		return nil, fmt.Errorf("the export took longer than %s", markdownRenderTimeout)
+	}
+}
+
+// validateExportTemplate parses a template and runs it on sample data, so templates referring to
+// fields that don't exist are refused on upload rather than on export.
+func validateExportTemplate(source string) error {
+	tmpl, err := parseExportTemplate(source)
+	if err != nil {
+		return err
+	}
+	sample := markdownTask{
+		Key: "KEY-1", Name: "Sample task", Description: "- [x] First\n- [ ] Second", Url: webUrl("/"), Board: "Board",
+		Status: "open", Priority: "high", Labels: []string{"Label"}, Assignees: []string{"Member"}, Deadline: time.Now().Unix(),
+		CreatedAt: time.Now().Unix(), Checklist: parseChecklist("- [x] First\n- [ ] Second"),
+	}
+	export := markdownExport{
+		Title: "Sample", Workspace: "Workspace", Board: "Board", GroupBy: markdownGroupStatus, ExportedAt: time.Now().Unix(),
+		Tasks: []markdownTask{sample}, Groups: []markdownGroup{{Name: "Open", Tasks: []markdownTask{sample}}},
+	}
+	_, err = renderExportTemplate(tmpl, export)
+	return err
+}
+
+// buildMarkdownExport resolves the ids of tasks to names and groups them by status or label.
+func buildMarkdownExport(workspace Workspace, tasks []Task, groupBy string) (markdownExport, error) {
+	export := markdownExport{Workspace: workspace.Name, GroupBy: groupBy, ExportedAt: time.Now().UTC().Unix(), Tasks: make([]markdownTask, 0, len(tasks))}
+	users, err := workspaceUsers(workspace)
+	if err != nil {
+		return export, err
+	}
+	userNames := map[bson.ObjectID]string{}
+	for _, user := range users {
+		userNames[user.Id] = user.Name
+	}
+	labels, err := workspaceLabels(workspace.Id)
+	if err != nil {
+		return export, err
+	}
+	labelNames := map[bson.ObjectID]string{}
+	for _, label := range labels {
+		labelNames[label.Id] = label.Name
+	}
+	boards, err := workspaceBoards(workspace.Id)
+	if err != nil {
+		return export, err
+	}
+	boardNames := map[bson.ObjectID]string{}
+	for _, board := range boards {
+		boardNames[board.Id] = board.Name
+	}
+
+	open := markdownGroup{Name: "Open", Tasks: make([]markdownTask, 0)}
+	done := markdownGroup{Name: "Done", Tasks: make([]markdownTask, 0)}
+	unlabeled := markdownGroup{Name: "No label", Tasks: make([]markdownTask, 0)}
+	byLabel := map[string]*markdownGroup{}
+	for _, task := range tasks {
+		item := markdownTask{
+			Key:         task.Key,
+			Name:        task.Name,
+			Description: task.Description,
+			Url:         taskWebUrl(task),
+			Board:       boardNames[task.Board],
+			Status:      "open",
+			Completed:   task.CompletedAt != 0,
+			Priority:    task.Priority,
+			Labels:      make([]string, 0),
+			Assignees:   make([]string, 0),
+			Deadline:    task.Deadline,
+			CreatedAt:   task.CreatedAt,
+			CompletedAt: task.CompletedAt,
+			Checklist:   parseChecklist(task.Description),
+		}
+		if item.Completed {
+			item.Status = "done"
+		}
+		for _, label := range task.Labels {
+			if name, ok := labelNames[label]; ok {
+				item.Labels = append(item.Labels, name)
+			}
+		}
+		for _, assignee := range task.Assignees {
+			if name, ok := userNames[assignee]; ok {
+				item.Assignees = append(item.Assignees, name)
+			}
+		}
+		export.Tasks = append(export.Tasks, item)
+
+		if groupBy == markdownGroupLabel {
+			if len(item.Labels) == 0 {
+				unlabeled.Tasks = append(unlabeled.Tasks, item)
+			}
+			// Tasks show up under each of their labels
+			for _, label := range item.Labels {
+				group, ok := byLabel[label]
+				if !ok {
+					group = &markdownGroup{Name: label, Tasks: make([]markdownTask, 0)}
+					byLabel[label] = group
+				}
+				group.Tasks = append(group.Tasks, item)
+			}
+		} else if item.Completed {
+			done.Tasks = append(done.Tasks, item)
+		} else {
+			open.Tasks = append(open.Tasks, item)
+		}
+	}
+
+	export.Groups = make([]markdownGroup, 0)
+	if groupBy == markdownGroupLabel {
+		names := make([]string, 0, len(byLabel))
+		for name := range byLabel {
+			names = append(names, name)
+		}
+		slices.SortFunc(names, func(a, b string) int { return strings.Compare(strings.ToLower(a), strings.ToLower(b)) })
+		for _, name := range names {
+			export.Groups = append(export.Groups, *byLabel[name])
+		}
+		if len(unlabeled.Tasks) != 0 {
+			export.Groups = append(export.Groups, unlabeled)
+		}
+	} else {
+		export.Groups = append(export.Groups, open, done)
+	}
+	return export, nil
+}
+
+// exportMarkdown renders the tasks matched by filter with the default or the requested template.
+func exportMarkdown(c *gin.Context, workspace Workspace, filter bson.D, title string, board string) {
+	groupBy := c.DefaultQuery("group_by", markdownGroupStatus)
+	if groupBy != markdownGroupStatus && groupBy != markdownGroupLabel {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'group_by' must be status or label"})
+		return
+	}
+	source := defaultMarkdownTemplate
+	if t := c.Query("template"); t != "" {
+		templateId, err := bson.ObjectIDFromHex(t)
+		if err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": "invalid template"})
+			return
+		}
+		var exportTemplate ExportTemplate
+		err = exportTemplatesDb.FindOne(context.TODO(), bson.D{{"_id", templateId}, {"workspace", workspace.Id}}).Decode(&exportTemplate)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.AbortWithStatusJSON(404, gin.H{"error": "Template does not exist"})
+			return
+		} else if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		source = exportTemplate.Template
+	}
+	tmpl, err := parseExportTemplate(source)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Template is invalid: " + err.Error()})
+		return
+	}
+
+	cursor, err := tasksDb.Find(context.TODO(), filter, options.Find().SetSort(bson.D{{"number", 1}, {"_id", 1}}).SetLimit(maxMarkdownTasks+1))
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	tasks := make([]Task, 0)
+	if err := cursor.All(context.TODO(), &tasks); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	} else if len(tasks) > maxMarkdownTasks {
+		c.AbortWithStatusJSON(400, gin.H{"error": fmt.Sprintf("Exports are limited to %d tasks, narrow down the filter", maxMarkdownTasks)})
+		return
+	}
+	export, err := buildMarkdownExport(workspace, tasks, groupBy)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	export.Title = title
+	export.Board = board
+
+	output, err := renderExportTemplate(tmpl, export)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Template failed: " + err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.md"`, csvFilenameRegex.ReplaceAllString(title, "_")))
+	c.Data(200, "text/markdown; charset=utf-8", output)
+}
+
+// @Summary 		Export a board as Markdown
+// @Description 	Renders the tasks of a board grouped by status or label, with keys, assignees, deadlines and the state of checklists in descriptions. Takes the same filters as the task list and an optional uploaded template.
+// @Router 			/workspaces/{workspaceId}/boards/{boardId}/export.md [get]
+// @Tags 			Import and export
+// @Security 		BearerAuth
+// @Produce 		text/markdown
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			boardId path string true "Board ID"
+// @Param 			group_by query string false "Group tasks by status or label, default status" Enums(status, label)
+// @Param 			template query string false "ID of an export template of the workspace"
+// @Param 			status query string false "Only open or done tasks"
+// @Param 			label query string false "Only tasks with this label ID"
+// @Param 			assignee query string false "Only tasks assigned to this user ID, or me"
+// @Success 		200 {string} string "The Markdown document"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid filter, grouping or a failing template"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - board or template not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func exportBoardMarkdown(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	boardId, err := bson.ObjectIDFromHex(c.Param("boardId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid boardId"})
+		return
+	}
+	var board Board
+	err = boardsDb.FindOne(context.TODO(), bson.D{{"_id", boardId}, {"owned_by", workspace.Id}}).Decode(&board)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Board does not exist"})
+		return
+	} else if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	query, err := taskQueryFromRequest(c)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	filter, err := taskQueryFilter(workspace.Id, userId.(bson.ObjectID), query)
+	if errors.Is(err, errInvalidTaskQuery) {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	exportMarkdown(c, workspace, append(filter, bson.E{"board", board.Id}), workspace.Name+" - "+board.Name, board.Name)
+}
+
+// @Summary 		Export tasks as Markdown
+// @Description 	Renders the tasks of a workspace matching the filters of the task list, grouped by status or label, with keys, assignees, deadlines and the state of checklists in descriptions. At most 5000 tasks.
+// @Router 			/workspaces/{workspaceId}/export.md [get]
+// @Tags 			Import and export
+// @Security 		BearerAuth
+// @Produce 		text/markdown
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			group_by query string false "Group tasks by status or label, default status" Enums(status, label)
+// @Param 			template query string false "ID of an export template of the workspace"
+// @Param 			status query string false "Only open or done tasks"
+// @Param 			label query string false "Only tasks with this label ID"
+// @Param 			assignee query string false "Only tasks assigned to this user ID, or me"
+// @Param 			due query string false "Relative deadline like overdue, <3d or >1w"
+// @Param 			q query string false "Text in the name or description"
+// @Success 		200 {string} string "The Markdown document"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid filter, grouping, too many tasks or a failing template"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - workspace or template not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func exportTasksMarkdown(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	query, err := taskQueryFromRequest(c)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	filter, err := taskQueryFilter(workspace.Id, userId.(bson.ObjectID), query)
+	if errors.Is(err, errInvalidTaskQuery) {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	exportMarkdown(c, workspace, filter, workspace.Name, "")
+}
+
+func findExportTemplate(c *gin.Context, workspace Workspace) (ExportTemplate, bool) {
+	var exportTemplate ExportTemplate
+	templateId, err := bson.ObjectIDFromHex(c.Param("templateId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid templateId"})
+		return exportTemplate, false
+	}
+	err = exportTemplatesDb.FindOne(context.TODO(), bson.D{{"_id", templateId}, {"workspace", workspace.Id}}).Decode(&exportTemplate)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Template does not exist"})
+		return exportTemplate, false
+	} else if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return exportTemplate, false
+	}
+	return exportTemplate, true
+}
+
+// readExportTemplate reads a template from a JSON body, or from the "file" and "name" fields of an upload.
+func readExportTemplate(c *gin.Context) (CreateExportTemplate, bool) {
+	var input CreateExportTemplate
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		input.Name = c.PostForm("name")
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Field 'file' is required"})
+			return input, false
+		} else if fileHeader.Size > maxExportTemplate {
+			c.AbortWithStatusJSON(413, gin.H{"error": "The template is too large"})
+			return input, false
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+			return input, false
+		}
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+			return input, false
+		}
+		input.Template = string(data)
+		if input.Name == "" {
+			input.Name = strings.TrimSuffix(fileHeader.Filename, ".tmpl")
+		}
+	} else if err := json.NewDecoder(c.Request.Body).Decode(&input); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return input, false
+	}
+	input.Name = strings.TrimSpace(input.Name)
+	if len(input.Template) > maxExportTemplate {
+		c.AbortWithStatusJSON(413, gin.H{"error": "The template is too large"})
+		return input, false
+	}
+	return input, true
+}
+
+// @Summary 		Get all export templates
+// @Description 	Returns the Markdown export templates of a workspace.
+// @Router 			/workspaces/{workspaceId}/export_templates [get]
+// @Tags 			Import and export
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Success 		200 {object} AllExportTemplatesResponse "A list of export templates"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func getAllExportTemplates(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	cursor, err := exportTemplatesDb.Find(context.TODO(), bson.D{{"workspace", workspace.Id}})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	templates := make([]ExportTemplate, 0)
+	if err := cursor.All(context.TODO(), &templates); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(200, gin.H{"templates": templates})
+}
+
+// @Summary 		Upload an export template
+// @Description 	Adds a Go text/template for Markdown exports, as JSON or as an uploaded file. Templates get the Title, Workspace, Board, GroupBy, ExportedAt, Tasks and Groups of an export, every group has a Name and Tasks. Tasks have Key, Name, Description, Url, Board, Status, Completed, Priority, Labels, Assignees, Deadline, CreatedAt, CompletedAt and a Checklist with Done, Total and Items. The functions escape, join, date, datetime and indent are available. Templates can not define sub-templates or range over numbers, range is nested at most 3 levels and inner loops only go over fields of the current item. Only the owner can manage templates.
+// @Router 			/workspaces/{workspaceId}/export_templates [post]
+// @Tags 			Import and export
+// @Security 		BearerAuth
+// @Accept 			json,multipart/form-data
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			data body CreateExportTemplate true "Name and template"
+// @Success 		200 {object} ExportTemplate "The created template"
+// @Failure 		400 {object} ErrorSwagger "Bad request - missing name or an invalid template"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not the owner of this workspace"
+// @Failure 		413 {object} ErrorSwagger "The template is too large"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func createExportTemplate(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceOwner(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	input, ok := readExportTemplate(c)
+	if !ok {
+		return
+	} else if input.Name == "" {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'name' is not specified"})
+		return
+	} else if err := validateExportTemplate(input.Template); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Template is invalid: " + err.Error()})
+		return
+	}
+	now := time.Now().UTC().Unix()
+	exportTemplate := ExportTemplate{
+		Workspace: workspace.Id,
+		Name:      input.Name,
+		Template:  input.Template,
+		CreatedBy: userId.(bson.ObjectID),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	result, err := exportTemplatesDb.InsertOne(context.TODO(), exportTemplate)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to create template"})
+		return
+	}
+	exportTemplate.Id = result.InsertedID.(bson.ObjectID)
+	c.JSON(200, exportTemplate)
+}
+
+// @Summary 		Edit an export template
+// @Description 	Renames a template or replaces its source, as JSON or as an uploaded file.
+// @Router 			/workspaces/{workspaceId}/export_templates/{templateId} [patch]
+// @Tags 			Import and export
+// @Security 		BearerAuth
+// @Accept 			json,multipart/form-data
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			templateId path string true "Template ID"
+// @Param 			data body CreateExportTemplate true "Fields to change"
+// @Success 		200 {object} ExportTemplate "The updated template"
+// @Failure 		400 {object} ErrorSwagger "Bad request - an invalid template"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not the owner of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - template not found"
+// @Failure 		413 {object} ErrorSwagger "The template is too large"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func editExportTemplate(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceOwner(c)
+	if !ok {
+		return
+	}
+	exportTemplate, ok := findExportTemplate(c, workspace)
+	if !ok {
+		return
+	}
+	input, ok := readExportTemplate(c)
+	if !ok {
+		return
+	}
+	if input.Name != "" {
+		exportTemplate.Name = input.Name
+	}
+	if input.Template != "" {
+		if err := validateExportTemplate(input.Template); err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Template is invalid: " + err.Error()})
+			return
+		}
+		exportTemplate.Template = input.Template
+	}
+	exportTemplate.UpdatedAt = time.Now().UTC().Unix()
+	if _, err := exportTemplatesDb.ReplaceOne(context.TODO(), bson.D{{"_id", exportTemplate.Id}}, exportTemplate); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to update template"})
+		return
+	}
+	c.JSON(200, exportTemplate)
+}
+
+// @Summary 		Delete an export template
+// @Description 	Deletes a Markdown export template.
+// @Router 			/workspaces/{workspaceId}/export_templates/{templateId} [delete]
+// @Tags 			Import and export
+// @Security 		BearerAuth
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			templateId path string true "Template ID"
+// @Success 		200 "Template deleted"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not the owner of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - template not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func deleteExportTemplate(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceOwner(c)
+	if !ok {
+		return
+	}
+	exportTemplate, ok := findExportTemplate(c, workspace)
+	if !ok {
+		return
+	}
+	if _, err := exportTemplatesDb.DeleteOne(context.TODO(), bson.D{{"_id", exportTemplate.Id}}); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to delete template"})
+		return
+	}
+	c.AbortWithStatus(200)
+}
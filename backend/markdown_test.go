@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseExportTemplateLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{"default template", defaultMarkdownTemplate, ""},
+		{"labels of grouped tasks", `{{range .Groups}}{{range .Tasks}}{{range .Labels}}{{.}}{{end}}{{end}}{{end}}`, ""},
+		{"declared loop variables", `{{range $i, $task := .Tasks}}{{$i}} {{$task.Name}}{{end}}`, ""},
+		{"too deeply nested range", `{{range .Groups}}{{range .Tasks}}{{range .Labels}}{{range .}}{{end}}{{end}}{{end}}{{end}}`, "range can be nested"},
+		{"range over a number", `{{range 1000000000}}{{end}}`, "range over a number"},
+		{"inner range over all tasks", `{{range .Tasks}}{{range $.Tasks}}{{end}}{{end}}`, "range inside range"},
+		{"inner range over a variable", `{{$all := .Tasks}}{{range .Groups}}{{range $all}}{{end}}{{end}}`, "range inside range"},
+		{"inner with over all tasks", `{{range .Tasks}}{{with $.Tasks}}{{end}}{{end}}`, "with inside range"},
+		{"sub-templates", `{{define "loop"}}{{template "loop"}}{{end}}`, "sub-templates"},
+		{"too many nodes", strings.Repeat("{{.Title}}", maxTemplateNodes), "at most"},
+		{"too deeply nested", strings.Repeat("{{if .Title}}", maxTemplateDepth+1) + strings.Repeat("{{end}}", maxTemplateDepth+1), "nested at most"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseExportTemplate(test.source)
+			if test.wantErr == "" && err != nil {
+				t.Fatalf("parseExportTemplate() = %v, want no error", err)
+			} else if test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+				t.Fatalf("parseExportTemplate() = %v, want an error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderExportTemplateOutputLimit(t *testing.T) {
+	tmpl, err := parseExportTemplate(`{{range .Tasks}}{{.Description}}{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks := make([]markdownTask, 0, 20)
+	for range 20 {
+		tasks = append(tasks, markdownTask{Description: strings.Repeat("x", maxMarkdownOutput/10)})
+	}
+	if _, err := renderExportTemplate(tmpl, markdownExport{Tasks: tasks}); err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Fatalf("renderExportTemplate() = %v, want the output limit to stop it", err)
+	}
+}
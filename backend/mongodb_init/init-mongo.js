@@ -3,4 +3,18 @@ db = db.getSiblingDB('rela');
 db.createCollection('users');
 db.createCollection('tasks');
 db.createCollection('boards');
-db.createCollection('workspaces');
\ No newline at end of file
+db.createCollection('workspaces');
+db.createCollection('labels');
+db.createCollection('custom_fields');
+db.createCollection('counters');
+db.createCollection('views');
+db.createCollection('notifications');
+db.createCollection('reminders');
+db.createCollection('events', { capped: true, size: 67108864 });
+db.createCollection('webhooks');
+db.createCollection('webhook_deliveries');
+db.createCollection('jobs');
+db.createCollection('feeds');
+db.createCollection('app_passwords');
+db.createCollection('export_templates');
+db.createCollection('workspace_templates');
\ No newline at end of file
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	notificationAssigned = "assigned"
+	notificationMention  = "mention"
+	notificationKicked   = "kicked"
+	notificationPromoted = "promoted"
+	notificationDeadline = "deadline"
+	notificationOverdue  = "overdue"
+	notificationCreated  = "created"
+	notificationUpdated  = "updated"
+	notificationDeleted  = "deleted"
+)
+
+// Only the latest events of a grouped notification are kept
+const maxNotificationEvents = 20
+
+// notify stores an in-app notification for a user. Notifications about a task are merged into
+// the unread notification of that task, if there is one. The notification shows the type and message
+// of its latest event, every event keeps its own type for digests.
+func notify(userId bson.ObjectID, workspaceId bson.ObjectID, taskId bson.ObjectID, actor bson.ObjectID, kind string, message string) error {
+	now := time.Now().UTC().Unix()
+	event := NotificationEvent{Type: kind, Message: message, Actor: actor, CreatedAt: now}
+	if taskId.IsZero() {
+		_, err := notificationsDb.InsertOne(context.TODO(), Notification{
+			User:      userId,
+			Workspace: workspaceId,
+			Type:      kind,
+			Message:   message,
+			Count:     1,
+			Events:    []NotificationEvent{event},
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		return err
+	}
+	update := bson.D{
+		{"$setOnInsert", bson.D{{"workspace", workspaceId}, {"created_at", now}}},
+		{"$set", bson.D{{"type", kind}, {"message", message}, {"updated_at", now}}},
+		{"$inc", bson.D{{"count", 1}}},
+		{"$push", bson.D{{"events", bson.D{{"$each", bson.A{event}}, {"$slice", -maxNotificationEvents}}}}},
+	}
+	filter := bson.D{{"user", userId}, {"task", taskId}, {"read", false}}
+	_, err := notificationsDb.UpdateOne(context.TODO(), filter, update, options.UpdateOne().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// Another request created the unread notification first, merge into it
+		_, err = notificationsDb.UpdateOne(context.TODO(), filter, update)
+	}
+	return err
+}
+
+// notifyInBackground is used by handlers, where a failed notification must not fail the request.
+func notifyInBackground(userId bson.ObjectID, workspaceId bson.ObjectID, taskId bson.ObjectID, actor bson.ObjectID, kind string, message string) {
+	go func() {
+		if err := notify(userId, workspaceId, taskId, actor, kind, message); err != nil {
+			println("WARNING Failed to store notification: ", err.Error())
+		}
+	}()
+}
+
+func taskTitle(task Task) string {
+	if task.Key != "" {
+		return task.Key + " " + task.Name
+	}
+	return task.Name
+}
+
+func userName(userId bson.ObjectID) string {
+	var user User
+	if err := usersDb.FindOne(context.TODO(), bson.D{{"_id", userId}}, options.FindOne().SetProjection(bson.D{{"name", 1}})).Decode(&user); err != nil {
+		return "Someone"
+	}
+	return user.Name
+}
+
+// mentionedUsers finds workspace members mentioned in a text as @name or @email.
+func mentionedUsers(workspace Workspace, text string) ([]bson.ObjectID, error) {
+	if !strings.Contains(text, "@") {
+		return nil, nil
+	}
+	cursor, err := usersDb.Find(context.TODO(),
+		bson.D{{"_id", bson.D{{"$in", append([]bson.ObjectID{workspace.OwnedBy}, workspace.Members...)}}}},
+		options.Find().SetProjection(bson.D{{"_id", 1}, {"name", 1}, {"email", 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]User, 0)
+	if err := cursor.All(context.TODO(), &users); err != nil {
+		return nil, err
+	}
+	lower := strings.ToLower(text)
+	mentioned := make([]bson.ObjectID, 0)
+	for _, user := range users {
+		for _, handle := range []string{user.Email, user.Name} {
+			if handle != "" && containsMention(lower, "@"+strings.ToLower(handle)) {
+				mentioned = append(mentioned, user.Id)
+				break
+			}
+		}
+	}
+	return mentioned, nil
+}
+
+// isHandleRune reports whether a rune can be part of a name or email address.
+func isHandleRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' || r == '-' || r == '@'
+}
+
+// containsMention checks that a mention is not just the prefix of a longer word or part of an
+// email address. Punctuation ending a sentence, like in "thanks @bob.", still ends the mention.
+func containsMention(text string, mention string) bool {
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], mention)
+		if i == -1 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(mention)
+		offset = start + 1
+		if previous, _ := utf8.DecodeLastRuneInString(text[:start]); isHandleRune(previous) {
+			continue
+		}
+		next, size := utf8.DecodeRuneInString(text[end:])
+		if next == '.' || next == '-' {
+			// A trailing dot or dash only continues the handle when more of it follows
+			next, _ = utf8.DecodeRuneInString(text[end+size:])
+		}
+		if !isHandleRune(next) {
+			return true
+		}
+	}
+}
+
+// notifyMentions notifies users newly mentioned in a task description. Users already mentioned
+// in the previous description and the author are skipped.
+func notifyMentions(workspace Workspace, task Task, previousDescription string, actor bson.ObjectID) {
+	go func() {
+		mentioned, err := mentionedUsers(workspace, task.Description)
+		if err != nil || len(mentioned) == 0 {
+			return
+		}
+		previous, err := mentionedUsers(workspace, previousDescription)
+		if err != nil {
+			return
+		}
+		message := userName(actor) + " mentioned you in " + taskTitle(task)
+		for _, userId := range mentioned {
+			if userId == actor || slices.Contains(previous, userId) {
+				continue
+			}
+			if err := notify(userId, workspace.Id, task.Id, actor, notificationMention, message); err != nil {
+				println("WARNING Failed to store notification: ", err.Error())
+			}
+		}
+	}()
+}
+
+// @Summary 		Get notifications
+// @Description 	Returns your notifications, newest activity first, together with the number of unread ones.
+// @Router 			/notifications [get]
+// @Tags 			Notifications
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			unread query bool false "Only return unread notifications"
+// @Param 			limit query int false "Page size, 1 to 500, default 100"
+// @Param 			cursor query string false "Cursor of the next page from a previous response"
+// @Success 		200 {object} AllNotificationsResponse "A page of notifications"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid cursor or limit"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func getAllNotifications(c *gin.Context) {
+	userId, _ := c.Get("id")
+	filter := bson.D{{"user", userId}}
+	if c.Query("unread") == "true" {
+		filter = append(filter, bson.E{"read", false})
+	}
+	page, ok := parsePageRequest(c, "", "$updated_at", true)
+	if !ok {
+		return
+	}
+	notifications, nextCursor, err := paginate[Notification](notificationsDb, filter, page)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	unread, err := notificationsDb.CountDocuments(context.TODO(), bson.D{{"user", userId}, {"read", false}})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(200, gin.H{"notifications": notifications, "unread": unread, "next_cursor": nextCursor})
+}
+
+// @Summary 		Mark a notification as read or unread
+// @Router 			/notifications/{notificationId} [patch]
+// @Tags 			Notifications
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			notificationId path string true "Notification ID"
+// @Param 			data body EditNotification true "New read state"
+// @Success 		200 {object} Notification "The updated notification"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid notification id"
+// @Failure 		404 {object} ErrorSwagger "Not Found - notification not found"
+// @Failure 		409 {object} ErrorSwagger "There already is an unread notification for this task"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func editNotification(c *gin.Context) {
+	userId, _ := c.Get("id")
+	notificationId, err := bson.ObjectIDFromHex(c.Param("notificationId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid notificationId"})
+		return
+	}
+	var input EditNotification
+	if err := json.NewDecoder(c.Request.Body).Decode(&input); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return
+	}
+	var notification Notification
+	err = notificationsDb.FindOneAndUpdate(context.TODO(),
+		bson.D{{"_id", notificationId}, {"user", userId}},
+		bson.D{{"$set", bson.D{{"read", input.Read}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&notification)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Notification does not exist"})
+		return
+	} else if mongo.IsDuplicateKeyError(err) {
+		c.AbortWithStatusJSON(409, gin.H{"error": "There already is an unread notification for this task"})
+		return
+	} else if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(200, notification)
+}
+
+// @Summary 		Mark all notifications as read
+// @Router 			/notifications/read_all [post]
+// @Tags 			Notifications
+// @Security 		BearerAuth
+// @Success 		200 "Notifications marked as read"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func readAllNotifications(c *gin.Context) {
+	userId, _ := c.Get("id")
+	if _, err := notificationsDb.UpdateMany(context.TODO(), bson.D{{"user", userId}, {"read", false}}, bson.D{{"$set", bson.D{{"read", true}}}}); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.AbortWithStatus(200)
+}
+
+// @Summary 		Delete a notification
+// @Router 			/notifications/{notificationId} [delete]
+// @Tags 			Notifications
+// @Security 		BearerAuth
+// @Param 			notificationId path string true "Notification ID"
+// @Success 		200 "Notification deleted successfully"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid notification id"
+// @Failure 		404 {object} ErrorSwagger "Not Found - notification not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func deleteNotification(c *gin.Context) {
+	userId, _ := c.Get("id")
+	notificationId, err := bson.ObjectIDFromHex(c.Param("notificationId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid notificationId"})
+		return
+	}
+	result, err := notificationsDb.DeleteOne(context.TODO(), bson.D{{"_id", notificationId}, {"user", userId}})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to delete notification"})
+		return
+	} else if result.DeletedCount == 0 {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Notification does not exist"})
+		return
+	}
+	c.AbortWithStatus(200)
+}
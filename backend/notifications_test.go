@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestContainsMention(t *testing.T) {
+	tests := []struct {
+		text    string
+		mention string
+		want    bool
+	}{
+		{"@bob", "@bob", true},
+		{"ping @bob please", "@bob", true},
+		{"thanks @bob.", "@bob", true},
+		{"thanks @bob, see above", "@bob", true},
+		{"(@bob)", "@bob", true},
+		{"@bob: done?", "@bob", true},
+		{"are you there @bob?\nnew line", "@bob", true},
+		{"@bob- oh well", "@bob", true},
+		{"@bobby", "@bob", false},
+		{"@bob2", "@bob", false},
+		{"@bob_smith", "@bob", false},
+		{"@bob.smith", "@bob", false},
+		{"@bob-smith", "@bob", false},
+		{"@bob@example.com", "@bob", false},
+		{"alice@bob", "@bob", false},
+		{"mail alice@bob.com", "@bob", false},
+		{"@bobby and @bob", "@bob", true},
+		{"@bobé", "@bob", false},
+		{"ping @bob@example.com now", "@bob@example.com", true},
+		{"ping @bob@example.com.", "@bob@example.com", true},
+		{"ping @bob@example.community", "@bob@example.com", false},
+		{"ping @bob smith", "@bob smith", true},
+		{"", "@bob", false},
+	}
+	for _, test := range tests {
+		if got := containsMention(test.text, test.mention); got != test.want {
+			t.Errorf("containsMention(%q, %q) = %v, want %v", test.text, test.mention, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 500
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// pageCursor points after the last item of a page. It is handed to clients as an opaque string.
+type pageCursor struct {
+	Sort  string        `bson:"s"`
+	Value bson.RawValue `bson:"v"`
+	Id    bson.ObjectID `bson:"i"`
+}
+
+type pageRequest struct {
+	Limit      int64
+	Sort       string // sort key as given by the client, cursors are only valid for the same key
+	SortExpr   any    // aggregation expression producing the sort value of a document
+	Descending bool
+	After      *pageCursor
+}
+
+func encodeCursor(cursor pageCursor) (string, error) {
+	raw, err := bson.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeCursor(raw string, sort string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	var cursor pageCursor
+	if err := bson.Unmarshal(data, &cursor); err != nil || cursor.Sort != sort || cursor.Id.IsZero() {
+		return nil, errInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// parsePageRequest reads the limit and cursor query parameters. On failure the request is aborted.
+func parsePageRequest(c *gin.Context, sort string, sortExpr any, descending bool) (pageRequest, bool) {
+	page := pageRequest{Limit: defaultPageLimit, Sort: sort, SortExpr: sortExpr, Descending: descending}
+	if l := c.Query("limit"); l != "" {
+		limit, err := strconv.ParseInt(l, 10, 64)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Field 'limit' must be between 1 and " + strconv.Itoa(maxPageLimit)})
+			return page, false
+		}
+		page.Limit = limit
+	}
+	if raw := c.Query("cursor"); raw != "" {
+		after, err := decodeCursor(raw, sort)
+		if err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Invalid cursor"})
+			return page, false
+		}
+		page.After = after
+	}
+	return page, true
+}
+
+// parseListRequest is parsePageRequest for listings that predate pagination. Without a limit or
+// cursor they keep returning every item, so existing clients see the same response.
+func parseListRequest(c *gin.Context, sort string, sortExpr any, descending bool) (pageRequest, bool) {
+	page, ok := parsePageRequest(c, sort, sortExpr, descending)
+	if ok && c.Query("limit") == "" && c.Query("cursor") == "" {
+		page.Limit = 0
+	}
+	return page, ok
+}
+
+// paginate runs a keyset-paginated aggregation over documents matching the filter, ordered by the
+// sort expression and then by _id. It returns the page and the cursor of the next page, if any.
+// A limit of 0 returns every matching document on a single page.
+func paginate[T any](collection *mongo.Collection, filter bson.D, page pageRequest) ([]T, string, error) {
+	direction, compare := 1, "$gt"
+	if page.Descending {
+		direction, compare = -1, "$lt"
+	}
+	pipeline := mongo.Pipeline{
+		{{"$match", filter}},
+		{{"$addFields", bson.D{{"_sort", bson.D{{"$ifNull", bson.A{page.SortExpr, nil}}}}}}},
+	}
+	if page.After != nil {
+		// $literal keeps string sort values starting with "$" from being read as field paths
+		after := bson.D{{"$literal", page.After.Value}}
+		pipeline = append(pipeline, bson.D{{"$match", bson.D{{"$expr", bson.D{{"$or", bson.A{
+			bson.D{{compare, bson.A{"$_sort", after}}},
+			bson.D{{"$and", bson.A{
+				bson.D{{"$eq", bson.A{"$_sort", after}}},
+				bson.D{{compare, bson.A{"$_id", page.After.Id}}},
+			}}},
+		}}}}}}})
+	}
+	pipeline = append(pipeline, bson.D{{"$sort", bson.D{{"_sort", direction}, {"_id", direction}}}})
+	if page.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{"$limit", page.Limit + 1}})
+	}
+
+	cursor, err := collection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, "", err
+	}
+	defer cursor.Close(context.TODO())
+
+	items := make([]T, 0, min(page.Limit, defaultPageLimit))
+	var last pageCursor
+	for cursor.Next(context.TODO()) {
+		if page.Limit > 0 && int64(len(items)) == page.Limit {
+			// There is at least one more document, so the page gets a next cursor
+			next, err := encodeCursor(last)
+			return items, next, err
+		}
+		var item T
+		if err := bson.Unmarshal(cursor.Current, &item); err != nil {
+			return nil, "", err
+		}
+		items = append(items, item)
+		last = pageCursor{
+			Sort:  page.Sort,
+			Value: cursor.Current.Lookup("_sort"),
+			Id:    cursor.Current.Lookup("_id").ObjectID(),
+		}
+	}
+	return items, "", cursor.Err()
+}
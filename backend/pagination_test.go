@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestParseListRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	document, err := bson.Marshal(bson.D{{"v", "Roadmap"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := bson.Raw(document).Lookup("v")
+	valid, err := encodeCursor(pageCursor{Sort: "name", Value: value, Id: bson.NewObjectID()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSort, err := encodeCursor(pageCursor{Sort: "created", Value: value, Id: bson.NewObjectID()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		query     string
+		wantOk    bool
+		wantLimit int64
+		wantAfter bool
+	}{
+		{"no limit lists everything", "", true, 0, false},
+		{"limit", "?limit=20", true, 20, false},
+		{"cursor without limit uses the default", "?cursor=" + valid, true, defaultPageLimit, true},
+		{"limit and cursor", "?limit=5&cursor=" + valid, true, 5, true},
+		{"zero limit", "?limit=0", false, 0, false},
+		{"limit too high", "?limit=501", false, 0, false},
+		{"limit not a number", "?limit=ten", false, 0, false},
+		{"cursor of another sort", "?cursor=" + otherSort, false, 0, false},
+		{"garbled cursor", "?cursor=%21%21", false, 0, false},
+	}
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = httptest.NewRequest("GET", "/"+test.query, nil)
+		page, ok := parseListRequest(c, "name", "$name", false)
+		if ok != test.wantOk {
+			t.Errorf("%s: ok = %v, want %v", test.name, ok, test.wantOk)
+			continue
+		}
+		if !ok {
+			if recorder.Code != 400 {
+				t.Errorf("%s: status = %d, want 400", test.name, recorder.Code)
+			}
+			continue
+		}
+		if page.Limit != test.wantLimit || (page.After != nil) != test.wantAfter {
+			t.Errorf("%s: limit = %d, after = %v, want %d, %v", test.name, page.Limit, page.After, test.wantLimit, test.wantAfter)
+		}
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	useTestDatabase(t)
+	collection := usersDb.Database().Collection("pages")
+	type item struct {
+		Id   bson.ObjectID `bson:"_id"`
+		Rank *int          `bson:"rank,omitempty"`
+	}
+	rank := func(n int) *int { return &n }
+	// Missing sort values come first, equal ones are ordered by id
+	items := []item{
+		{bson.NewObjectID(), nil},
+		{bson.NewObjectID(), rank(1)},
+		{bson.NewObjectID(), rank(2)},
+		{bson.NewObjectID(), rank(2)},
+		{bson.NewObjectID(), rank(3)},
+	}
+	documents := make([]any, 0, len(items))
+	for i := len(items) - 1; i >= 0; i-- {
+		documents = append(documents, items[i])
+	}
+	if _, err := collection.InsertMany(context.TODO(), documents); err != nil {
+		t.Fatal(err)
+	}
+
+	walk := func(limit int64, descending bool) ([]bson.ObjectID, int) {
+		ids := make([]bson.ObjectID, 0)
+		pages := 0
+		page := pageRequest{Limit: limit, Sort: "rank", SortExpr: "$rank", Descending: descending}
+		for {
+			got, next, err := paginate[item](collection, bson.D{}, page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pages++
+			for _, item := range got {
+				ids = append(ids, item.Id)
+			}
+			if next == "" {
+				return ids, pages
+			}
+			if page.After, err = decodeCursor(next, "rank"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	want := make([]bson.ObjectID, 0, len(items))
+	for _, item := range items {
+		want = append(want, item.Id)
+	}
+	reversed := make([]bson.ObjectID, 0, len(want))
+	for i := len(want) - 1; i >= 0; i-- {
+		reversed = append(reversed, want[i])
+	}
+
+	tests := []struct {
+		name       string
+		limit      int64
+		descending bool
+		want       []bson.ObjectID
+		wantPages  int
+	}{
+		{"pages of two", 2, false, want, 3},
+		{"pages of one", 1, false, want, 5},
+		{"one exact page", 5, false, want, 1},
+		{"no limit", 0, false, want, 1},
+		{"descending", 2, true, reversed, 3},
+	}
+	for _, test := range tests {
+		got, pages := walk(test.limit, test.descending)
+		if pages != test.wantPages {
+			t.Errorf("%s: %d pages, want %d", test.name, pages, test.wantPages)
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("%s: got %d items, want %d", test.name, len(got), len(test.want))
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%s: item %d = %s, want %s", test.name, i, got[i].Hex(), test.want[i].Hex())
+			}
+		}
+	}
+}
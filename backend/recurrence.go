@@ -0,0 +1,417 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	recurrenceOnCompletion = "completion"
+	recurrenceOnSchedule   = "schedule"
+)
+
+const (
+	recurrenceTick  = time.Minute
+	recurrenceLease = 2 * time.Minute
+	// Occurrences are searched day by day, rules that match less often than this never fire
+	recurrenceHorizonDays = 10 * 366
+)
+
+var errInvalidRecurrence = errors.New("invalid recurrence rule")
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+type ruleWeekday struct {
+	Ordinal int // 0 means every such weekday, otherwise the n-th (or n-th from the end) in the month
+	Day     time.Weekday
+}
+
+// recurrenceRule is the supported subset of an RFC 5545 RRULE.
+type recurrenceRule struct {
+	Freq       string
+	Interval   int
+	ByDay      []ruleWeekday
+	ByMonthDay []int
+	Count      int
+	Until      time.Time
+}
+
+func invalidRecurrence(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", errInvalidRecurrence, fmt.Sprintf(format, args...))
+}
+
+// parseRecurrenceRule parses rules like FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR or FREQ=MONTHLY;BYMONTHDAY=-1.
+func parseRecurrenceRule(raw string) (recurrenceRule, error) {
+	rule := recurrenceRule{Interval: 1}
+	raw = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(raw)), "RRULE:")
+	for _, part := range strings.Split(raw, ";") {
+		name, value, found := strings.Cut(part, "=")
+		if !found || value == "" {
+			return rule, invalidRecurrence("malformed part '%s'", part)
+		}
+		switch name {
+		case "FREQ":
+			if value != "DAILY" && value != "WEEKLY" && value != "MONTHLY" && value != "YEARLY" {
+				return rule, invalidRecurrence("FREQ must be DAILY, WEEKLY, MONTHLY or YEARLY")
+			}
+			rule.Freq = value
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 || interval > 1000 {
+				return rule, invalidRecurrence("INTERVAL must be between 1 and 1000")
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				if len(day) < 2 {
+					return rule, invalidRecurrence("unknown weekday '%s'", day)
+				}
+				weekday, ok := rruleWeekdays[day[len(day)-2:]]
+				if !ok {
+					return rule, invalidRecurrence("unknown weekday '%s'", day)
+				}
+				ordinal := 0
+				if prefix := day[:len(day)-2]; prefix != "" {
+					n, err := strconv.Atoi(prefix)
+					if err != nil || n == 0 || n < -5 || n > 5 {
+						return rule, invalidRecurrence("unknown weekday '%s'", day)
+					}
+					ordinal = n
+				}
+				rule.ByDay = append(rule.ByDay, ruleWeekday{Ordinal: ordinal, Day: weekday})
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return rule, invalidRecurrence("BYMONTHDAY must be between 1 and 31 or -31 and -1")
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return rule, invalidRecurrence("COUNT must be a positive number")
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := time.Parse("20060102T150405Z", value)
+			if err != nil {
+				if until, err = time.Parse("20060102", value); err != nil {
+					return rule, invalidRecurrence("UNTIL must look like 20261231 or 20261231T235959Z")
+				}
+				until = until.Add(24*time.Hour - time.Second)
+			}
+			rule.Until = until
+		default:
+			return rule, invalidRecurrence("%s is not supported", name)
+		}
+	}
+	if rule.Freq == "" {
+		return rule, invalidRecurrence("FREQ is required")
+	} else if rule.Count != 0 && !rule.Until.IsZero() {
+		return rule, invalidRecurrence("COUNT and UNTIL can not be combined")
+	} else if len(rule.ByMonthDay) > 0 && rule.Freq != "MONTHLY" {
+		return rule, invalidRecurrence("BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+	for _, day := range rule.ByDay {
+		if day.Ordinal != 0 && rule.Freq != "MONTHLY" {
+			return rule, invalidRecurrence("numbered weekdays are only supported with FREQ=MONTHLY")
+		}
+	}
+	return rule, nil
+}
+
+func civilDays(t time.Time) int {
+	return int(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
+
+// matchesDay reports whether a local date is part of the recurrence anchored at start.
+func (rule recurrenceRule) matchesDay(start time.Time, day time.Time) bool {
+	hasWeekday := func() bool {
+		for _, d := range rule.ByDay {
+			if d.Day == day.Weekday() && d.Ordinal == 0 {
+				return true
+			}
+		}
+		return false
+	}
+	switch rule.Freq {
+	case "DAILY":
+		if (civilDays(day)-civilDays(start))%rule.Interval != 0 {
+			return false
+		}
+		return len(rule.ByDay) == 0 || hasWeekday()
+	case "WEEKLY":
+		// Weeks start on Monday as in the RFC 5545 default of WKST
+		weekStart := func(t time.Time) int { return civilDays(t) - (int(t.Weekday())+6)%7 }
+		if (weekStart(day)-weekStart(start))/7%rule.Interval != 0 {
+			return false
+		}
+		if len(rule.ByDay) == 0 {
+			return day.Weekday() == start.Weekday()
+		}
+		return hasWeekday()
+	case "MONTHLY":
+		months := (day.Year()-start.Year())*12 + int(day.Month()) - int(start.Month())
+		if months%rule.Interval != 0 {
+			return false
+		}
+		daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		if len(rule.ByMonthDay) > 0 {
+			for _, n := range rule.ByMonthDay {
+				if n == day.Day() || daysInMonth+n+1 == day.Day() {
+					return true
+				}
+			}
+			return false
+		}
+		if len(rule.ByDay) > 0 {
+			for _, d := range rule.ByDay {
+				if d.Day != day.Weekday() {
+					continue
+				}
+				fromStart := (day.Day()-1)/7 + 1
+				fromEnd := -((daysInMonth-day.Day())/7 + 1)
+				if d.Ordinal == 0 || d.Ordinal == fromStart || d.Ordinal == fromEnd {
+					return true
+				}
+			}
+			return false
+		}
+		// Months without the start day are skipped, as RFC 5545 requires
+		return day.Day() == start.Day()
+	case "YEARLY":
+		return (day.Year()-start.Year())%rule.Interval == 0 && day.Month() == start.Month() && day.Day() == start.Day()
+	}
+	return false
+}
+
+// next returns the first occurrence after the given time. Occurrences keep the local time of day of start.
+func (rule recurrenceRule) next(start time.Time, after time.Time) (time.Time, bool) {
+	location := start.Location()
+	after = after.In(location)
+	day := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, location)
+	for i := 0; i < recurrenceHorizonDays; i++ {
+		candidateDay := day.AddDate(0, 0, i)
+		if candidateDay.Before(time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, location)) {
+			continue
+		}
+		if !rule.matchesDay(start, candidateDay) {
+			continue
+		}
+		candidate := time.Date(candidateDay.Year(), candidateDay.Month(), candidateDay.Day(), start.Hour(), start.Minute(), start.Second(), 0, location)
+		if !candidate.After(after) {
+			continue
+		}
+		if !rule.Until.IsZero() && candidate.After(rule.Until) {
+			return time.Time{}, false
+		}
+		return candidate, true
+	}
+	return time.Time{}, false
+}
+
+// newTaskRecurrence validates recurrence input for a task. The deadline of the task becomes the start of the series.
+func newTaskRecurrence(task Task, input TaskRecurrenceInput) (*TaskRecurrence, error) {
+	if task.Deadline == 0 {
+		return nil, invalidRecurrence("recurring tasks need a deadline")
+	}
+	if _, err := parseRecurrenceRule(input.Rule); err != nil {
+		return nil, err
+	}
+	if input.Timezone == "" {
+		input.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(input.Timezone); err != nil {
+		return nil, invalidRecurrence("unknown timezone '%s'", input.Timezone)
+	}
+	if input.Mode == "" {
+		input.Mode = recurrenceOnCompletion
+	} else if input.Mode != recurrenceOnCompletion && input.Mode != recurrenceOnSchedule {
+		return nil, invalidRecurrence("mode must be completion or schedule")
+	}
+	recurrence := &TaskRecurrence{
+		Rule:       strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(input.Rule)), "RRULE:"),
+		Timezone:   input.Timezone,
+		Mode:       input.Mode,
+		Series:     task.Id,
+		Start:      task.Deadline,
+		Occurrence: 1,
+	}
+	if task.Recurrence != nil {
+		// Changing the rule of an existing series keeps its identity and position
+		recurrence.Series = task.Recurrence.Series
+		recurrence.Occurrence = task.Recurrence.Occurrence
+	}
+	return recurrence, nil
+}
+
+// nextOccurrenceDeadline computes the deadline of the occurrence following the task. Occurrences
+// that would already be overdue are skipped, so a series does not flood a board after downtime.
+func nextOccurrenceDeadline(task Task, now time.Time) (int64, bool) {
+	recurrence := task.Recurrence
+	rule, err := parseRecurrenceRule(recurrence.Rule)
+	if err != nil {
+		return 0, false
+	}
+	location, err := time.LoadLocation(recurrence.Timezone)
+	if err != nil {
+		return 0, false
+	}
+	if rule.Count != 0 && recurrence.Occurrence >= rule.Count {
+		return 0, false
+	}
+	start := time.Unix(recurrence.Start, 0).In(location)
+	after := time.Unix(task.Deadline, 0)
+	if after.Before(now) {
+		after = now
+	}
+	next, ok := rule.next(start, after)
+	if !ok {
+		return 0, false
+	}
+	return next.UTC().Unix(), true
+}
+
+// spawnNextOccurrence claims a finished occurrence and creates the one after it. The claim is a lease,
+// so replicas never work on the same task at once and a crashed replica's claim expires. The unique
+// index on series and occurrence keeps a retried claim from creating the same occurrence twice.
+func spawnNextOccurrence(taskId bson.ObjectID) error {
+	now := time.Now().UTC()
+	var task Task
+	err := tasksDb.FindOneAndUpdate(context.TODO(),
+		bson.D{
+			{"_id", taskId},
+			{"recurrence.next_task", bson.D{{"$exists", false}}},
+			{"recurrence.ended", bson.D{{"$ne", true}}},
+			{"recurrence.claimed_until", bson.D{{"$not", bson.D{{"$gt", now.Unix()}}}}},
+		},
+		bson.D{{"$set", bson.D{{"recurrence.claimed_until", now.Add(recurrenceLease).Unix()}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&task)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// Already handled or claimed by another replica
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	deadline, ok := nextOccurrenceDeadline(task, now)
+	if !ok {
+		_, err := tasksDb.UpdateOne(context.TODO(), bson.D{{"_id", task.Id}}, bson.D{
+			{"$set", bson.D{{"recurrence.ended", true}}},
+			{"$unset", bson.D{{"recurrence.claimed_until", ""}}},
+		})
+		return err
+	}
+
+	var workspace Workspace
+	if err := workspacesDb.FindOne(context.TODO(), bson.D{{"_id", task.CreatedBy}}).Decode(&workspace); err != nil {
+		return err
+	}
+	recurrence := *task.Recurrence
+	recurrence.Occurrence++
+	recurrence.ClaimedUntil = 0
+	next := Task{
+		Name:         task.Name,
+		Description:  task.Description,
+		CreatedAt:    now.Unix(),
+		CreatedBy:    task.CreatedBy,
+		Board:        task.Board,
+		Deadline:     deadline,
+		Labels:       task.Labels,
+		Priority:     task.Priority,
+		PriorityRank: task.PriorityRank,
+		Estimate:     task.Estimate,
+		CustomFields: task.CustomFields,
+		Assignees:    task.Assignees,
+		Recurrence:   &recurrence,
+	}
+	var existing Task
+	err = tasksDb.FindOne(context.TODO(), bson.D{{"recurrence.series", recurrence.Series}, {"recurrence.occurrence", recurrence.Occurrence}}).Decode(&existing)
+	if err == nil {
+		next.Id = existing.Id
+	} else if !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	} else {
+		keyPrefix, err := ensureWorkspaceKey(workspace)
+		if err != nil {
+			return err
+		}
+		if next.Number, err = nextTaskNumber(task.CreatedBy); err != nil {
+			return err
+		}
+		next.Key = formatTaskKey(keyPrefix, next.Number)
+		result, err := tasksDb.InsertOne(context.TODO(), next)
+		if err != nil {
+			return err
+		}
+		next.Id = result.InsertedID.(bson.ObjectID)
+		publishEvent(Event{Type: eventTaskCreated, Workspace: next.CreatedBy, Task: &next})
+	}
+	_, err = tasksDb.UpdateOne(context.TODO(), bson.D{{"_id", task.Id}}, bson.D{
+		{"$set", bson.D{{"recurrence.next_task", next.Id}}},
+		{"$unset", bson.D{{"recurrence.claimed_until", ""}}},
+	})
+	return err
+}
+
+// dueRecurringTasks finds occurrences whose successor should exist by now: completed ones,
+// and for schedule mode also those whose deadline has passed.
+func dueRecurringTasks(now time.Time) ([]bson.ObjectID, error) {
+	cursor, err := tasksDb.Find(context.TODO(),
+		bson.D{
+			{"recurrence.series", bson.D{{"$exists", true}}},
+			{"recurrence.next_task", bson.D{{"$exists", false}}},
+			{"recurrence.ended", bson.D{{"$ne", true}}},
+			{"$or", bson.A{
+				bson.D{{"completed_at", bson.D{{"$gt", 0}}}},
+				bson.D{{"recurrence.mode", recurrenceOnSchedule}, {"deadline", bson.D{{"$gt", 0}, {"$lte", now.Unix()}}}},
+			}},
+		},
+		options.Find().SetProjection(bson.D{{"_id", 1}}).SetLimit(500),
+	)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]Task, 0)
+	if err := cursor.All(context.TODO(), &tasks); err != nil {
+		return nil, err
+	}
+	ids := make([]bson.ObjectID, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.Id)
+	}
+	return ids, nil
+}
+
+// runRecurrenceScheduler periodically creates the next occurrences of recurring tasks.
+// Every replica can run it, claims make sure each occurrence is created once.
+func runRecurrenceScheduler() {
+	ticker := time.NewTicker(recurrenceTick)
+	defer ticker.Stop()
+	for ; ; <-ticker.C {
+		ids, err := dueRecurringTasks(time.Now().UTC())
+		if err != nil {
+			println("WARNING Failed to look up recurring tasks: ", err.Error())
+			continue
+		}
+		for _, id := range ids {
+			if err := spawnNextOccurrence(id); err != nil {
+				println("WARNING Failed to create next occurrence of task ", id.Hex(), ": ", err.Error())
+			}
+		}
+	}
+}
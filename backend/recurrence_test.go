@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want recurrenceRule
+	}{
+		{"FREQ=DAILY", recurrenceRule{Freq: "DAILY", Interval: 1}},
+		{"rrule:freq=weekly;interval=2;byday=MO,FR", recurrenceRule{Freq: "WEEKLY", Interval: 2, ByDay: []ruleWeekday{{0, time.Monday}, {0, time.Friday}}}},
+		{"FREQ=MONTHLY;BYDAY=2TU,-1FR", recurrenceRule{Freq: "MONTHLY", Interval: 1, ByDay: []ruleWeekday{{2, time.Tuesday}, {-1, time.Friday}}}},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,-1;COUNT=5", recurrenceRule{Freq: "MONTHLY", Interval: 1, ByMonthDay: []int{1, -1}, Count: 5}},
+		{"FREQ=YEARLY;UNTIL=20271231", recurrenceRule{Freq: "YEARLY", Interval: 1, Until: time.Date(2027, 12, 31, 23, 59, 59, 0, time.UTC)}},
+		{"FREQ=DAILY;UNTIL=20270101T120000Z", recurrenceRule{Freq: "DAILY", Interval: 1, Until: time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC)}},
+	}
+	for _, test := range tests {
+		got, err := parseRecurrenceRule(test.raw)
+		if err != nil {
+			t.Errorf("parseRecurrenceRule(%q) failed: %v", test.raw, err)
+		} else if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parseRecurrenceRule(%q) = %+v, want %+v", test.raw, got, test.want)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;COUNT=2;UNTIL=20270101",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;WKST=MO",
+		"FREQ=DAILY;;",
+	}
+	for _, raw := range invalid {
+		if _, err := parseRecurrenceRule(raw); !errors.Is(err, errInvalidRecurrence) {
+			t.Errorf("parseRecurrenceRule(%q) = %v, want errInvalidRecurrence", raw, err)
+		}
+	}
+}
+
+func TestRecurrenceRuleNext(t *testing.T) {
+	// Monday
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	at := func(month time.Month, day int) time.Time { return time.Date(2026, month, day, 9, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		rule  string
+		start time.Time
+		after time.Time
+		want  time.Time // zero when the series has ended
+	}{
+		{"FREQ=DAILY", start, start, at(1, 6)},
+		{"FREQ=DAILY", start, start.Add(-time.Hour), start},
+		{"FREQ=DAILY;INTERVAL=3", start, at(1, 6).Add(time.Hour), at(1, 8)},
+		{"FREQ=DAILY;BYDAY=MO,WE", start, start, at(1, 7)},
+		{"FREQ=WEEKLY", start, start, at(1, 12)},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", start, start, at(1, 9)},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", start, at(1, 9), at(1, 19)},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", start, start, at(1, 31)},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", start, at(1, 31), at(2, 28)},
+		{"FREQ=MONTHLY", at(1, 31), at(1, 31), at(3, 31)},
+		{"FREQ=MONTHLY;BYDAY=2TU", start, start, at(1, 13)},
+		{"FREQ=MONTHLY;BYDAY=-1FR", start, start, at(1, 30)},
+		{"FREQ=YEARLY", start, start, time.Date(2027, 1, 5, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=DAILY;UNTIL=20260106", start, start, at(1, 6)},
+		{"FREQ=DAILY;UNTIL=20260106", start, at(1, 6), time.Time{}},
+		{"FREQ=YEARLY", time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC), time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		rule, err := parseRecurrenceRule(test.rule)
+		if err != nil {
+			t.Fatalf("parseRecurrenceRule(%q) failed: %v", test.rule, err)
+		}
+		got, ok := rule.next(test.start, test.after)
+		if ok != !test.want.IsZero() || !got.Equal(test.want) {
+			t.Errorf("%s after %s = %s %v, want %s", test.rule, test.after, got, ok, test.want)
+		}
+	}
+}
+
+func TestNextOccurrenceDeadline(t *testing.T) {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	task := func(rule string, timezone string, deadline time.Time, occurrence int) Task {
+		return Task{Deadline: deadline.Unix(), Recurrence: &TaskRecurrence{Rule: rule, Timezone: timezone, Start: start.Unix(), Occurrence: occurrence}}
+	}
+	tests := []struct {
+		name string
+		task Task
+		now  time.Time
+		want time.Time // zero when no occurrence follows
+	}{
+		{"next day", task("FREQ=DAILY", "UTC", start, 1), start.Add(-time.Hour), start.AddDate(0, 0, 1)},
+		{"overdue occurrences are skipped", task("FREQ=DAILY", "UTC", start, 1), start.AddDate(0, 0, 5).Add(3 * time.Hour), start.AddDate(0, 0, 6)},
+		{"count not reached", task("FREQ=DAILY;COUNT=2", "UTC", start, 1), start, start.AddDate(0, 0, 1)},
+		{"count reached", task("FREQ=DAILY;COUNT=2", "UTC", start.AddDate(0, 0, 1), 2), start, time.Time{}},
+		{"until passed", task("FREQ=DAILY;UNTIL=20260105", "UTC", start, 1), start, time.Time{}},
+		{"invalid rule", task("FREQ=HOURLY", "UTC", start, 1), start, time.Time{}},
+		{"unknown timezone", task("FREQ=DAILY", "Mars/Olympus", start, 1), start, time.Time{}},
+	}
+	for _, test := range tests {
+		got, ok := nextOccurrenceDeadline(test.task, test.now)
+		if ok != !test.want.IsZero() || (ok && got != test.want.Unix()) {
+			t.Errorf("%s: nextOccurrenceDeadline = %s %v, want %s", test.name, time.Unix(got, 0).UTC(), ok, test.want)
+		}
+	}
+
+	// Occurrences keep their local time of day across daylight saving changes
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no time zone database")
+	}
+	beforeChange := time.Date(2026, 3, 28, 9, 0, 0, 0, berlin)
+	occurrence := Task{Deadline: beforeChange.Unix(), Recurrence: &TaskRecurrence{Rule: "FREQ=DAILY", Timezone: "Europe/Berlin", Start: beforeChange.Unix(), Occurrence: 1}}
+	got, ok := nextOccurrenceDeadline(occurrence, beforeChange)
+	if want := time.Date(2026, 3, 29, 9, 0, 0, 0, berlin); !ok || got != want.Unix() {
+		t.Errorf("across daylight saving = %s %v, want %s", time.Unix(got, 0).In(berlin), ok, want)
+	}
+}
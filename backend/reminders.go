@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	reminderTick = time.Minute
+	// Overdue reminders are not sent for tasks that were already overdue long before the scheduler saw them
+	overdueLookback = 7 * 24 * time.Hour
+	// A reminder email that failed is retried after the lease, until it is this old
+	reminderEmailLease = 2 * time.Minute
+	reminderEmailRetry = 24 * time.Hour
+)
+
+var reminderWindows = parseReminderWindows(os.Getenv("REMINDER_WINDOWS"))
+
+// parseReminderWindows reads a comma separated list of durations like "24h,1h", smallest first.
+func parseReminderWindows(raw string) []time.Duration {
+	if raw == "" {
+		raw = "24h,1h"
+	}
+	windows := make([]time.Duration, 0)
+	for _, part := range strings.Split(raw, ",") {
+		window, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil || window <= 0 {
+			println("WARNING Ignoring invalid reminder window: ", part)
+			continue
+		}
+		windows = append(windows, window)
+	}
+	slices.Sort(windows)
+	return slices.Compact(windows)
+}
+
+type reminder struct {
+	Id        bson.ObjectID `bson:"_id,omitempty"`
+	Task      bson.ObjectID `bson:"task"`
+	User      bson.ObjectID `bson:"user"`
+	Kind      string        `bson:"kind"`
+	Window    int64         `bson:"window"`
+	Deadline  int64         `bson:"deadline"`
+	CreatedAt int64         `bson:"created_at"`
+	// The in-app notification is stored once, the email is retried until it went out
+	EmailPending      bool  `bson:"email_pending,omitempty"`
+	EmailClaimedUntil int64 `bson:"email_claimed_until,omitempty"`
+	EmailSentAt       int64 `bson:"email_sent_at,omitempty"`
+}
+
+// reminderRecipients returns the users that should hear about the deadline of a task, its assignees and watchers.
+func reminderRecipients(task Task, workspace Workspace) ([]bson.ObjectID, error) {
+	watchers, err := taskWatchers(task)
+	if err != nil {
+		return nil, err
+	}
+	recipients := make([]bson.ObjectID, 0, len(task.Assignees)+len(watchers))
+	for _, userId := range append(slices.Clone(task.Assignees), watchers...) {
+		// Users that left the workspace keep their assignment but get no reminders
+		if slices.Contains(recipients, userId) {
+			continue
+		}
+		if slices.Contains(workspace.Members, userId) || workspace.OwnedBy == userId {
+			recipients = append(recipients, userId)
+		}
+	}
+	return recipients, nil
+}
+
+// reminderWindowFor picks the smallest window the deadline falls in, so a task created an hour
+// before its deadline does not get the day-ahead reminder as well.
+func reminderWindowFor(deadline int64, now time.Time) (time.Duration, bool) {
+	left := time.Unix(deadline, 0).Sub(now)
+	for _, window := range reminderWindows {
+		if left <= window {
+			return window, true
+		}
+	}
+	return 0, false
+}
+
+func userLocation(user User) *time.Location {
+	if location, err := time.LoadLocation(user.Timezone); err == nil && user.Timezone != "" {
+		return location
+	}
+	return time.UTC
+}
+
+var reminderTextTemplate = texttemplate.Must(texttemplate.New("reminder").Parse(`Hi {{.Name}},
+
+{{.Message}}.
+
+Open the task: {{.TaskUrl}}
+
+To stop receiving these emails, open {{.UnsubscribeUrl}}
+`))
+
+var reminderHtmlTemplate = htmltemplate.Must(htmltemplate.New("reminder").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #222;">
+<p>Hi {{.Name}},</p>
+<p><a href="{{.TaskUrl}}">{{.Message}}</a>.</p>
+<p style="font-size: 12px; color: #666;"><a href="{{.UnsubscribeUrl}}">Unsubscribe</a> from these emails.</p>
+</body>
+</html>
+`))
+
+type reminderEmailData struct {
+	Name           string
+	Message        string
+	TaskUrl        string
+	UnsubscribeUrl string
+}
+
+func reminderMessage(task Task, user User, kind string) string {
+	deadline := time.Unix(task.Deadline, 0).In(userLocation(user)).Format("Mon, 02 Jan 2006 15:04 MST")
+	if kind == notificationOverdue {
+		return fmt.Sprintf("%s is overdue since %s", taskTitle(task), deadline)
+	}
+	return fmt.Sprintf("%s is due %s", taskTitle(task), deadline)
+}
+
+// sendReminder records the reminder, notifies the user and emails them right away, unless they
+// turned off notification emails. Only the replica that manages to insert the record notifies, and
+// since records survive restarts no reminder is sent twice. If notifying fails the record is removed
+// again, so the next check retries the reminder. A failed email leaves the record pending and only
+// the email is retried by retryReminderEmails.
+func sendReminder(task Task, userId bson.ObjectID, kind string, window time.Duration, now time.Time) error {
+	var user User
+	if err := usersDb.FindOne(context.TODO(), bson.D{{"_id", userId}}).Decode(&user); err != nil {
+		return err
+	}
+	record := reminder{
+		Task:         task.Id,
+		User:         userId,
+		Kind:         kind,
+		Window:       int64(window.Seconds()),
+		Deadline:     task.Deadline,
+		CreatedAt:    now.Unix(),
+		EmailPending: user.EmailDigest != digestOff,
+	}
+	if record.EmailPending {
+		// Other replicas leave the email alone while this one sends it
+		record.EmailClaimedUntil = now.Add(reminderEmailLease).Unix()
+	}
+	result, err := remindersDb.InsertOne(context.TODO(), record)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	record.Id = result.InsertedID.(bson.ObjectID)
+	if err := notify(userId, task.CreatedBy, task.Id, bson.ObjectID{}, kind, reminderMessage(task, user, kind)); err != nil {
+		if _, deleteErr := remindersDb.DeleteOne(context.TODO(), bson.D{{"_id", record.Id}}); deleteErr != nil {
+			println("WARNING Failed to release reminder of task ", task.Id.Hex(), ": ", deleteErr.Error())
+		}
+		return err
+	}
+	if !record.EmailPending {
+		return nil
+	}
+	return emailReminder(record, task, user)
+}
+
+// emailReminder sends the email of a reminder and marks it as sent. On failure the record stays
+// pending and the email is retried once its claim ends.
+func emailReminder(record reminder, task Task, user User) error {
+	message := reminderMessage(task, user, record.Kind)
+	data := reminderEmailData{
+		Name:           user.Name,
+		Message:        message,
+		TaskUrl:        taskWebUrl(task),
+		UnsubscribeUrl: apiUrl("/users/unsubscribe?token=" + unsubscribeToken(user.Id)),
+	}
+	var text, body bytes.Buffer
+	if err := reminderTextTemplate.Execute(&text, data); err != nil {
+		return err
+	}
+	if err := reminderHtmlTemplate.Execute(&body, data); err != nil {
+		return err
+	}
+	err := mailer.Send(Email{
+		To:      user.Email,
+		Subject: message,
+		Text:    text.String(),
+		HTML:    body.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + data.UnsubscribeUrl + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = remindersDb.UpdateOne(context.TODO(), bson.D{{"_id", record.Id}}, bson.D{
+		{"$set", bson.D{{"email_sent_at", time.Now().UTC().Unix()}}},
+		{"$unset", bson.D{{"email_pending", ""}, {"email_claimed_until", ""}}},
+	})
+	return err
+}
+
+// retryReminderEmails sends the emails of reminders whose email failed before. Emails of tasks that
+// were completed, rescheduled or deleted meanwhile are dropped, as are those older than reminderEmailRetry.
+func retryReminderEmails(now time.Time) error {
+	for {
+		var record reminder
+		err := remindersDb.FindOneAndUpdate(context.TODO(),
+			bson.D{
+				{"email_pending", true},
+				{"created_at", bson.D{{"$gte", now.Add(-reminderEmailRetry).Unix()}}},
+				{"email_claimed_until", bson.D{{"$lte", now.Unix()}}},
+			},
+			bson.D{{"$set", bson.D{{"email_claimed_until", now.Add(reminderEmailLease).Unix()}}}},
+		).Decode(&record)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		var task Task
+		taskErr := tasksDb.FindOne(context.TODO(), bson.D{{"_id", record.Task}}).Decode(&task)
+		var user User
+		userErr := usersDb.FindOne(context.TODO(), bson.D{{"_id", record.User}}).Decode(&user)
+		if errors.Is(taskErr, mongo.ErrNoDocuments) || errors.Is(userErr, mongo.ErrNoDocuments) ||
+			(taskErr == nil && (task.CompletedAt != 0 || task.Deadline != record.Deadline)) || (userErr == nil && user.EmailDigest == digestOff) {
+			if _, err := remindersDb.UpdateOne(context.TODO(), bson.D{{"_id", record.Id}}, bson.D{{"$unset", bson.D{{"email_pending", ""}, {"email_claimed_until", ""}}}}); err != nil {
+				return err
+			}
+			continue
+		} else if taskErr != nil {
+			return taskErr
+		} else if userErr != nil {
+			return userErr
+		}
+		if err := emailReminder(record, task, user); err != nil {
+			// The mail server is likely down, the claim makes the next check try again later
+			return err
+		}
+	}
+}
+
+// checkReminders sends due-soon reminders for open tasks inside a reminder window and overdue
+// reminders for open tasks whose deadline has passed.
+func checkReminders(now time.Time) error {
+	if len(reminderWindows) == 0 {
+		return nil
+	}
+	from := now.Add(-overdueLookback).Unix()
+	to := now.Add(reminderWindows[len(reminderWindows)-1]).Unix()
+	cursor, err := tasksDb.Find(context.TODO(),
+		bson.D{
+			{"deadline", bson.D{{"$gte", from}, {"$lte", to}}},
+			{"completed_at", bson.D{{"$in", bson.A{0, nil}}}},
+		},
+		options.Find().SetProjection(bson.D{{"_id", 1}, {"key", 1}, {"name", 1}, {"created_by", 1}, {"board", 1}, {"deadline", 1}, {"assignees", 1}, {"watchers", 1}}),
+	)
+	if err != nil {
+		return err
+	}
+	tasks := make([]Task, 0)
+	if err := cursor.All(context.TODO(), &tasks); err != nil {
+		return err
+	}
+	workspaces := map[bson.ObjectID]Workspace{}
+	for _, task := range tasks {
+		workspace, ok := workspaces[task.CreatedBy]
+		if !ok {
+			if err := workspacesDb.FindOne(context.TODO(), bson.D{{"_id", task.CreatedBy}}).Decode(&workspace); err != nil {
+				continue
+			}
+			workspaces[task.CreatedBy] = workspace
+		}
+		kind, window := notificationOverdue, time.Duration(0)
+		if task.Deadline > now.Unix() {
+			if window, ok = reminderWindowFor(task.Deadline, now); !ok {
+				continue
+			}
+			kind = notificationDeadline
+		}
+		recipients, err := reminderRecipients(task, workspace)
+		if err != nil {
+			println("WARNING Failed to load reminder recipients of task ", task.Id.Hex(), ": ", err.Error())
+			continue
+		}
+		for _, userId := range recipients {
+			if err := sendReminder(task, userId, kind, window, now); err != nil {
+				println("WARNING Failed to send reminder for task ", task.Id.Hex(), ": ", err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// runReminderScheduler checks deadlines once a minute. Every replica can run it.
+func runReminderScheduler() {
+	ticker := time.NewTicker(reminderTick)
+	defer ticker.Stop()
+	for ; ; <-ticker.C {
+		if err := checkReminders(time.Now().UTC()); err != nil {
+			println("WARNING Failed to check deadline reminders: ", err.Error())
+		}
+		if err := retryReminderEmails(time.Now().UTC()); err != nil {
+			println("WARNING Failed to retry reminder emails: ", err.Error())
+		}
+	}
+}
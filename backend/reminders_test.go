@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestSendReminder(t *testing.T) {
+	useTestDatabase(t)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	setup := func(t *testing.T) (User, Task) {
+		t.Helper()
+		user := User{Id: bson.NewObjectID(), Name: "<Ada>", Email: "ada@example.com", EmailDigest: digestImmediate}
+		if _, err := usersDb.InsertOne(context.TODO(), user); err != nil {
+			t.Fatal(err)
+		}
+		workspace := Workspace{Id: bson.NewObjectID(), Name: "Web", OwnedBy: user.Id}
+		if _, err := workspacesDb.InsertOne(context.TODO(), workspace); err != nil {
+			t.Fatal(err)
+		}
+		task := Task{Id: bson.NewObjectID(), Key: "WEB-1", Name: "Ship it", CreatedBy: workspace.Id, Assignees: []bson.ObjectID{user.Id}, Deadline: now.Add(time.Hour).Unix()}
+		if _, err := tasksDb.InsertOne(context.TODO(), task); err != nil {
+			t.Fatal(err)
+		}
+		return user, task
+	}
+	record := func(t *testing.T, task Task) reminder {
+		t.Helper()
+		var stored reminder
+		if err := remindersDb.FindOne(context.TODO(), bson.D{{"task", task.Id}}).Decode(&stored); err != nil {
+			t.Fatal(err)
+		}
+		return stored
+	}
+	notifications := func(t *testing.T, user User) int {
+		t.Helper()
+		var notification Notification
+		if err := notificationsDb.FindOne(context.TODO(), bson.D{{"user", user.Id}}).Decode(&notification); err != nil {
+			t.Fatal(err)
+		}
+		return notification.Count
+	}
+
+	t.Run("notifies and emails once", func(t *testing.T) {
+		fake := useFakeMailer(t, nil)
+		user, task := setup(t)
+		for range 2 {
+			if err := sendReminder(task, user.Id, notificationDeadline, time.Hour, now); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if len(fake.sent) != 1 || fake.sent[0].To != user.Email || !strings.HasPrefix(fake.sent[0].Subject, "WEB-1 Ship it is due") {
+			t.Fatalf("sent %+v, want one reminder to %s", fake.sent, user.Email)
+		}
+		if !strings.Contains(fake.sent[0].HTML, "&lt;Ada&gt;") || !strings.Contains(fake.sent[0].Text, "Hi <Ada>,") {
+			t.Errorf("unexpected email %+v", fake.sent[0])
+		}
+		if count := notifications(t, user); count != 1 {
+			t.Errorf("%d notifications, want 1", count)
+		}
+		if stored := record(t, task); stored.EmailPending || stored.EmailSentAt == 0 {
+			t.Errorf("reminder = %+v, want the email marked as sent", stored)
+		}
+	})
+
+	t.Run("retries only the email when sending fails", func(t *testing.T) {
+		fake := useFakeMailer(t, errors.New("connection refused"))
+		user, task := setup(t)
+		if err := sendReminder(task, user.Id, notificationDeadline, time.Hour, now); err == nil {
+			t.Fatal("sendReminder succeeded while the mail server is down")
+		}
+		if stored := record(t, task); !stored.EmailPending {
+			t.Fatalf("reminder = %+v, want the email pending", stored)
+		}
+		// The next check neither notifies again nor retries the email before the claim ends
+		if err := checkReminders(now); err != nil {
+			t.Fatal(err)
+		}
+		if err := retryReminderEmails(now); err != nil || len(fake.sent) != 1 {
+			t.Fatalf("retried before the claim ended: %v, %d emails", err, len(fake.sent))
+		}
+		fake.err = nil
+		if err := retryReminderEmails(now.Add(reminderEmailLease)); err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.sent) != 2 {
+			t.Errorf("%d emails, want the retry to send a second one", len(fake.sent))
+		}
+		if count := notifications(t, user); count != 1 {
+			t.Errorf("%d notifications, want 1", count)
+		}
+		if stored := record(t, task); stored.EmailPending || stored.EmailSentAt == 0 {
+			t.Errorf("reminder = %+v, want the email marked as sent", stored)
+		}
+	})
+
+	t.Run("drops the email of a completed task", func(t *testing.T) {
+		fake := useFakeMailer(t, errors.New("connection refused"))
+		user, task := setup(t)
+		if err := sendReminder(task, user.Id, notificationDeadline, time.Hour, now); err == nil {
+			t.Fatal("sendReminder succeeded while the mail server is down")
+		}
+		if _, err := tasksDb.UpdateOne(context.TODO(), bson.D{{"_id", task.Id}}, bson.D{{"$set", bson.D{{"completed_at", now.Unix()}}}}); err != nil {
+			t.Fatal(err)
+		}
+		fake.err = nil
+		if err := retryReminderEmails(now.Add(reminderEmailLease)); err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.sent) != 1 {
+			t.Errorf("%d emails, want no retry", len(fake.sent))
+		}
+		if stored := record(t, task); stored.EmailPending {
+			t.Errorf("reminder = %+v, want the email dropped", stored)
+		}
+	})
+
+	t.Run("no email when emails are off", func(t *testing.T) {
+		fake := useFakeMailer(t, nil)
+		user, task := setup(t)
+		if _, err := usersDb.UpdateOne(context.TODO(), bson.D{{"_id", user.Id}}, bson.D{{"$set", bson.D{{"email_digest", digestOff}}}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sendReminder(task, user.Id, notificationOverdue, 0, now); err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.sent) != 0 || notifications(t, user) != 1 {
+			t.Errorf("sent %d emails, want only the in-app reminder", len(fake.sent))
+		}
+	})
+}
@@ -0,0 +1,417 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var dueRegex = regexp.MustCompile(`^([<>])(\d+)([hdw])$`)
+
+var errInvalidSearch = errors.New("invalid search query")
+
+type searchQuery struct {
+	Terms    []string // free text, phrases are kept as one term
+	Phrases  []bool
+	Boards   []string
+	Labels   []string
+	Assignee string
+	DueFrom  int64
+	DueTo    int64
+	Status   string
+}
+
+type searchToken struct {
+	text   string
+	quoted bool
+}
+
+// tokenizeSearch splits a query on whitespace while keeping "quoted phrases" and key:"quoted values" together.
+func tokenizeSearch(q string) []searchToken {
+	tokens := make([]searchToken, 0)
+	var current strings.Builder
+	inQuotes, quoted := false, false
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, searchToken{text: current.String(), quoted: quoted})
+		}
+		current.Reset()
+		quoted = false
+	}
+	for _, r := range q {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			if current.Len() == 0 {
+				quoted = true
+			}
+		case !inQuotes && (r == ' ' || r == '\t' || r == '\n'):
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// parseSearchQuery understands board:, label:, assignee:, due: and is: filters. Everything else is free text.
+func parseSearchQuery(q string, now time.Time) (searchQuery, error) {
+	var query searchQuery
+	for _, token := range tokenizeSearch(q) {
+		key, value, found := strings.Cut(token.text, ":")
+		if token.quoted || !found || value == "" {
+			query.Terms = append(query.Terms, token.text)
+			query.Phrases = append(query.Phrases, token.quoted)
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "board":
+			query.Boards = append(query.Boards, value)
+		case "label":
+			query.Labels = append(query.Labels, value)
+		case "assignee":
+			query.Assignee = value
+		case "is":
+			switch strings.ToLower(value) {
+			case "open":
+				query.Status = "open"
+			case "done", "closed":
+				query.Status = "done"
+			default:
+				return query, errInvalidSearch
+			}
+		case "due":
+			from, to, onlyOpen, err := parseDueFilter(value, now)
+			if err != nil {
+				return query, err
+			}
+			query.DueFrom, query.DueTo = from, to
+			if onlyOpen {
+				query.Status = "open"
+			}
+		default:
+			query.Terms = append(query.Terms, token.text)
+			query.Phrases = append(query.Phrases, false)
+		}
+	}
+	return query, nil
+}
+
+// parseDueFilter resolves relative deadline filters such as <7d, >2w or overdue to a deadline range.
+// Overdue only makes sense for open tasks, which is reported through onlyOpen.
+func parseDueFilter(value string, now time.Time) (from int64, to int64, onlyOpen bool, err error) {
+	if strings.ToLower(value) == "overdue" {
+		return 1, now.Unix(), true, nil
+	}
+	match := dueRegex.FindStringSubmatch(value)
+	if match == nil {
+		return 0, 0, false, errInvalidSearch
+	}
+	amount, _ := strconv.Atoi(match[2])
+	unit := map[string]time.Duration{"h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}[match[3]]
+	bound := now.Add(time.Duration(amount) * unit).Unix()
+	if match[1] == "<" {
+		return now.Unix(), bound, false, nil
+	}
+	return bound, 0, false, nil
+}
+
+// statusFilter matches open or completed tasks.
+func statusFilter(status string) (bson.E, bool) {
+	switch status {
+	case "open":
+		return bson.E{"completed_at", bson.D{{"$in", bson.A{0, nil}}}}, true
+	case "done":
+		return bson.E{"completed_at", bson.D{{"$gt", 0}}}, true
+	}
+	return bson.E{}, false
+}
+
+// textSearchString renders the free text terms in MongoDB $text syntax.
+func (query searchQuery) textSearchString() string {
+	parts := make([]string, 0, len(query.Terms))
+	for i, term := range query.Terms {
+		if query.Phrases[i] {
+			parts = append(parts, `"`+strings.ReplaceAll(term, `"`, "")+`"`)
+		} else {
+			parts = append(parts, term)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// highlight finds every case-insensitive occurrence of the terms in text.
+// Long texts are cut to a fragment around the first match, indices are relative to the fragment.
+func highlight(field string, text string, terms []string) (SearchHighlight, bool) {
+	const maxFragment = 160
+	matches := make([][]int, 0)
+	for _, term := range terms {
+		if term == "" {
+			continue
+		}
+		re := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(term))
+		matches = append(matches, re.FindAllStringIndex(text, -1)...)
+	}
+	if len(matches) == 0 {
+		return SearchHighlight{}, false
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i][0] < matches[j][0] })
+	start, end := 0, len(text)
+	if len(text) > maxFragment {
+		start = max(0, matches[0][0]-maxFragment/4)
+		for start > 0 && !utf8.RuneStart(text[start]) {
+			start--
+		}
+		end = min(len(text), start+maxFragment)
+		for end < len(text) && !utf8.RuneStart(text[end]) {
+			end++
+		}
+	}
+	fragmentMatches := make([][]int, 0, len(matches))
+	for _, match := range matches {
+		if match[0] >= start && match[1] <= end {
+			fragmentMatches = append(fragmentMatches, []int{match[0] - start, match[1] - start})
+		}
+	}
+	return SearchHighlight{Field: field, Fragment: text[start:end], Matches: fragmentMatches}, true
+}
+
+// @Summary 		Search tasks
+// @Description 	Searches task names, descriptions and labels across every workspace the current user is a member of. Tasks have no comments, imported comments are part of the description and are searched with it.
+// @Description 	Supports the filters board:<name or id>, label:<name>, assignee:me, assignee:<userId>, due:<7d, due:>2w, due:overdue, is:open and is:done. Use quotes for phrases.
+// @Router 			/search [get]
+// @Tags 			Tasks
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			q query string true "Search query"
+// @Param 			limit query int false "Maximum number of results (default 20, max 100)"
+// @Success 		200 {object} SearchResponse "Ranked search results"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid query"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func searchTasks(c *gin.Context) {
+	id, _ := c.Get("id")
+	userId := id.(bson.ObjectID)
+	query, err := parseSearchQuery(c.Query("q"), time.Now().UTC())
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Invalid search filter"})
+		return
+	}
+	limit := int64(20)
+	if l := c.Query("limit"); l != "" {
+		limit, err = strconv.ParseInt(l, 10, 64)
+		if err != nil || limit < 1 || limit > 100 {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Field 'limit' must be between 1 and 100"})
+			return
+		}
+	}
+
+	// Same authorization as getAllWorkspaces
+	cursor, err := workspacesDb.Find(context.TODO(), bson.D{{"members", userId}}, options.Find().SetProjection(bson.D{{"_id", 1}}))
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	workspaces := make([]Workspace, 0)
+	if err := cursor.All(context.TODO(), &workspaces); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	workspaceIds := make([]bson.ObjectID, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		workspaceIds = append(workspaceIds, workspace.Id)
+	}
+	results := make([]SearchResult, 0)
+	if len(workspaceIds) == 0 {
+		c.JSON(200, gin.H{"results": results})
+		return
+	}
+
+	filter := bson.D{{"created_by", bson.D{{"$in", workspaceIds}}}}
+	if len(query.Boards) > 0 {
+		boardFilters := bson.A{}
+		for _, board := range query.Boards {
+			if boardId, err := bson.ObjectIDFromHex(board); err == nil {
+				boardFilters = append(boardFilters, bson.D{{"_id", boardId}})
+			} else {
+				boardFilters = append(boardFilters, bson.D{{"name", bson.D{{"$regex", "^" + regexp.QuoteMeta(board) + "$"}, {"$options", "i"}}}})
+			}
+		}
+		boardIds, err := distinctIds(boardsDb, bson.D{{"owned_by", bson.D{{"$in", workspaceIds}}}, {"$or", boardFilters}})
+		if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		filter = append(filter, bson.E{"board", bson.D{{"$in", boardIds}}})
+	}
+	if len(query.Labels) > 0 {
+		labelIds, err := labelIdsByName(workspaceIds, query.Labels)
+		if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		filter = append(filter, bson.E{"labels", bson.D{{"$in", labelIds}}})
+	}
+	if query.Assignee != "" {
+		assignee := userId
+		if strings.ToLower(query.Assignee) != "me" {
+			if assignee, err = bson.ObjectIDFromHex(query.Assignee); err != nil {
+				c.AbortWithStatusJSON(400, gin.H{"error": "Invalid assignee"})
+				return
+			}
+		}
+		filter = append(filter, bson.E{"assignees", assignee})
+	}
+	if query.DueFrom != 0 || query.DueTo != 0 {
+		due := bson.D{{"$gte", max(query.DueFrom, 1)}}
+		if query.DueTo != 0 {
+			due = append(due, bson.E{"$lte", query.DueTo})
+		}
+		filter = append(filter, bson.E{"deadline", due})
+	}
+	if status, ok := statusFilter(query.Status); ok {
+		filter = append(filter, status)
+	}
+
+	type scoredTask struct {
+		Task  `bson:",inline"`
+		Score float64 `bson:"score"`
+	}
+	byId := map[bson.ObjectID]*SearchResult{}
+	order := make([]bson.ObjectID, 0)
+	addHits := func(hits []scoredTask, bonus float64) {
+		for _, hit := range hits {
+			if existing, ok := byId[hit.Id]; ok {
+				existing.Score += hit.Score + bonus
+				continue
+			}
+			byId[hit.Id] = &SearchResult{Task: hit.Task, Score: hit.Score + bonus, Highlights: make([]SearchHighlight, 0)}
+			order = append(order, hit.Id)
+		}
+	}
+	find := func(filter bson.D, opts *options.FindOptionsBuilder) ([]scoredTask, error) {
+		cursor, err := tasksDb.Find(context.TODO(), filter, opts)
+		if err != nil {
+			return nil, err
+		}
+		hits := make([]scoredTask, 0)
+		err = cursor.All(context.TODO(), &hits)
+		return hits, err
+	}
+
+	var matchedLabels []Label
+	if len(query.Terms) > 0 {
+		textFilter := append(slices.Clone(filter), bson.E{"$text", bson.D{{"$search", query.textSearchString()}}})
+		hits, err := find(textFilter, options.Find().
+			SetProjection(bson.D{{"score", bson.D{{"$meta", "textScore"}}}}).
+			SetSort(bson.D{{"score", bson.D{{"$meta", "textScore"}}}}).
+			SetLimit(limit))
+		if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		addHits(hits, 0)
+
+		// Free text that names a label also finds tasks carrying that label
+		matchedLabels, err = labelsByName(workspaceIds, query.Terms)
+		if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		if len(matchedLabels) > 0 {
+			labelIds := make([]bson.ObjectID, 0, len(matchedLabels))
+			for _, label := range matchedLabels {
+				labelIds = append(labelIds, label.Id)
+			}
+			hits, err := find(append(slices.Clone(filter), bson.E{"labels", bson.D{{"$in", labelIds}}}), options.Find().SetLimit(limit))
+			if err != nil {
+				c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+				return
+			}
+			addHits(hits, 1)
+		}
+	} else {
+		hits, err := find(filter, options.Find().SetSort(bson.D{{"created_at", -1}}).SetLimit(limit))
+		if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		addHits(hits, 0)
+	}
+
+	for _, taskId := range order {
+		result := byId[taskId]
+		if h, ok := highlight("name", result.Task.Name, query.Terms); ok {
+			result.Highlights = append(result.Highlights, h)
+		}
+		if h, ok := highlight("description", result.Task.Description, query.Terms); ok {
+			result.Highlights = append(result.Highlights, h)
+		}
+		for _, label := range matchedLabels {
+			if slices.Contains(result.Task.Labels, label.Id) {
+				result.Highlights = append(result.Highlights, SearchHighlight{Field: "label", Fragment: label.Name, Matches: [][]int{{0, len(label.Name)}}})
+			}
+		}
+		results = append(results, *result)
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if int64(len(results)) > limit {
+		results = results[:limit]
+	}
+	c.JSON(200, gin.H{"results": results})
+}
+
+func labelsByName(workspaceIds []bson.ObjectID, names []string) ([]Label, error) {
+	nameFilters := bson.A{}
+	for _, name := range names {
+		nameFilters = append(nameFilters, bson.D{{"name", bson.D{{"$regex", "^" + regexp.QuoteMeta(name) + "$"}, {"$options", "i"}}}})
+	}
+	cursor, err := labelsDb.Find(context.TODO(), bson.D{{"workspace", bson.D{{"$in", workspaceIds}}}, {"$or", nameFilters}})
+	if err != nil {
+		return nil, err
+	}
+	labels := make([]Label, 0)
+	err = cursor.All(context.TODO(), &labels)
+	return labels, err
+}
+
+func labelIdsByName(workspaceIds []bson.ObjectID, names []string) ([]bson.ObjectID, error) {
+	labels, err := labelsByName(workspaceIds, names)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]bson.ObjectID, 0, len(labels))
+	for _, label := range labels {
+		ids = append(ids, label.Id)
+	}
+	return ids, nil
+}
+
+func distinctIds(collection *mongo.Collection, filter bson.D) ([]bson.ObjectID, error) {
+	cursor, err := collection.Find(context.TODO(), filter, options.Find().SetProjection(bson.D{{"_id", 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		Id bson.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(context.TODO(), &docs); err != nil {
+		return nil, err
+	}
+	ids := make([]bson.ObjectID, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.Id)
+	}
+	return ids, nil
+}
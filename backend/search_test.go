@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	day := int64(24 * 60 * 60)
+	tests := []struct {
+		q    string
+		want searchQuery
+	}{
+		{"", searchQuery{}},
+		{"login bug", searchQuery{Terms: []string{"login", "bug"}, Phrases: []bool{false, false}}},
+		{`"login page" crash`, searchQuery{Terms: []string{"login page", "crash"}, Phrases: []bool{true, false}}},
+		{`"status: broken"`, searchQuery{Terms: []string{"status: broken"}, Phrases: []bool{true}}},
+		{`board:Backend label:"in progress" label:bug`, searchQuery{Boards: []string{"Backend"}, Labels: []string{"in progress", "bug"}}},
+		{"assignee:me is:open", searchQuery{Assignee: "me", Status: "open"}},
+		{"IS:Closed", searchQuery{Status: "done"}},
+		{"due:<7d", searchQuery{DueFrom: now.Unix(), DueTo: now.Unix() + 7*day}},
+		{"due:>2w", searchQuery{DueFrom: now.Unix() + 14*day}},
+		{"due:<12h", searchQuery{DueFrom: now.Unix(), DueTo: now.Add(12 * time.Hour).Unix()}},
+		{"due:overdue", searchQuery{DueFrom: 1, DueTo: now.Unix(), Status: "open"}},
+		{"http://example.com note:", searchQuery{Terms: []string{"http://example.com", "note:"}, Phrases: []bool{false, false}}},
+		{"  spaced\tout\n", searchQuery{Terms: []string{"spaced", "out"}, Phrases: []bool{false, false}}},
+	}
+	for _, test := range tests {
+		got, err := parseSearchQuery(test.q, now)
+		if err != nil {
+			t.Errorf("parseSearchQuery(%q) failed: %v", test.q, err)
+		} else if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parseSearchQuery(%q) = %+v, want %+v", test.q, got, test.want)
+		}
+	}
+
+	for _, q := range []string{"is:blocked", "due:soon", "due:<7m", "due:7d"} {
+		if _, err := parseSearchQuery(q, now); !errors.Is(err, errInvalidSearch) {
+			t.Errorf("parseSearchQuery(%q) = %v, want errInvalidSearch", q, err)
+		}
+	}
+}
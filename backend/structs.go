@@ -6,31 +6,127 @@ import (
 )
 
 type Task struct {
-	Id          bson.ObjectID `json:"_id" bson:"_id,omitempty"`
-	Name        string        `json:"name" bson:"name"`
-	Description string        `json:"description" bson:"description"`
-	CreatedAt   int64         `json:"created_at" bson:"created_at"`
-	CreatedBy   bson.ObjectID `json:"created_by" bson:"created_by"`
-	Board       bson.ObjectID `json:"board" bson:"board"`
-	Deadline    int64         `json:"deadline" bson:"deadline"`
+	Id           bson.ObjectID   `json:"_id" bson:"_id,omitempty"`
+	Key          string          `json:"key" bson:"key,omitempty"`
+	Number       int64           `json:"number" bson:"number,omitempty"`
+	Name         string          `json:"name" bson:"name"`
+	Description  string          `json:"description" bson:"description"`
+	CreatedAt    int64           `json:"created_at" bson:"created_at"`
+	CreatedBy    bson.ObjectID   `json:"created_by" bson:"created_by"`
+	Board        bson.ObjectID   `json:"board" bson:"board"`
+	Deadline     int64           `json:"deadline" bson:"deadline"`
+	CompletedAt  int64           `json:"completed_at" bson:"completed_at"`
+	Links        []TaskLink      `json:"links" bson:"links,omitempty"`
+	Labels       []bson.ObjectID `json:"labels" bson:"labels,omitempty"`
+	Priority     string          `json:"priority" bson:"priority"`
+	PriorityRank int             `json:"-" bson:"priority_rank"`
+	Estimate     TaskEstimate    `json:"estimate" bson:"estimate"`
+	CustomFields map[string]any  `json:"custom_fields" bson:"custom_fields,omitempty"`
+	Assignees    []bson.ObjectID `json:"assignees" bson:"assignees,omitempty"`
+	Recurrence   *TaskRecurrence `json:"recurrence,omitempty" bson:"recurrence,omitempty"`
+	Watchers     []bson.ObjectID `json:"watchers" bson:"watchers,omitempty"`
+	ExternalId   string          `json:"external_id,omitempty" bson:"external_id,omitempty"`
+	// The UID and resource name CalDAV clients gave tasks they created
+	IcalUid  string `json:"-" bson:"ical_uid,omitempty"`
+	IcalName string `json:"-" bson:"ical_name,omitempty"`
+}
+
+// TaskRecurrence makes a task one occurrence of a series. In completion mode the next occurrence is
+// created when this one is completed, in schedule mode also once its deadline has passed.
+type TaskRecurrence struct {
+	Rule         string         `json:"rule" bson:"rule"`
+	Timezone     string         `json:"timezone" bson:"timezone"`
+	Mode         string         `json:"mode" bson:"mode"`
+	Series       bson.ObjectID  `json:"series" bson:"series"`
+	Start        int64          `json:"start" bson:"start"`
+	Occurrence   int            `json:"occurrence" bson:"occurrence"`
+	NextTask     *bson.ObjectID `json:"next_task,omitempty" bson:"next_task,omitempty"`
+	Ended        bool           `json:"ended,omitempty" bson:"ended,omitempty"`
+	ClaimedUntil int64          `json:"-" bson:"claimed_until,omitempty"`
+}
+
+type TaskRecurrenceInput struct {
+	Rule     string `json:"rule"`
+	Timezone string `json:"timezone"`
+	Mode     string `json:"mode"`
+}
+
+type TaskEstimate struct {
+	Unit  string  `json:"unit" bson:"unit"`
+	Value float64 `json:"value" bson:"value"`
+}
+
+type EstimateTotals struct {
+	Points      float64 `json:"points" bson:"points"`
+	Minutes     float64 `json:"minutes" bson:"minutes"`
+	OpenPoints  float64 `json:"open_points" bson:"open_points"`
+	OpenMinutes float64 `json:"open_minutes" bson:"open_minutes"`
+}
+
+type TaskLink struct {
+	Type string        `json:"type" bson:"type"`
+	Task bson.ObjectID `json:"task" bson:"task"`
+}
+
+type CreateTaskLink struct {
+	Type string        `json:"type" bson:"type"`
+	Task bson.ObjectID `json:"task" bson:"task"`
+}
+
+type BlockedTaskSwagger struct {
+	Error     string          `json:"error"`
+	BlockedBy []bson.ObjectID `json:"blocked_by"`
+}
+
+type WatchedTasksResponse struct {
+	Tasks      []Task `json:"tasks"`
+	NextCursor string `json:"next_cursor"`
 }
 
 type AllTasksResponse struct {
-	Tasks []Task `json:"tasks"`
+	Tasks      []Task         `json:"tasks"`
+	Estimate   EstimateTotals `json:"estimate"`
+	NextCursor string         `json:"next_cursor"`
+}
+
+type TaskQuery struct {
+	Label        string            `json:"label" bson:"label"`
+	Assignee     string            `json:"assignee" bson:"assignee"`
+	Text         string            `json:"text" bson:"text"`
+	DeadlineFrom int64             `json:"deadline_from" bson:"deadline_from"`
+	DeadlineTo   int64             `json:"deadline_to" bson:"deadline_to"`
+	CreatedFrom  int64             `json:"created_from" bson:"created_from"`
+	CreatedTo    int64             `json:"created_to" bson:"created_to"`
+	Status       string            `json:"status" bson:"status"`
+	Due          string            `json:"due" bson:"due"`
+	CustomFields map[string]string `json:"custom_fields" bson:"custom_fields"`
+	Sort         string            `json:"sort" bson:"sort"`
 }
 
 type CreateTask struct {
-	Name        string        `json:"name" bson:"name"`
-	Description string        `json:"description" bson:"description"`
-	Board       bson.ObjectID `json:"board" bson:"board"`
+	Name         string               `json:"name" bson:"name"`
+	Description  string               `json:"description" bson:"description"`
+	Board        bson.ObjectID        `json:"board" bson:"board"`
+	Labels       []bson.ObjectID      `json:"labels" bson:"labels"`
+	Priority     string               `json:"priority" bson:"priority"`
+	Estimate     TaskEstimate         `json:"estimate" bson:"estimate"`
+	CustomFields map[string]any       `json:"custom_fields" bson:"custom_fields"`
+	Deadline     int64                `json:"deadline" bson:"deadline"`
+	Recurrence   *TaskRecurrenceInput `json:"recurrence" bson:"recurrence"`
 }
 
 type EditTask struct {
-	Name        string `json:"name" bson:"name"`
-	Description string `json:"description" bson:"description"`
-	CompletedAt int64  `json:"completed_at" bson:"completed_at"`
-	Board       string `json:"board" bson:"board"`
-	Deadline    int64  `json:"deadline" bson:"deadline"`
+	Name         string               `json:"name" bson:"name"`
+	Description  string               `json:"description" bson:"description"`
+	CompletedAt  *int64               `json:"completed_at" bson:"completed_at"`
+	Board        string               `json:"board" bson:"board"`
+	Deadline     int64                `json:"deadline" bson:"deadline"`
+	AddLabels    []bson.ObjectID      `json:"add_labels" bson:"add_labels"`
+	RemoveLabels []bson.ObjectID      `json:"remove_labels" bson:"remove_labels"`
+	Priority     string               `json:"priority" bson:"priority"`
+	Estimate     *TaskEstimate        `json:"estimate" bson:"estimate"`
+	CustomFields map[string]any       `json:"custom_fields" bson:"custom_fields"`
+	Recurrence   *TaskRecurrenceInput `json:"recurrence" bson:"recurrence"`
 }
 
 type User struct {
@@ -40,6 +136,17 @@ type User struct {
 	Email          string        `json:"email" bson:"email"`
 	HashedPassword string        `json:"-" bson:"hashed_password"`
 	Salt           string        `json:"-" bson:"salt"`
+	Timezone       string        `json:"timezone" bson:"timezone,omitempty"`
+	EmailDigest    string        `json:"email_digest" bson:"email_digest,omitempty"`
+	DigestSentAt   int64         `json:"-" bson:"digest_sent_at,omitempty"`
+}
+
+type EditUser struct {
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	Password    string `json:"password"`
+	Timezone    string `json:"timezone"`
+	EmailDigest string `json:"email_digest"`
 }
 
 type CreateUser struct {
@@ -52,6 +159,11 @@ type TokenSwagger struct {
 	Token string `json:"token" bson:"token"`
 }
 
+type StreamTicket struct {
+	Ticket    string `json:"ticket"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
 type ErrorSwagger struct {
 	Error string `json:"error"`
 }
@@ -68,26 +180,66 @@ type LoginUser struct {
 }
 
 type Board struct {
-	Id      bson.ObjectID `json:"_id" bson:"_id,omitempty"`
-	Name    string        `json:"name" bson:"name"`
-	OwnedBy bson.ObjectID `json:"owned_by" bson:"owned_by"`
+	Id       bson.ObjectID   `json:"_id" bson:"_id,omitempty"`
+	Name     string          `json:"name" bson:"name"`
+	OwnedBy  bson.ObjectID   `json:"owned_by" bson:"owned_by"`
+	Watchers []bson.ObjectID `json:"watchers" bson:"watchers,omitempty"`
+	Estimate *EstimateTotals `json:"estimate,omitempty" bson:"-"`
 }
 
 type AllBoardsResponse struct {
-	Boards []Board `json:"boards"`
+	Boards     []Board `json:"boards"`
+	NextCursor string  `json:"next_cursor"`
 }
 
 type CreateBoard struct {
 	Name string `json:"name" bson:"name"`
 }
 
+type DuplicateBoard struct {
+	Name         string `json:"name"`
+	IncludeTasks bool   `json:"include_tasks"`
+}
+
+type DuplicateBoardResponse struct {
+	Board Board `json:"board"`
+	Tasks int   `json:"tasks"`
+}
+
+type TransferBoard struct {
+	Workspace bson.ObjectID `json:"workspace"`
+	// Transfer even if assignees aren't members of the target workspace, their assignments are removed
+	Force bool `json:"force"`
+}
+
+// NonMemberAssignee is a user assigned to tasks of a transferred board who isn't a member of the target workspace.
+type NonMemberAssignee struct {
+	Id    bson.ObjectID `json:"_id"`
+	Name  string        `json:"name"`
+	Email string        `json:"email"`
+	Tasks []string      `json:"tasks"`
+}
+
+type TransferBoardResponse struct {
+	Board        Board               `json:"board"`
+	Tasks        int                 `json:"tasks"`
+	NonMembers   []NonMemberAssignee `json:"non_members"`
+	RemovedLinks int                 `json:"removed_links"`
+	RemovedViews int                 `json:"removed_views"`
+	// Tasks added to the board in the source workspace faster than they could be moved
+	UnmovedTasks []string `json:"unmoved_tasks"`
+}
+
 type Workspace struct {
-	Id      bson.ObjectID     `bson:"_id,omitempty" json:"_id"`
-	Avatar  string            `json:"avatar" bson:"avatar"`
-	Name    string            `json:"name"`
-	OwnedBy bson.ObjectID     `bson:"owned_by" json:"owned_by"`
-	Members []bson.ObjectID   `bson:"members" json:"members"`
-	Tokens  []WorkspaceTokens `json:"tokens" bson:"tokens"`
+	Id     bson.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Avatar string        `json:"avatar" bson:"avatar"`
+	Name   string        `json:"name"`
+	Key    string        `json:"key" bson:"key"`
+	// Earlier key prefixes, so task keys issued before a key change keep resolving
+	KeyAliases []string          `json:"key_aliases" bson:"key_aliases,omitempty"`
+	OwnedBy    bson.ObjectID     `bson:"owned_by" json:"owned_by"`
+	Members    []bson.ObjectID   `bson:"members" json:"members"`
+	Tokens     []WorkspaceTokens `json:"tokens" bson:"tokens"`
 }
 type WorkspaceTokens struct {
 	Sqid  string `json:"sqid" bson:"sqid"`
@@ -95,17 +247,239 @@ type WorkspaceTokens struct {
 	Uses  uint8  `json:"uses" bson:"uses"`
 }
 
+type Label struct {
+	Id        bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+	Name      string        `json:"name" bson:"name"`
+	Color     string        `json:"color" bson:"color"`
+	Workspace bson.ObjectID `json:"workspace" bson:"workspace"`
+}
+
+type CreateLabel struct {
+	Name  string `json:"name" bson:"name"`
+	Color string `json:"color" bson:"color"`
+}
+
+type AllLabelsResponse struct {
+	Labels []Label `json:"labels"`
+}
+
+type CustomField struct {
+	Id        bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+	Name      string        `json:"name" bson:"name"`
+	Type      string        `json:"type" bson:"type"`
+	Options   []string      `json:"options" bson:"options,omitempty"`
+	Workspace bson.ObjectID `json:"workspace" bson:"workspace"`
+}
+
+type CreateCustomField struct {
+	Name    string   `json:"name" bson:"name"`
+	Type    string   `json:"type" bson:"type"`
+	Options []string `json:"options" bson:"options"`
+}
+
+type EditCustomField struct {
+	Name    string   `json:"name" bson:"name"`
+	Options []string `json:"options" bson:"options"`
+}
+
+type AllCustomFieldsResponse struct {
+	Fields []CustomField `json:"fields"`
+}
+
+// Event describes a change in a workspace. It is streamed to connected clients.
+type Event struct {
+	Id            bson.ObjectID   `json:"_id" bson:"_id,omitempty"`
+	Type          string          `json:"type" bson:"type"`
+	Workspace     bson.ObjectID   `json:"workspace" bson:"workspace"`
+	BoardId       bson.ObjectID   `json:"board_id" bson:"board_id,omitempty"`
+	PreviousBoard bson.ObjectID   `json:"-" bson:"previous_board,omitempty"`
+	Actor         bson.ObjectID   `json:"actor" bson:"actor,omitempty"`
+	Task          *Task           `json:"task,omitempty" bson:"task,omitempty"`
+	Board         *Board          `json:"board,omitempty" bson:"board,omitempty"`
+	Member        *Member         `json:"member,omitempty" bson:"member,omitempty"`
+	Watchers      []bson.ObjectID `json:"-" bson:"watchers,omitempty"`
+	CreatedAt     int64           `json:"created_at" bson:"created_at"`
+}
+
+type Webhook struct {
+	Id        bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+	Workspace bson.ObjectID `json:"workspace" bson:"workspace"`
+	URL       string        `json:"url" bson:"url"`
+	Secret    string        `json:"secret,omitempty" bson:"secret"`
+	Events    []string      `json:"events" bson:"events"`
+	Active    bool          `json:"active" bson:"active"`
+	CreatedAt int64         `json:"created_at" bson:"created_at"`
+}
+
+type CreateWebhook struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+type EditWebhook struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
+
+type AllWebhooksResponse struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// WebhookPayload is the JSON body posted to webhook URLs.
+type WebhookPayload struct {
+	Event     string        `json:"event"`
+	Workspace bson.ObjectID `json:"workspace"`
+	CreatedAt int64         `json:"created_at"`
+	Data      any           `json:"data"`
+}
+
+type WebhookDelivery struct {
+	Id            bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+	Webhook       bson.ObjectID `json:"webhook" bson:"webhook"`
+	Workspace     bson.ObjectID `json:"workspace" bson:"workspace"`
+	Event         string        `json:"event" bson:"event"`
+	Payload       string        `json:"payload" bson:"payload"`
+	Status        string        `json:"status" bson:"status"`
+	Attempts      int           `json:"attempts" bson:"attempts"`
+	NextAttemptAt int64         `json:"next_attempt_at" bson:"next_attempt_at"`
+	ResponseCode  int           `json:"response_code" bson:"response_code"`
+	Error         string        `json:"error" bson:"error"`
+	CreatedAt     int64         `json:"created_at" bson:"created_at"`
+	DeliveredAt   int64         `json:"delivered_at" bson:"delivered_at"`
+	ClaimedUntil  int64         `json:"-" bson:"claimed_until"`
+}
+
+type AllWebhookDeliveriesResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	NextCursor string            `json:"next_cursor"`
+}
+
+// Notification is an inbox entry. Unread notifications about the same task are merged into one,
+// with the latest event as message and the most recent events kept in Events.
+type Notification struct {
+	Id        bson.ObjectID       `json:"_id" bson:"_id,omitempty"`
+	User      bson.ObjectID       `json:"user" bson:"user"`
+	Workspace bson.ObjectID       `json:"workspace" bson:"workspace"`
+	Task      bson.ObjectID       `json:"task" bson:"task,omitempty"`
+	Type      string              `json:"type" bson:"type"`
+	Message   string              `json:"message" bson:"message"`
+	Count     int                 `json:"count" bson:"count"`
+	Events    []NotificationEvent `json:"events" bson:"events"`
+	CreatedAt int64               `json:"created_at" bson:"created_at"`
+	UpdatedAt int64               `json:"updated_at" bson:"updated_at"`
+	Read      bool                `json:"read" bson:"read"`
+}
+
+type NotificationEvent struct {
+	Type      string        `json:"type" bson:"type"`
+	Message   string        `json:"message" bson:"message"`
+	Actor     bson.ObjectID `json:"actor" bson:"actor,omitempty"`
+	CreatedAt int64         `json:"created_at" bson:"created_at"`
+}
+
+type EditNotification struct {
+	Read bool `json:"read"`
+}
+
+type AllNotificationsResponse struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int64          `json:"unread"`
+	NextCursor    string         `json:"next_cursor"`
+}
+
+type SavedView struct {
+	Id            bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+	Name          string        `json:"name" bson:"name"`
+	Workspace     bson.ObjectID `json:"workspace" bson:"workspace"`
+	Board         bson.ObjectID `json:"board" bson:"board,omitempty"`
+	Owner         bson.ObjectID `json:"owner" bson:"owner"`
+	Shared        bool          `json:"shared" bson:"shared"`
+	Query         TaskQuery     `json:"query" bson:"query"`
+	GroupBy       string        `json:"group_by" bson:"group_by"`
+	VisibleFields []string      `json:"visible_fields" bson:"visible_fields"`
+	CreatedAt     int64         `json:"created_at" bson:"created_at"`
+}
+
+type CreateSavedView struct {
+	Name          string        `json:"name"`
+	Board         bson.ObjectID `json:"board"`
+	Shared        bool          `json:"shared"`
+	Query         TaskQuery     `json:"query"`
+	GroupBy       string        `json:"group_by"`
+	VisibleFields []string      `json:"visible_fields"`
+}
+
+type EditSavedView struct {
+	Name          string     `json:"name"`
+	Shared        *bool      `json:"shared"`
+	Query         *TaskQuery `json:"query"`
+	GroupBy       *string    `json:"group_by"`
+	VisibleFields []string   `json:"visible_fields"`
+}
+
+type AllSavedViewsResponse struct {
+	Views []SavedView `json:"views"`
+}
+
+type TaskGroup struct {
+	Key   string          `json:"key"`
+	Tasks []bson.ObjectID `json:"tasks"`
+}
+
+type SavedViewTasksResponse struct {
+	View       SavedView   `json:"view"`
+	Tasks      []Task      `json:"tasks"`
+	Groups     []TaskGroup `json:"groups"`
+	NextCursor string      `json:"next_cursor"`
+}
+
 type CreateWorkspace struct {
+	Name     string        `json:"name"`
+	Key      string        `json:"key"`
+	Template bson.ObjectID `json:"template"`
+}
+
+// WorkspaceTemplate is the structure of a workspace saved to start new ones from. Documents keep the
+// ids they had in the source workspace, they are remapped when a workspace is created.
+type WorkspaceTemplate struct {
+	Id           bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+	Name         string        `json:"name" bson:"name"`
+	Description  string        `json:"description" bson:"description"`
+	CreatedBy    bson.ObjectID `json:"created_by" bson:"created_by"`
+	Source       bson.ObjectID `json:"source" bson:"source"`
+	Boards       []Board       `json:"boards" bson:"boards"`
+	Labels       []Label       `json:"labels" bson:"labels"`
+	CustomFields []CustomField `json:"custom_fields" bson:"custom_fields"`
+	Tasks        []Task        `json:"tasks" bson:"tasks"`
+	CreatedAt    int64         `json:"created_at" bson:"created_at"`
+}
+
+type CreateWorkspaceTemplate struct {
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	IncludeTasks bool   `json:"include_tasks"`
+}
+
+type AllWorkspaceTemplatesResponse struct {
+	Templates []WorkspaceTemplate `json:"templates"`
+}
+
+type DuplicateWorkspace struct {
 	Name string `json:"name"`
 }
 
 type EditWorkspace struct {
 	Name   string `json:"name"`
 	Avatar string `json:"avatar"`
+	Key    string `json:"key"`
 }
 
 type AllWorkspacesResponse struct {
 	Workspaces []Workspace `json:"workspaces"`
+	NextCursor string      `json:"next_cursor"`
 }
 
 type AllMembersResponse struct {
@@ -116,6 +490,7 @@ type WorkspaceInfo struct {
 	Id            bson.ObjectID   `bson:"_id,omitempty" json:"_id"`
 	Avatar        string          `json:"avatar" bson:"avatar"`
 	Name          string          `json:"name"`
+	Key           string          `json:"key" bson:"key"`
 	OwnedBy       bson.ObjectID   `bson:"owned_by" json:"owned_by"`
 	Members       []bson.ObjectID `bson:"members" json:"-"`
 	MemberDetails []Member        `bson:"memberDetails" json:"memberDetails"`
@@ -136,3 +511,143 @@ type AssignTask struct {
 	TaskId bson.ObjectID `bson:"taskId" json:"taskId"`
 	UserId bson.ObjectID `bson:"userId" json:"userId"`
 }
+
+type SearchResponse struct {
+	Results []SearchResult `json:"results"`
+}
+
+type SearchResult struct {
+	Task       Task              `json:"task"`
+	Score      float64           `json:"score"`
+	Highlights []SearchHighlight `json:"highlights"`
+}
+
+type SearchHighlight struct {
+	Field    string  `json:"field"`
+	Fragment string  `json:"fragment"`
+	Matches  [][]int `json:"matches"`
+}
+
+type CsvRowError struct {
+	Row    int    `json:"row"`
+	Column string `json:"column"`
+	Error  string `json:"error"`
+}
+
+type CsvImportReport struct {
+	DryRun         bool          `json:"dry_run"`
+	Rows           int           `json:"rows"`
+	Imported       int           `json:"imported"`
+	Failed         int           `json:"failed"`
+	CreatedBoards  []string      `json:"created_boards"`
+	IgnoredColumns []string      `json:"ignored_columns"`
+	Errors         []CsvRowError `json:"errors"`
+}
+
+// Job is a long running operation like an import, started by a request and followed by polling.
+type Job struct {
+	Id         bson.ObjectID  `json:"_id" bson:"_id,omitempty"`
+	Type       string         `json:"type" bson:"type"`
+	Workspace  bson.ObjectID  `json:"workspace" bson:"workspace"`
+	CreatedBy  bson.ObjectID  `json:"created_by" bson:"created_by"`
+	Status     string         `json:"status" bson:"status"`
+	Progress   int            `json:"progress" bson:"progress"`
+	Total      int            `json:"total" bson:"total"`
+	Warnings   []string       `json:"warnings" bson:"warnings"`
+	Result     map[string]any `json:"result" bson:"result"`
+	Error      string         `json:"error,omitempty" bson:"error,omitempty"`
+	CreatedAt  int64          `json:"created_at" bson:"created_at"`
+	UpdatedAt  int64          `json:"updated_at" bson:"updated_at"`
+	FinishedAt int64          `json:"finished_at,omitempty" bson:"finished_at,omitempty"`
+}
+
+type AllJobsResponse struct {
+	Jobs       []Job  `json:"jobs"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// BackupManifest describes a workspace backup archive. Restores refuse archives of a newer version.
+type BackupManifest struct {
+	Format     string         `json:"format"`
+	Version    int            `json:"version"`
+	ExportedAt int64          `json:"exported_at"`
+	Workspace  string         `json:"workspace"`
+	Counts     map[string]int `json:"counts"`
+}
+
+// BackupMember references a member of a backed up workspace. Restores match members by email.
+type BackupMember struct {
+	Id     bson.ObjectID `json:"_id" bson:"_id"`
+	Name   string        `json:"name" bson:"name"`
+	Email  string        `json:"email" bson:"email"`
+	Avatar string        `json:"avatar,omitempty" bson:"avatar,omitempty"`
+	Owner  bool          `json:"owner,omitempty" bson:"owner,omitempty"`
+}
+
+// FeedToken lets a calendar or feed reader, which can't send a bearer header, read a feed of one
+// user through a secret URL. Only a hash of the token is stored.
+type FeedToken struct {
+	Id         bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+	User       bson.ObjectID `json:"user" bson:"user"`
+	Kind       string        `json:"kind" bson:"kind"`
+	Scope      string        `json:"scope" bson:"scope"`
+	Workspace  bson.ObjectID `json:"workspace" bson:"workspace,omitempty"`
+	Board      bson.ObjectID `json:"board" bson:"board,omitempty"`
+	Component  string        `json:"component,omitempty" bson:"component,omitempty"`
+	TokenHash  string        `json:"-" bson:"token_hash"`
+	Url        string        `json:"url,omitempty" bson:"-"`
+	CreatedAt  int64         `json:"created_at" bson:"created_at"`
+	LastUsedAt int64         `json:"last_used_at" bson:"last_used_at"`
+}
+
+type CreateFeedToken struct {
+	Kind      string        `json:"kind"`
+	Scope     string        `json:"scope"`
+	Workspace bson.ObjectID `json:"workspace"`
+	Board     bson.ObjectID `json:"board"`
+	Component string        `json:"component"`
+}
+
+type AllFeedTokensResponse struct {
+	Feeds []FeedToken `json:"feeds"`
+}
+
+// AppPassword lets clients that can't log in, like calendar apps, authenticate as a user. It works as
+// the password of HTTP basic auth with the email of the user or as a bearer token. Only a hash is stored.
+type AppPassword struct {
+	Id         bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+	User       bson.ObjectID `json:"user" bson:"user"`
+	Name       string        `json:"name" bson:"name"`
+	Hash       string        `json:"-" bson:"hash"`
+	Password   string        `json:"password,omitempty" bson:"-"`
+	CreatedAt  int64         `json:"created_at" bson:"created_at"`
+	LastUsedAt int64         `json:"last_used_at" bson:"last_used_at"`
+}
+
+type CreateAppPassword struct {
+	Name string `json:"name"`
+}
+
+type AllAppPasswordsResponse struct {
+	AppPasswords []AppPassword `json:"app_passwords"`
+}
+
+// ExportTemplate is a Go text/template a workspace owner uploaded to render Markdown exports.
+type ExportTemplate struct {
+	Id        bson.ObjectID `json:"_id" bson:"_id,omitempty"`
+	Workspace bson.ObjectID `json:"workspace" bson:"workspace"`
+	Name      string        `json:"name" bson:"name"`
+	Template  string        `json:"template" bson:"template"`
+	CreatedBy bson.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedAt int64         `json:"created_at" bson:"created_at"`
+	UpdatedAt int64         `json:"updated_at" bson:"updated_at"`
+}
+
+type CreateExportTemplate struct {
+	Name     string `json:"name"`
+	Template string `json:"template"`
+}
+
+type AllExportTemplatesResponse struct {
+	Templates []ExportTemplate `json:"templates"`
+}
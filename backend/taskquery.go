@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var errInvalidTaskQuery = errors.New("invalid task query")
+
+// taskQueryFromRequest reads the task listing filters and sort key from query parameters.
+func taskQueryFromRequest(c *gin.Context) (TaskQuery, error) {
+	query := TaskQuery{
+		Label:        c.Query("label"),
+		Assignee:     c.Query("assignee"),
+		Text:         c.Query("q"),
+		Status:       c.Query("status"),
+		Due:          c.Query("due"),
+		CustomFields: c.QueryMap("cf"),
+		Sort:         c.Query("sort"),
+	}
+	ranges := []struct {
+		param string
+		value *int64
+	}{
+		{"deadline_from", &query.DeadlineFrom},
+		{"deadline_to", &query.DeadlineTo},
+		{"created_from", &query.CreatedFrom},
+		{"created_to", &query.CreatedTo},
+	}
+	for _, r := range ranges {
+		if raw := c.Query(r.param); raw != "" {
+			value, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				return query, fmt.Errorf("%w: field '%s' must be a unix timestamp", errInvalidTaskQuery, r.param)
+			}
+			*r.value = value
+		}
+	}
+	return query, nil
+}
+
+func timeRange(from int64, to int64) bson.D {
+	bounds := bson.D{}
+	if from != 0 {
+		bounds = append(bounds, bson.E{"$gte", from})
+	}
+	if to != 0 {
+		bounds = append(bounds, bson.E{"$lte", to})
+	}
+	return bounds
+}
+
+// taskQueryFilter turns a task query into a MongoDB filter for the tasks of a workspace.
+// "me" as assignee refers to the current user.
+func taskQueryFilter(workspaceId bson.ObjectID, userId bson.ObjectID, query TaskQuery) (bson.D, error) {
+	filter := bson.D{{"created_by", workspaceId}}
+	if query.Label != "" {
+		labelId, err := bson.ObjectIDFromHex(query.Label)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid label id", errInvalidTaskQuery)
+		}
+		filter = append(filter, bson.E{"labels", labelId})
+	}
+	if query.Assignee != "" {
+		assignee := userId
+		if query.Assignee != "me" {
+			var err error
+			if assignee, err = bson.ObjectIDFromHex(query.Assignee); err != nil {
+				return nil, fmt.Errorf("%w: invalid assignee", errInvalidTaskQuery)
+			}
+		}
+		filter = append(filter, bson.E{"assignees", assignee})
+	}
+	if query.Text != "" {
+		pattern := bson.D{{"$regex", regexp.QuoteMeta(query.Text)}, {"$options", "i"}}
+		filter = append(filter, bson.E{"$or", bson.A{
+			bson.D{{"name", pattern}},
+			bson.D{{"description", pattern}},
+		}})
+	}
+	if bounds := timeRange(query.DeadlineFrom, query.DeadlineTo); len(bounds) > 0 {
+		filter = append(filter, bson.E{"deadline", bounds})
+	}
+	if bounds := timeRange(query.CreatedFrom, query.CreatedTo); len(bounds) > 0 {
+		filter = append(filter, bson.E{"created_at", bounds})
+	}
+	status := query.Status
+	if query.Due != "" {
+		// Relative deadlines are resolved at execution time so saved views stay current
+		from, to, onlyOpen, err := parseDueFilter(query.Due, time.Now().UTC())
+		if err != nil {
+			return nil, fmt.Errorf("%w: field 'due' must be overdue, <Nh, <Nd, <Nw, >Nh, >Nd or >Nw", errInvalidTaskQuery)
+		}
+		filter = append(filter, bson.E{"deadline", timeRange(from, to)})
+		if onlyOpen {
+			status = "open"
+		}
+	}
+	if status != "" {
+		statusMatch, ok := statusFilter(status)
+		if !ok {
+			return nil, fmt.Errorf("%w: field 'status' must be open or done", errInvalidTaskQuery)
+		}
+		filter = append(filter, statusMatch)
+	}
+	fieldFilter, err := customFieldFilter(workspaceId, query.CustomFields)
+	if errors.Is(err, errInvalidCustomField) {
+		return nil, fmt.Errorf("%w: %s", errInvalidTaskQuery, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	return append(filter, fieldFilter...), nil
+}
+
+// taskSortExpr resolves a sort key to the aggregation expression used for ordering and paging.
+// A leading "-" reverses the natural order of the key.
+func taskSortExpr(sort string) (any, bool, error) {
+	reverse := strings.HasPrefix(sort, "-")
+	key := strings.TrimPrefix(sort, "-")
+	var expr any
+	descending := false
+	switch {
+	case key == "" || key == "created":
+		expr = "$created_at"
+	case key == "name":
+		expr = bson.D{{"$toLower", "$name"}}
+	case key == "priority":
+		// Urgent first
+		expr = bson.D{{"$ifNull", bson.A{"$priority_rank", 0}}}
+		descending = true
+	case key == "deadline":
+		// Soonest first, tasks without a deadline go last
+		expr = bson.D{{"$cond", bson.A{
+			bson.D{{"$gt", bson.A{bson.D{{"$ifNull", bson.A{"$deadline", 0}}}, 0}}},
+			"$deadline",
+			int64(math.MaxInt64),
+		}}}
+	case strings.HasPrefix(key, "cf."):
+		fieldId, err := bson.ObjectIDFromHex(strings.TrimPrefix(key, "cf."))
+		if err != nil {
+			return nil, false, fmt.Errorf("%w: invalid custom field id", errInvalidTaskQuery)
+		}
+		expr = "$custom_fields." + fieldId.Hex()
+	default:
+		return nil, false, fmt.Errorf("%w: field 'sort' must be one of created, name, priority, deadline, cf.<fieldId>", errInvalidTaskQuery)
+	}
+	return expr, descending != reverse, nil
+}
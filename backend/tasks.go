@@ -10,38 +10,86 @@ import (
 	"github.com/goccy/go-json"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 // @Summary 		Get all tasks
-// @Description 	Returns all tasks for a given workspace.
+// @Description 	Returns a page of tasks on a board. Filters can be combined, use next_cursor to fetch the following page.
 // @Router 			/workspaces/{workspaceId}/tasks/{boardId} [get]
 // @Tags 			Tasks
 // @Security 		BearerAuth
 // @Produce 		json
 // @Param 			workspaceId path string true "Workspace ID"
 // @Param 			boardId path string true "Board ID"
-// @Success 		200 {object} AllTasksResponse "A list of tasks"
+// @Param 			label query string false "Only return tasks with this label"
+// @Param 			assignee query string false "Only return tasks assigned to this user id, or me"
+// @Param 			q query string false "Only return tasks whose name or description contains this text"
+// @Param 			deadline_from query int false "Only return tasks with a deadline at or after this unix time"
+// @Param 			deadline_to query int false "Only return tasks with a deadline at or before this unix time"
+// @Param 			created_from query int false "Only return tasks created at or after this unix time"
+// @Param 			created_to query int false "Only return tasks created at or before this unix time"
+// @Param 			status query string false "Only return open or done tasks" Enums(open, done)
+// @Param 			due query string false "Relative deadline filter: overdue, <7d, >2w, <24h"
+// @Param 			cf query object false "Custom field filters as cf[<fieldId>]=<value>"
+// @Param 			sort query string false "Sort by created (default), name, priority (urgent first), deadline (soonest first) or cf.<fieldId>. Prefix with - to reverse"
+// @Param 			limit query int false "Page size, max 500. Without limit and cursor all items are returned"
+// @Param 			cursor query string false "Cursor from a previous page"
+// @Success 		200 {object} AllTasksResponse "A page of tasks and the estimate totals of the board"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid filter, sort key or cursor"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
 // @Failure 		500 {object} ErrorSwagger "Internal Server Error"
 func getAllTasks(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
 	bId := c.Param("boardId")
-	boardId, _ := bson.ObjectIDFromHex(bId)
-	tasks := make([]Task, 0)
-	wId := c.Param("workspaceId")
-	workspaceId, _ := bson.ObjectIDFromHex(wId)
-	cursor, _ := tasksDb.Find(context.TODO(), bson.D{
-		{"created_by", workspaceId},
-		{"board", boardId},
-	})
-	_ = cursor.All(context.TODO(), &tasks)
-	c.IndentedJSON(200, gin.H{"tasks": tasks})
-	if err := cursor.Close(context.TODO()); err != nil {
+	boardId, err := bson.ObjectIDFromHex(bId)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid boardId"})
+		return
+	}
+
+	query, err := taskQueryFromRequest(c)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	filter, err := taskQueryFilter(workspace.Id, userId.(bson.ObjectID), query)
+	if errors.Is(err, errInvalidTaskQuery) {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
 		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
 		return
 	}
+	filter = append(filter, bson.E{"board", boardId})
+	sortExpr, descending, err := taskSortExpr(query.Sort)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	page, ok := parseListRequest(c, query.Sort, sortExpr, descending)
+	if !ok {
+		return
+	}
+
+	tasks, nextCursor, err := paginate[Task](tasksDb, filter, page)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	totals, err := estimateTotals(bson.D{{"created_by", workspace.Id}, {"board", boardId}})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(200, gin.H{"tasks": tasks, "estimate": totals[boardId], "next_cursor": nextCursor})
 }
 
 // @Summary 		Create a new task
-// @Description 	Creates a new task for a workspace.
+// @Description 	Creates a new task for a workspace. With a recurrence rule the task becomes the first occurrence of a series and needs a deadline.
 // @Router 			/workspaces/{workspaceId}/tasks [post]
 // @Tags 			Tasks
 // @Security 		BearerAuth
@@ -50,7 +98,8 @@ func getAllTasks(c *gin.Context) {
 // @Param 			workspaceId path string true "Workspace ID"
 // @Param 			data body CreateTask true "Task creation data"
 // @Success 		200 {object} Task "The created task"
-// @Failure 		400 {object} ErrorSwagger "Bad request - check your input"
+// @Failure 		400 {object} ErrorSwagger "Bad request - check your input or unknown label"
+// @Failure 		404 {object} ErrorSwagger "Not Found - workspace not found"
 // @Failure 		500 {object} ErrorSwagger "Internal server error"
 func createNewTask(c *gin.Context) {
 	var input CreateTask
@@ -76,19 +125,84 @@ func createNewTask(c *gin.Context) {
 	}
 	workspaceId := c.Param("workspaceId")
 	transformedId, _ := bson.ObjectIDFromHex(workspaceId)
+	var workspace Workspace
+	if err := workspacesDb.FindOne(context.TODO(), bson.D{{"_id", transformedId}}).Decode(&workspace); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.AbortWithStatusJSON(404, gin.H{"error": "Workspace not found"})
+		} else {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		}
+		return
+	}
+	if valid, err := validateLabels(transformedId, input.Labels); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	} else if !valid {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Unknown label"})
+		return
+	}
+	if !validateEstimate(input.Estimate) {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'estimate' must be a non-negative number of points or whole minutes"})
+		return
+	}
+	userId, _ := c.Get("id")
 	newTask := Task{
 		Name:        input.Name,
 		Description: input.Description,
 		CreatedAt:   time.Now().UTC().Unix(),
 		CreatedBy:   transformedId,
 		Board:       input.Board,
+		Labels:      uniqueLabels(input.Labels),
+		Estimate:    input.Estimate,
+		Deadline:    input.Deadline,
+		// Creators follow their tasks
+		Watchers: []bson.ObjectID{userId.(bson.ObjectID)},
+	}
+	if !setTaskPriority(&newTask, input.Priority) {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'priority' must be one of urgent, high, medium, low, none"})
+		return
+	}
+	if input.Deadline != 0 && input.Deadline <= time.Now().UTC().Unix() {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Deadline cant be past current time"})
+		return
+	}
+	if input.Recurrence != nil && input.Recurrence.Rule != "" {
+		// The series is named after its first task, so the id is assigned before inserting
+		newTask.Id = bson.NewObjectID()
+		recurrence, err := newTaskRecurrence(newTask, *input.Recurrence)
+		if err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		newTask.Recurrence = recurrence
+	}
+	if err := applyCustomFields(&newTask, input.CustomFields); errors.Is(err, errInvalidCustomField) {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
 	}
+	keyPrefix, err := ensureWorkspaceKey(workspace)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	newTask.Number, err = nextTaskNumber(transformedId)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	newTask.Key = formatTaskKey(keyPrefix, newTask.Number)
 	task, err := tasksDb.InsertOne(context.TODO(), newTask)
 	if err != nil {
 		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
 		return
 	}
 	newTask.Id = task.InsertedID.(bson.ObjectID)
+	notifyMentions(workspace, newTask, "", userId.(bson.ObjectID))
+	notifyWatchers(newTask, userId.(bson.ObjectID), notificationCreated, userName(userId.(bson.ObjectID))+" created "+taskTitle(newTask))
+	publishEvent(Event{Type: eventTaskCreated, Workspace: transformedId, Actor: userId.(bson.ObjectID), Task: &newTask})
 	c.AbortWithStatusJSON(200, newTask)
 	return
 }
@@ -125,18 +239,20 @@ func authorizeTaskAccess(c *gin.Context, task Task) bool {
 }
 
 // @Summary 		Edit an existing task
-// @Description 	Edits the details of a specific task.
+// @Description 	Edits the details of a specific task. A completed_at of 0 reopens a completed task. Setting a recurrence rule makes the task the first occurrence of a series, an empty rule stops the series.
 // @Router 			/workspaces/{workspaceId}/tasks/{taskId} [patch]
 // @Tags 			Tasks
 // @Security 		BearerAuth
 // @Accept 			json
-// @Param 			taskId path string true "Task ID"
+// @Param 			taskId path string true "Task ID or key like WEB-42"
 // @Param 			workspaceId path string true "Workspace ID"
 // @Param 			data body EditTask true "Fields to edit in the task"
+// @Param 			force query bool false "Complete the task even if it is blocked by unfinished tasks"
 // @Success 		200 "Task updated successfully"
 // @Failure 		400 {object} ErrorSwagger "Bad request - invalid input or deadline in the past"
 // @Failure 		403 {object} ErrorSwagger "Forbidden - you do not have access to this task"
 // @Failure 		404 {object} ErrorSwagger "Not Found - task or workspace not found"
+// @Failure 		409 {object} BlockedTaskSwagger "Task is blocked by unfinished tasks"
 // @Failure 		500 {object} ErrorSwagger "Internal server error"
 func editExistingTask(c *gin.Context) {
 	taskInput, exists := c.Get("taskObj")
@@ -155,6 +271,8 @@ func editExistingTask(c *gin.Context) {
 		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
 		return
 	}
+	previousDescription := task.Description
+	previousBoard := task.Board
 
 	if valuesToEdit.Name != "" {
 		task.Name = valuesToEdit.Name
@@ -196,10 +314,102 @@ func editExistingTask(c *gin.Context) {
 		}
 		task.Deadline = valuesToEdit.Deadline
 	}
+	if valuesToEdit.Recurrence != nil {
+		if valuesToEdit.Recurrence.Rule == "" {
+			task.Recurrence = nil
+		} else {
+			recurrence, err := newTaskRecurrence(task, *valuesToEdit.Recurrence)
+			if err != nil {
+				c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+				return
+			}
+			task.Recurrence = recurrence
+		}
+	}
+	if valuesToEdit.Priority != "" && !setTaskPriority(&task, valuesToEdit.Priority) {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'priority' must be one of urgent, high, medium, low, none"})
+		return
+	}
+	if valuesToEdit.Estimate != nil {
+		if !validateEstimate(*valuesToEdit.Estimate) {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Field 'estimate' must be a non-negative number of points or whole minutes"})
+			return
+		}
+		task.Estimate = *valuesToEdit.Estimate
+	}
+	if err := applyCustomFields(&task, valuesToEdit.CustomFields); errors.Is(err, errInvalidCustomField) {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	if len(valuesToEdit.AddLabels) > 0 {
+		if valid, err := validateLabels(task.CreatedBy, valuesToEdit.AddLabels); err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		} else if !valid {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Unknown label"})
+			return
+		}
+		for _, labelId := range valuesToEdit.AddLabels {
+			if slices.Index(task.Labels, labelId) == -1 {
+				task.Labels = append(task.Labels, labelId)
+			}
+		}
+	}
+	if len(valuesToEdit.RemoveLabels) > 0 {
+		task.Labels = slices.DeleteFunc(task.Labels, func(labelId bson.ObjectID) bool {
+			return slices.Contains(valuesToEdit.RemoveLabels, labelId)
+		})
+	}
+	completed := false
+	if valuesToEdit.CompletedAt != nil && *valuesToEdit.CompletedAt == 0 {
+		// Sending 0 reopens a completed task
+		task.CompletedAt = 0
+	} else if valuesToEdit.CompletedAt != nil && task.CompletedAt == 0 {
+		// Completing a task before its blockers is refused unless explicitly forced
+		if c.Query("force") != "true" {
+			blockers, err := unfinishedBlockers(task)
+			if err != nil {
+				c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+				return
+			}
+			if len(blockers) > 0 {
+				c.AbortWithStatusJSON(409, gin.H{"error": "Task is blocked by unfinished tasks", "blocked_by": blockers})
+				return
+			}
+		}
+		task.CompletedAt = *valuesToEdit.CompletedAt
+		completed = true
+	}
 	if _, err := tasksDb.ReplaceOne(context.TODO(), bson.D{{Key: "_id", Value: task.Id}}, &task); err != nil {
 		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
 		return
 	}
+	userId, _ := c.Get("id")
+	if task.Description != previousDescription {
+		var workspace Workspace
+		if err := workspacesDb.FindOne(context.TODO(), bson.D{{"_id", task.CreatedBy}}).Decode(&workspace); err == nil {
+			notifyMentions(workspace, task, previousDescription, userId.(bson.ObjectID))
+		}
+	}
+	if completed {
+		notifyWatchers(task, userId.(bson.ObjectID), notificationUpdated, userName(userId.(bson.ObjectID))+" completed "+taskTitle(task))
+	} else {
+		notifyWatchers(task, userId.(bson.ObjectID), notificationUpdated, userName(userId.(bson.ObjectID))+" updated "+taskTitle(task))
+	}
+	event := Event{Type: eventTaskUpdated, Workspace: task.CreatedBy, Actor: userId.(bson.ObjectID), Task: &task}
+	if previousBoard != task.Board {
+		event.PreviousBoard = previousBoard
+	}
+	publishEvent(event)
+	if completed && task.Recurrence != nil {
+		// The scheduler retries on its next run if this fails
+		if err := spawnNextOccurrence(task.Id); err != nil {
+			println("WARNING Failed to create next occurrence of task ", task.Id.Hex(), ": ", err.Error())
+		}
+	}
 	c.AbortWithStatus(200)
 }
 
@@ -208,7 +418,7 @@ func editExistingTask(c *gin.Context) {
 // @Router 			/workspaces/{workspaceId}/tasks/{taskId} [delete]
 // @Tags 			Tasks
 // @Security 		BearerAuth
-// @Param 			taskId path string true "Task ID"
+// @Param 			taskId path string true "Task ID or key like WEB-42"
 // @Param 			workspaceId path string true "Workspace ID"
 // @Success 		200 "Task deleted successfully"
 // @Failure 		403 {object} ErrorSwagger "Forbidden - you do not have access to this task"
@@ -230,5 +440,92 @@ func deleteExistingTask(c *gin.Context) {
 		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to delete task"})
 		return
 	}
+	if _, err := tasksDb.UpdateMany(context.TODO(), bson.D{{"links.task", task.Id}}, bson.D{{"$pull", bson.D{{"links", bson.D{{"task", task.Id}}}}}}); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to remove links to task"})
+		return
+	}
+	userId, _ := c.Get("id")
+	notifyWatchers(task, userId.(bson.ObjectID), notificationDeleted, userName(userId.(bson.ObjectID))+" deleted "+taskTitle(task))
+	publishEvent(Event{Type: eventTaskDeleted, Workspace: task.CreatedBy, Actor: userId.(bson.ObjectID), Task: &task})
 	c.AbortWithStatus(200)
 }
+
+func changeAssignment(c *gin.Context, operator string) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	var input AssignTask
+	if err := json.NewDecoder(c.Request.Body).Decode(&input); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return
+	}
+	if operator == "$addToSet" && !workspaceMembers(workspace)[input.UserId] {
+		c.AbortWithStatusJSON(400, gin.H{"error": "User is not part of this workspace"})
+		return
+	}
+	// Members can only (un)assign themselves, the owner can (un)assign anyone
+	if workspace.OwnedBy != userId.(bson.ObjectID) && input.UserId != userId.(bson.ObjectID) {
+		c.AbortWithStatusJSON(403, gin.H{"error": "Only the workspace owner can assign other members"})
+		return
+	}
+	update := bson.D{{operator, bson.D{{"assignees", input.UserId}}}}
+	if operator == "$addToSet" {
+		// Assignees follow the task, unassigning keeps them watching until they unwatch
+		update = bson.D{{operator, bson.D{{"assignees", input.UserId}, {"watchers", input.UserId}}}}
+	}
+	var task Task
+	err := tasksDb.FindOneAndUpdate(context.TODO(),
+		bson.D{{"_id", input.TaskId}, {"created_by", workspace.Id}},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&task)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Task not found"})
+		return
+	} else if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	if operator == "$addToSet" && input.UserId != userId.(bson.ObjectID) {
+		notifyInBackground(input.UserId, workspace.Id, task.Id, userId.(bson.ObjectID), notificationAssigned, userName(userId.(bson.ObjectID))+" assigned you to "+taskTitle(task))
+	}
+	publishEvent(Event{Type: eventTaskUpdated, Workspace: workspace.Id, Actor: userId.(bson.ObjectID), Task: &task})
+	c.JSON(200, task)
+}
+
+// @Summary 		Assign a task to a user
+// @Description 	Assigns a task within a workspace to a specific user, who then watches the task and gets an assigned notification unless they assigned themselves. Members can assign themselves, the owner can assign anyone.
+// @Router 			/workspaces/{workspaceId}/assign [post]
+// @Tags 			Tasks
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			data body AssignTask true "Task and User IDs"
+// @Success 		200 {object} Task "The updated task"
+// @Failure 		400 {object} ErrorSwagger "Bad request - user is not part of this workspace"
+// @Failure 		403 {object} ErrorSwagger "Forbidden - you are not the owner or the user to be assigned"
+// @Failure 		404 {object} ErrorSwagger "Not Found - workspace or task not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func assignTask(c *gin.Context) {
+	changeAssignment(c, "$addToSet")
+}
+
+// @Summary 		Unassign a user from a task
+// @Description 	Removes a user from the assignees of a task. Members can unassign themselves, the owner can unassign anyone.
+// @Router 			/workspaces/{workspaceId}/unassign [post]
+// @Tags 			Tasks
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			data body AssignTask true "Task and User IDs"
+// @Success 		200 {object} Task "The updated task"
+// @Failure 		403 {object} ErrorSwagger "Forbidden - you are not the owner or the user to be unassigned"
+// @Failure 		404 {object} ErrorSwagger "Not Found - workspace or task not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func unassignTask(c *gin.Context) {
+	changeAssignment(c, "$pull")
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestChangeAssignment(t *testing.T) {
+	useTestDatabase(t)
+	gin.SetMode(gin.TestMode)
+
+	owner := User{Id: bson.NewObjectID(), Name: "Ada"}
+	member := User{Id: bson.NewObjectID(), Name: "Bob"}
+	other := User{Id: bson.NewObjectID(), Name: "Cy"}
+	if _, err := usersDb.InsertMany(context.TODO(), []any{owner, member, other}); err != nil {
+		t.Fatal(err)
+	}
+	workspace := Workspace{Id: bson.NewObjectID(), Name: "Web", OwnedBy: owner.Id, Members: []bson.ObjectID{member.Id, other.Id}}
+	if _, err := workspacesDb.InsertOne(context.TODO(), workspace); err != nil {
+		t.Fatal(err)
+	}
+	task := Task{Id: bson.NewObjectID(), Key: "WEB-1", Name: "Ship it", CreatedBy: workspace.Id}
+	if _, err := tasksDb.InsertOne(context.TODO(), task); err != nil {
+		t.Fatal(err)
+	}
+
+	r := gin.New()
+	as := func(c *gin.Context) {
+		id, _ := bson.ObjectIDFromHex(c.GetHeader("X-User"))
+		c.Set("id", id)
+	}
+	r.POST("/workspaces/:workspaceId/assign", as, assignTask)
+	r.POST("/workspaces/:workspaceId/unassign", as, unassignTask)
+	request := func(path string, actor, userId bson.ObjectID) *httptest.ResponseRecorder {
+		body := `{"taskId":"` + task.Id.Hex() + `","userId":"` + userId.Hex() + `"}`
+		req := httptest.NewRequest("POST", "/workspaces/"+workspace.Id.Hex()+path, strings.NewReader(body))
+		req.Header.Set("X-User", actor.Hex())
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+		return recorder
+	}
+	stored := func(t *testing.T) Task {
+		t.Helper()
+		var stored Task
+		if err := tasksDb.FindOne(context.TODO(), bson.D{{"_id", task.Id}}).Decode(&stored); err != nil {
+			t.Fatal(err)
+		}
+		return stored
+	}
+	assignedNotification := func(userId bson.ObjectID) bool {
+		// The notification is stored in the background
+		for range 20 {
+			count, err := notificationsDb.CountDocuments(context.TODO(), bson.D{{"user", userId}, {"type", notificationAssigned}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count > 0 {
+				return true
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		return false
+	}
+
+	t.Run("owner assigns a member", func(t *testing.T) {
+		if recorder := request("/assign", owner.Id, member.Id); recorder.Code != 200 {
+			t.Fatalf("status = %d: %s", recorder.Code, recorder.Body.String())
+		}
+		task := stored(t)
+		if !slices.Contains(task.Assignees, member.Id) || !slices.Contains(task.Watchers, member.Id) {
+			t.Errorf("task = %+v, want %s assigned and watching", task, member.Id.Hex())
+		}
+		if !assignedNotification(member.Id) {
+			t.Error("no assigned notification for the member")
+		}
+	})
+
+	t.Run("owner can be assigned", func(t *testing.T) {
+		if recorder := request("/assign", owner.Id, owner.Id); recorder.Code != 200 {
+			t.Fatalf("status = %d: %s", recorder.Code, recorder.Body.String())
+		}
+		if task := stored(t); !slices.Contains(task.Assignees, owner.Id) {
+			t.Errorf("assignees = %v, want the owner", task.Assignees)
+		}
+	})
+
+	t.Run("unassigning keeps watching", func(t *testing.T) {
+		if recorder := request("/unassign", member.Id, member.Id); recorder.Code != 200 {
+			t.Fatalf("status = %d: %s", recorder.Code, recorder.Body.String())
+		}
+		task := stored(t)
+		if slices.Contains(task.Assignees, member.Id) || !slices.Contains(task.Watchers, member.Id) {
+			t.Errorf("task = %+v, want %s unassigned but watching", task, member.Id.Hex())
+		}
+	})
+
+	t.Run("member cannot assign someone else", func(t *testing.T) {
+		if recorder := request("/assign", member.Id, other.Id); recorder.Code != 403 {
+			t.Errorf("status = %d, want 403", recorder.Code)
+		}
+	})
+
+	t.Run("non-member cannot be assigned", func(t *testing.T) {
+		if recorder := request("/assign", owner.Id, bson.NewObjectID()); recorder.Code != 400 {
+			t.Errorf("status = %d, want 400", recorder.Code)
+		}
+	})
+}
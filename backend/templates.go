@@ -0,0 +1,518 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Templates are single documents, seed tasks have to fit in one
+const maxTemplateTasks = 500
+
+var errTemplateTooLarge = errors.New("template too large")
+
+// templateTask strips what only makes sense for the task a seed task was copied from: its key,
+// dates, people, recurrence and links to tasks that aren't part of the template.
+func templateTask(task Task, taskIds map[bson.ObjectID]bool, fields map[string]CustomField) Task {
+	seed := Task{
+		Id:           task.Id,
+		Name:         task.Name,
+		Description:  task.Description,
+		Board:        task.Board,
+		Labels:       task.Labels,
+		Priority:     task.Priority,
+		PriorityRank: task.PriorityRank,
+		Estimate:     task.Estimate,
+	}
+	for _, link := range task.Links {
+		if taskIds[link.Task] {
+			seed.Links = append(seed.Links, link)
+		}
+	}
+	for fieldId, value := range task.CustomFields {
+		field, ok := fields[fieldId]
+		if !ok || field.Type == fieldUser || field.Type == fieldDate {
+			continue
+		}
+		if seed.CustomFields == nil {
+			seed.CustomFields = map[string]any{}
+		}
+		seed.CustomFields[fieldId] = value
+	}
+	return seed
+}
+
+// newWorkspaceTemplate reads the boards, labels and custom fields of a workspace and, if asked to,
+// its open tasks as seed tasks.
+func newWorkspaceTemplate(workspace Workspace, includeTasks bool) (WorkspaceTemplate, error) {
+	template := WorkspaceTemplate{Source: workspace.Id, CustomFields: make([]CustomField, 0), Tasks: make([]Task, 0)}
+	var err error
+	if template.Boards, err = workspaceBoards(workspace.Id); err != nil {
+		return template, err
+	}
+	for i := range template.Boards {
+		template.Boards[i].OwnedBy = bson.ObjectID{}
+		template.Boards[i].Watchers = nil
+	}
+	if template.Labels, err = workspaceLabels(workspace.Id); err != nil {
+		return template, err
+	}
+	for i := range template.Labels {
+		template.Labels[i].Workspace = bson.ObjectID{}
+	}
+	fields, err := loadCustomFields(workspace.Id)
+	if err != nil {
+		return template, err
+	}
+	for _, field := range fields {
+		field.Workspace = bson.ObjectID{}
+		template.CustomFields = append(template.CustomFields, field)
+	}
+	slices.SortFunc(template.CustomFields, func(a, b CustomField) int { return strings.Compare(a.Id.Hex(), b.Id.Hex()) })
+	if !includeTasks {
+		return template, nil
+	}
+
+	cursor, err := tasksDb.Find(context.TODO(), bson.D{{"created_by", workspace.Id}, {"completed_at", bson.D{{"$in", bson.A{0, nil}}}}},
+		options.Find().SetSort(bson.D{{"number", 1}, {"_id", 1}}).SetLimit(maxTemplateTasks+1))
+	if err != nil {
+		return template, err
+	}
+	tasks := make([]Task, 0)
+	if err := cursor.All(context.TODO(), &tasks); err != nil {
+		return template, err
+	}
+	if len(tasks) > maxTemplateTasks {
+		return template, fmt.Errorf("%w: templates can hold at most %d seed tasks", errTemplateTooLarge, maxTemplateTasks)
+	}
+	taskIds := map[bson.ObjectID]bool{}
+	for _, task := range tasks {
+		taskIds[task.Id] = true
+	}
+	for _, task := range tasks {
+		template.Tasks = append(template.Tasks, templateTask(task, taskIds, fields))
+	}
+	return template, nil
+}
+
+// copyWorkspaceStructure inserts labels, custom fields and boards into a workspace under the ids
+// restore maps them to, so tasks copied afterwards can refer to them.
+func copyWorkspaceStructure(restore *backupRestore, workspace Workspace, labels []Label, fields []CustomField, boards []Board) error {
+	if len(labels) != 0 {
+		copied := make([]Label, 0, len(labels))
+		for _, label := range labels {
+			label.Id = restore.ids.get(label.Id)
+			label.Workspace = workspace.Id
+			copied = append(copied, label)
+		}
+		if _, err := labelsDb.InsertMany(context.TODO(), copied); err != nil {
+			return err
+		}
+	}
+	if len(fields) != 0 {
+		copied := make([]CustomField, 0, len(fields))
+		for _, field := range fields {
+			restore.fields[field.Id.Hex()] = field.Type
+			field.Id = restore.ids.get(field.Id)
+			field.Workspace = workspace.Id
+			copied = append(copied, field)
+		}
+		if _, err := customFieldsDb.InsertMany(context.TODO(), copied); err != nil {
+			return err
+		}
+	}
+	if len(boards) != 0 {
+		copied := make([]Board, 0, len(boards))
+		for _, board := range boards {
+			board.Id = restore.ids.get(board.Id)
+			board.OwnedBy = workspace.Id
+			board.Watchers = restore.userList(board.Watchers)
+			copied = append(copied, board)
+		}
+		if _, err := boardsDb.InsertMany(context.TODO(), copied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyWorkspaceTemplate creates the boards, labels, custom fields and seed tasks of a template in a
+// new workspace. Seed tasks get the next keys of the workspace.
+func applyWorkspaceTemplate(template WorkspaceTemplate, workspace Workspace) error {
+	restore := &backupRestore{
+		ids:    idMap{},
+		users:  map[bson.ObjectID]bson.ObjectID{},
+		fields: map[string]string{},
+	}
+	if err := copyWorkspaceStructure(restore, workspace, template.Labels, template.CustomFields, template.Boards); err != nil {
+		return err
+	}
+	if len(template.Tasks) == 0 {
+		return nil
+	}
+	first, err := reserveTaskNumbers(workspace.Id, int64(len(template.Tasks)))
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC().Unix()
+	tasks := make([]Task, 0, len(template.Tasks))
+	for i, task := range template.Tasks {
+		task = restore.task(task, workspace.Id)
+		task.Number = first + int64(i)
+		task.Key = formatTaskKey(workspace.Key, task.Number)
+		task.CreatedAt = now
+		tasks = append(tasks, task)
+	}
+	_, err = tasksDb.InsertMany(context.TODO(), tasks)
+	return err
+}
+
+// discardWorkspace removes a workspace that could not be set up completely, with everything created in it.
+func discardWorkspace(workspaceId bson.ObjectID) error {
+	return errors.Join(
+		deleteAll(tasksDb, bson.D{{"created_by", workspaceId}}),
+		deleteAll(boardsDb, bson.D{{"owned_by", workspaceId}}),
+		deleteAll(labelsDb, bson.D{{"workspace", workspaceId}}),
+		deleteAll(customFieldsDb, bson.D{{"workspace", workspaceId}}),
+		deleteAll(viewsDb, bson.D{{"workspace", workspaceId}}),
+		deleteAll(countersDb, bson.D{{"_id", workspaceId}}),
+		deleteAll(workspacesDb, bson.D{{"_id", workspaceId}}),
+	)
+}
+
+func deleteAll(collection *mongo.Collection, filter bson.D) error {
+	_, err := collection.DeleteMany(context.TODO(), filter)
+	return err
+}
+
+// duplicateWorkspace deep-copies a workspace with its boards, tasks, labels, custom fields and views
+// into a new workspace owned by actor. Members stay the same, so assignments carry over. If copying
+// fails the partial copy is removed again.
+func duplicateWorkspace(source Workspace, name string, actor bson.ObjectID, runner *jobRunner) (err error) {
+	total, err := tasksDb.CountDocuments(context.TODO(), bson.D{{"created_by", source.Id}})
+	if err != nil {
+		return err
+	}
+	runner.setTotal(int(total))
+	restore := &backupRestore{
+		ids:    idMap{},
+		users:  map[bson.ObjectID]bson.ObjectID{source.OwnedBy: source.OwnedBy},
+		fields: map[string]string{},
+	}
+	members := []bson.ObjectID{actor}
+	for _, member := range source.Members {
+		restore.users[member] = member
+		if !slices.Contains(members, member) {
+			members = append(members, member)
+		}
+	}
+
+	workspace := Workspace{
+		Name:    name,
+		Key:     source.Key,
+		Avatar:  source.Avatar,
+		OwnedBy: actor,
+		Members: members,
+	}
+	if workspace.Name == "" {
+		workspace.Name = source.Name + " (copy)"
+	}
+	if workspace.Key == "" {
+		workspace.Key = deriveWorkspaceKey(workspace.Name)
+	}
+	result, err := workspacesDb.InsertOne(context.TODO(), workspace)
+	if err != nil {
+		return err
+	}
+	workspace.Id = result.InsertedID.(bson.ObjectID)
+	runner.setWorkspace(workspace.Id)
+	defer func() {
+		if err == nil {
+			return
+		}
+		if discardErr := discardWorkspace(workspace.Id); discardErr != nil {
+			println("WARNING Failed to remove partial copy ", workspace.Id.Hex(), ": ", discardErr.Error())
+			return
+		}
+		runner.setWorkspace(source.Id)
+	}()
+
+	labels, err := workspaceLabels(source.Id)
+	if err != nil {
+		return err
+	}
+	fieldsById, err := loadCustomFields(source.Id)
+	if err != nil {
+		return err
+	}
+	fields := make([]CustomField, 0, len(fieldsById))
+	for _, field := range fieldsById {
+		fields = append(fields, field)
+	}
+	boards, err := workspaceBoards(source.Id)
+	if err != nil {
+		return err
+	}
+	if err := copyWorkspaceStructure(restore, workspace, labels, fields, boards); err != nil {
+		return err
+	}
+
+	copied, lastNumber, err := copyTasks(restore, bson.D{{"created_by", source.Id}}, workspace, false, runner)
+	if err != nil {
+		return err
+	}
+	// New tasks continue after the highest copied key
+	if _, err := countersDb.UpdateOne(context.TODO(), bson.D{{"_id", workspace.Id}}, bson.D{{"$max", bson.D{{"seq", lastNumber}}}}, options.UpdateOne().SetUpsert(true)); err != nil {
+		return err
+	}
+
+	cursor, err := viewsDb.Find(context.TODO(), bson.D{{"workspace", source.Id}})
+	if err != nil {
+		return err
+	}
+	sourceViews := make([]SavedView, 0)
+	if err := cursor.All(context.TODO(), &sourceViews); err != nil {
+		return err
+	}
+	views := make([]SavedView, 0, len(sourceViews))
+	for _, view := range sourceViews {
+		if view, ok := restore.view(view, workspace.Id, actor); ok {
+			views = append(views, view)
+		}
+	}
+	if len(views) != 0 {
+		if _, err := viewsDb.InsertMany(context.TODO(), views); err != nil {
+			return err
+		}
+	}
+
+	runner.setResult("boards", len(boards))
+	runner.setResult("labels", len(labels))
+	runner.setResult("custom_fields", len(fields))
+	runner.setResult("views", len(views))
+	runner.setResult("tasks", copied)
+	return nil
+}
+
+// copyTasks copies the tasks matched by filter into a workspace in batches, with the ids restore maps
+// them to. Copies within a workspace need renumbering as keys are unique. Copies are new resources for
+// CalDAV clients, so they don't keep the UIDs clients gave.
+func copyTasks(restore *backupRestore, filter bson.D, workspace Workspace, renumber bool, runner *jobRunner) (int, int64, error) {
+	cursor, err := tasksDb.Find(context.TODO(), filter, options.Find().SetSort(bson.D{{"_id", 1}}))
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(context.TODO())
+	copied := 0
+	var lastNumber int64
+	batch := make([]Task, 0, importBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if renumber {
+			first, err := reserveTaskNumbers(workspace.Id, int64(len(batch)))
+			if err != nil {
+				return err
+			}
+			for i := range batch {
+				batch[i].Number = first + int64(i)
+				batch[i].Key = formatTaskKey(workspace.Key, batch[i].Number)
+				lastNumber = batch[i].Number
+			}
+		}
+		if _, err := tasksDb.InsertMany(context.TODO(), batch); err != nil {
+			return err
+		}
+		copied += len(batch)
+		if runner != nil {
+			runner.advance(len(batch))
+		}
+		batch = batch[:0]
+		return nil
+	}
+	for cursor.Next(context.TODO()) {
+		var task Task
+		if err := cursor.Decode(&task); err != nil {
+			return copied, lastNumber, err
+		}
+		task = restore.task(task, workspace.Id)
+		if renumber {
+			// External ids are unique in a workspace too
+			task.ExternalId = ""
+		}
+		task.IcalUid = ""
+		task.IcalName = ""
+		lastNumber = max(lastNumber, task.Number)
+		batch = append(batch, task)
+		if len(batch) == importBatchSize {
+			if err := flush(); err != nil {
+				return copied, lastNumber, err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return copied, lastNumber, err
+	}
+	return copied, lastNumber, flush()
+}
+
+func findWorkspaceTemplate(c *gin.Context) (WorkspaceTemplate, bool) {
+	userId, _ := c.Get("id")
+	var template WorkspaceTemplate
+	templateId, err := bson.ObjectIDFromHex(c.Param("templateId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid templateId"})
+		return template, false
+	}
+	err = workspaceTemplatesDb.FindOne(context.TODO(), bson.D{{"_id", templateId}, {"created_by", userId}}).Decode(&template)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Template does not exist"})
+		return template, false
+	} else if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return template, false
+	}
+	return template, true
+}
+
+// @Summary 		Get your workspace templates
+// @Description 	Returns the workspace templates you saved, including their boards, labels, custom fields and seed tasks.
+// @Router 			/workspaces/templates [get]
+// @Tags 			Workspaces
+// @Security 		BearerAuth
+// @Produce 		json
+// @Success 		200 {object} AllWorkspaceTemplatesResponse "A list of templates"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func getAllWorkspaceTemplates(c *gin.Context) {
+	userId, _ := c.Get("id")
+	cursor, err := workspaceTemplatesDb.Find(context.TODO(), bson.D{{"created_by", userId}}, options.Find().SetSort(bson.D{{"created_at", -1}}))
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	templates := make([]WorkspaceTemplate, 0)
+	if err := cursor.All(context.TODO(), &templates); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(200, gin.H{"templates": templates})
+}
+
+// @Summary 		Save a workspace as template
+// @Description 	Saves the boards, labels and custom fields of a workspace as a template to create new workspaces from. With include_tasks the open tasks become seed tasks, without their assignees, watchers, dates and recurrence. Only the owner can save a workspace as template.
+// @Router 			/workspaces/{workspaceId}/template [post]
+// @Tags 			Workspaces
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			data body CreateWorkspaceTemplate true "Name of the template and whether to include tasks"
+// @Success 		200 {object} WorkspaceTemplate "The saved template"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid JSON or too many seed tasks"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not the owner of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - workspace not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func createWorkspaceTemplate(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceOwner(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	var input CreateWorkspaceTemplate
+	if err := json.NewDecoder(c.Request.Body).Decode(&input); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Failed to parse request"})
+		return
+	}
+	template, err := newWorkspaceTemplate(workspace, input.IncludeTasks)
+	if errors.Is(err, errTemplateTooLarge) {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	template.Name = strings.TrimSpace(input.Name)
+	if template.Name == "" {
+		template.Name = workspace.Name
+	}
+	template.Description = input.Description
+	template.CreatedBy = userId.(bson.ObjectID)
+	template.CreatedAt = time.Now().UTC().Unix()
+	result, err := workspaceTemplatesDb.InsertOne(context.TODO(), template)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to save template"})
+		return
+	}
+	template.Id = result.InsertedID.(bson.ObjectID)
+	c.JSON(200, template)
+}
+
+// @Summary 		Delete a workspace template
+// @Description 	Deletes one of your workspace templates. Workspaces created from it are not affected.
+// @Router 			/workspaces/templates/{templateId} [delete]
+// @Tags 			Workspaces
+// @Security 		BearerAuth
+// @Param 			templateId path string true "Template ID"
+// @Success 		200 "Template deleted"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid id"
+// @Failure 		404 {object} ErrorSwagger "Not Found - template not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func deleteWorkspaceTemplate(c *gin.Context) {
+	template, ok := findWorkspaceTemplate(c)
+	if !ok {
+		return
+	}
+	if _, err := workspaceTemplatesDb.DeleteOne(context.TODO(), bson.D{{"_id", template.Id}}); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to delete template"})
+		return
+	}
+	c.AbortWithStatus(200)
+}
+
+// @Summary 		Duplicate a workspace
+// @Description 	Starts a background job copying a workspace with its boards, tasks, labels, custom fields and views into a new workspace you own. All documents get new ids, tasks keep their keys and the members of the workspace become members of the copy. A copy that fails halfway is removed again. Only the owner can duplicate a workspace.
+// @Router 			/workspaces/{workspaceId}/duplicate [post]
+// @Tags 			Workspaces
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			data body DuplicateWorkspace false "Name of the copy instead of the original name with (copy)"
+// @Success 		202 {object} Job "The started duplicate job"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid JSON"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not the owner of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - workspace not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func duplicateWorkspaceJob(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceOwner(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	var input DuplicateWorkspace
+	if c.Request.ContentLength != 0 {
+		if err := json.NewDecoder(c.Request.Body).Decode(&input); err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Failed to parse request"})
+			return
+		}
+	}
+	name := strings.TrimSpace(input.Name)
+	job, err := startJob(Job{Type: "duplicate", Workspace: workspace.Id, CreatedBy: userId.(bson.ObjectID)}, func(runner *jobRunner) error {
+		return duplicateWorkspace(workspace, name, userId.(bson.ObjectID), runner)
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to start duplicating"})
+		return
+	}
+	c.JSON(202, job)
+}
@@ -0,0 +1,436 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	// Trello exports only contain the latest actions, so older comments are missing
+	trelloActionsLimit = 1000
+	trelloListsAsField = "field"
+	trelloListField    = "List"
+)
+
+// Trello label colors, the _dark and _light variants use the base color.
+var trelloColors = map[string]string{
+	"green":  "#61bd4f",
+	"yellow": "#f2d600",
+	"orange": "#ff9f1a",
+	"red":    "#eb5a46",
+	"purple": "#c377e0",
+	"blue":   "#0079bf",
+	"sky":    "#00c2e0",
+	"lime":   "#51e898",
+	"pink":   "#ff78cb",
+	"black":  "#344563",
+}
+
+type trelloExport struct {
+	Name       string            `json:"name"`
+	Lists      []trelloList      `json:"lists"`
+	Cards      []trelloCard      `json:"cards"`
+	Labels     []trelloLabel     `json:"labels"`
+	Checklists []trelloChecklist `json:"checklists"`
+	Members    []trelloMember    `json:"members"`
+	Actions    []trelloAction    `json:"actions"`
+}
+
+type trelloList struct {
+	Id     string `json:"id"`
+	Name   string `json:"name"`
+	Closed bool   `json:"closed"`
+}
+
+type trelloCard struct {
+	Id               string            `json:"id"`
+	Name             string            `json:"name"`
+	Desc             string            `json:"desc"`
+	Closed           bool              `json:"closed"`
+	Due              string            `json:"due"`
+	DueComplete      bool              `json:"dueComplete"`
+	DateLastActivity string            `json:"dateLastActivity"`
+	IdList           string            `json:"idList"`
+	IdLabels         []string          `json:"idLabels"`
+	IdMembers        []string          `json:"idMembers"`
+	ShortUrl         string            `json:"shortUrl"`
+	Attachments      []json.RawMessage `json:"attachments"`
+	CustomFieldItems []json.RawMessage `json:"customFieldItems"`
+}
+
+type trelloLabel struct {
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type trelloChecklist struct {
+	Id         string `json:"id"`
+	IdCard     string `json:"idCard"`
+	Name       string `json:"name"`
+	CheckItems []struct {
+		Name  string `json:"name"`
+		State string `json:"state"`
+	} `json:"checkItems"`
+}
+
+type trelloMember struct {
+	Id       string `json:"id"`
+	FullName string `json:"fullName"`
+	Username string `json:"username"`
+}
+
+type trelloAction struct {
+	Type string `json:"type"`
+	Date string `json:"date"`
+	Data struct {
+		Text string `json:"text"`
+		Card struct {
+			Id string `json:"id"`
+		} `json:"card"`
+	} `json:"data"`
+	MemberCreator struct {
+		FullName string `json:"fullName"`
+	} `json:"memberCreator"`
+}
+
+func parseTrelloTime(value string) int64 {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0
+	}
+	return parsed.Unix()
+}
+
+// listField returns the single select custom field lists are mapped to, adding missing options.
+func (t *importTarget) listField(options []string) (CustomField, error) {
+	var field CustomField
+	err := customFieldsDb.FindOne(context.TODO(), bson.D{{"workspace", t.workspace.Id}, {"name", trelloListField}}).Decode(&field)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		field = CustomField{Name: trelloListField, Type: fieldSingleSelect, Options: options, Workspace: t.workspace.Id}
+		result, err := customFieldsDb.InsertOne(context.TODO(), field)
+		if err != nil {
+			return field, err
+		}
+		field.Id = result.InsertedID.(bson.ObjectID)
+		return field, nil
+	} else if err != nil {
+		return field, err
+	}
+	if field.Type != fieldSingleSelect {
+		return field, fmt.Errorf("the custom field '%s' exists but is not a single select", trelloListField)
+	}
+	_, err = customFieldsDb.UpdateOne(context.TODO(), bson.D{{"_id", field.Id}}, bson.D{{"$addToSet", bson.D{{"options", bson.D{{"$each", options}}}}}})
+	return field, err
+}
+
+// trelloDescription appends checklists and comments, which Rela has no place for, to the card description.
+func trelloDescription(card trelloCard, checklists []trelloChecklist, comments []trelloAction) string {
+	sections := make([]string, 0)
+	if card.Desc != "" {
+		sections = append(sections, card.Desc)
+	}
+	for _, checklist := range checklists {
+		var section strings.Builder
+		fmt.Fprintf(&section, "### %s\n", checklist.Name)
+		for _, item := range checklist.CheckItems {
+			mark := " "
+			if item.State == "complete" {
+				mark = "x"
+			}
+			fmt.Fprintf(&section, "\n- [%s] %s", mark, item.Name)
+		}
+		sections = append(sections, section.String())
+	}
+	imported := make([]importedComment, 0, len(comments))
+	for _, comment := range comments {
+		imported = append(imported, importedComment{Author: comment.MemberCreator.FullName, Date: parseTrelloTime(comment.Date), Text: comment.Data.Text})
+	}
+	return importedDescription(sections, imported, card.ShortUrl)
+}
+
+// importTrello maps a Trello board export onto the workspace of the target.
+func importTrello(export trelloExport, target *importTarget, listsAsField bool, runner *jobRunner) error {
+	cards := make([]trelloCard, 0, len(export.Cards))
+	lists := map[string]trelloList{}
+	for _, list := range export.Lists {
+		lists[list.Id] = list
+	}
+	archived, archivedLists := 0, 0
+	for _, card := range export.Cards {
+		if card.Closed {
+			archived++
+		} else if lists[card.IdList].Closed {
+			archivedLists++
+		} else {
+			cards = append(cards, card)
+		}
+	}
+	if archived != 0 {
+		runner.warn("%d archived cards were not imported", archived)
+	}
+	if archivedLists != 0 {
+		runner.warn("%d cards on archived lists were not imported", archivedLists)
+	}
+	runner.setTotal(len(cards))
+
+	// Lists become boards, or values of the List field on a single board
+	boards := map[string]bson.ObjectID{}
+	var listField CustomField
+	createdBoards := 0
+	if listsAsField {
+		board, created, err := target.board(export.Name)
+		if err != nil {
+			return err
+		}
+		if created {
+			createdBoards++
+		}
+		names := make([]string, 0)
+		for _, list := range export.Lists {
+			if !list.Closed && !slices.Contains(names, list.Name) {
+				names = append(names, list.Name)
+			}
+			boards[list.Id] = board.Id
+		}
+		if listField, err = target.listField(names); err != nil {
+			return err
+		}
+	} else {
+		for _, list := range export.Lists {
+			if list.Closed {
+				continue
+			}
+			board, created, err := target.board(list.Name)
+			if err != nil {
+				return err
+			}
+			if created {
+				createdBoards++
+			}
+			boards[list.Id] = board.Id
+		}
+	}
+
+	// Labels without a name in Trello are only a color, which becomes the name
+	labels := map[string]bson.ObjectID{}
+	createdLabels := 0
+	for _, label := range export.Labels {
+		base := strings.TrimSuffix(strings.TrimSuffix(label.Color, "_dark"), "_light")
+		name := label.Name
+		if name == "" {
+			if base == "" {
+				runner.warn("A label without name and color was not imported")
+				continue
+			}
+			name = strings.ToUpper(base[:1]) + base[1:]
+		}
+		labelId, created, err := target.label(name, trelloColors[base])
+		if err != nil {
+			return err
+		}
+		if created {
+			createdLabels++
+		}
+		labels[label.Id] = labelId
+	}
+
+	members := map[string]bson.ObjectID{}
+	for _, member := range export.Members {
+		if userId, ok := target.userByName(member.FullName, member.Username); ok {
+			members[member.Id] = userId
+		} else {
+			runner.warn("Trello member %s (@%s) is not a member of this workspace, their cards are unassigned", member.FullName, member.Username)
+		}
+	}
+
+	checklists := map[string][]trelloChecklist{}
+	checklistCount := 0
+	for _, checklist := range export.Checklists {
+		checklists[checklist.IdCard] = append(checklists[checklist.IdCard], checklist)
+		checklistCount++
+	}
+	comments := map[string][]trelloAction{}
+	commentCount := 0
+	for _, action := range export.Actions {
+		if action.Type == "commentCard" {
+			comments[action.Data.Card.Id] = append(comments[action.Data.Card.Id], action)
+			commentCount++
+		}
+	}
+	for cardId := range comments {
+		sort.SliceStable(comments[cardId], func(i, j int) bool { return comments[cardId][i].Date < comments[cardId][j].Date })
+	}
+	if len(export.Actions) >= trelloActionsLimit {
+		runner.warn("The export contains only the latest %d actions, older comments are missing", trelloActionsLimit)
+	}
+	runner.setResult("boards_created", createdBoards)
+	runner.setResult("labels_created", createdLabels)
+
+	attachments, customFields := 0, 0
+	now := time.Now().UTC().Unix()
+	tasks := make([]Task, 0, len(cards))
+	for _, card := range cards {
+		if boards[card.IdList].IsZero() {
+			runner.warn("Card '%s' is on a list missing from the export and was not imported", card.Name)
+			runner.advance(1)
+			continue
+		}
+		task := Task{
+			Name:        card.Name,
+			Description: trelloDescription(card, checklists[card.Id], comments[card.Id]),
+			CreatedAt:   now,
+			Board:       boards[card.IdList],
+			Deadline:    parseTrelloTime(card.Due),
+			ExternalId:  "trello:" + card.Id,
+		}
+		// Trello ids start with the creation time, like ObjectIDs
+		if cardId, err := bson.ObjectIDFromHex(card.Id); err == nil {
+			task.CreatedAt = cardId.Timestamp().Unix()
+		}
+		if card.DueComplete {
+			task.CompletedAt = parseTrelloTime(card.DateLastActivity)
+			if task.CompletedAt == 0 {
+				task.CompletedAt = now
+			}
+		}
+		for _, labelId := range card.IdLabels {
+			if id, ok := labels[labelId]; ok && !slices.Contains(task.Labels, id) {
+				task.Labels = append(task.Labels, id)
+			}
+		}
+		for _, memberId := range card.IdMembers {
+			if userId, ok := members[memberId]; ok && !slices.Contains(task.Assignees, userId) {
+				task.Assignees = append(task.Assignees, userId)
+			}
+		}
+		if listsAsField {
+			task.CustomFields = map[string]any{listField.Id.Hex(): lists[card.IdList].Name}
+		}
+		attachments += len(card.Attachments)
+		customFields += len(card.CustomFieldItems)
+		tasks = append(tasks, task)
+	}
+	if attachments != 0 {
+		runner.warn("%d attachments were not imported", attachments)
+	}
+	if customFields != 0 {
+		runner.warn("%d Trello custom field values were not imported", customFields)
+	}
+	created, skipped, err := target.insertTasks(tasks, runner)
+	if err != nil {
+		return err
+	}
+	runner.setResult("tasks_created", created)
+	runner.setResult("tasks_skipped", skipped)
+	runner.setResult("checklists", checklistCount)
+	runner.setResult("comments", commentCount)
+	return nil
+}
+
+// startTrelloImport parses the upload and starts the import job. A zero workspace id creates a
+// new workspace named after the Trello board.
+func startTrelloImport(c *gin.Context, workspace *Workspace) {
+	userId, _ := c.Get("id")
+	data, ok := readImportFile(c)
+	if !ok {
+		return
+	}
+	var export trelloExport
+	if err := json.Unmarshal(data, &export); err != nil || (export.Name == "" && len(export.Lists) == 0) {
+		c.AbortWithStatusJSON(400, gin.H{"error": "The file is not a Trello board export"})
+		return
+	}
+	listsAsField := c.Query("lists") == trelloListsAsField
+	if lists := c.Query("lists"); lists != "" && lists != "boards" && !listsAsField {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'lists' must be boards or field"})
+		return
+	}
+	if export.Name == "" {
+		export.Name = "Trello"
+	}
+
+	job := Job{Type: "import.trello", CreatedBy: userId.(bson.ObjectID)}
+	if workspace != nil {
+		job.Workspace = workspace.Id
+	}
+	job, err := startJob(job, func(runner *jobRunner) error {
+		target := workspace
+		if target == nil {
+			created := Workspace{
+				Name:    export.Name,
+				Key:     deriveWorkspaceKey(export.Name),
+				OwnedBy: userId.(bson.ObjectID),
+				Members: []bson.ObjectID{userId.(bson.ObjectID)},
+			}
+			result, err := workspacesDb.InsertOne(context.TODO(), created)
+			if err != nil {
+				return err
+			}
+			created.Id = result.InsertedID.(bson.ObjectID)
+			runner.setWorkspace(created.Id)
+			target = &created
+		}
+		importer, err := newImportTarget(*target, userId.(bson.ObjectID))
+		if err != nil {
+			return err
+		}
+		return importTrello(export, importer, listsAsField, runner)
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to start import"})
+		return
+	}
+	c.JSON(202, job)
+}
+
+// @Summary 		Import a Trello board as a new workspace
+// @Description 	Starts a background job that creates a workspace named after the Trello board and imports it like the import into an existing workspace. Follow the job with GET /jobs/{jobId}, its workspace is set once created.
+// @Router 			/workspaces/import/trello [post]
+// @Tags 			Import and export
+// @Security 		BearerAuth
+// @Accept 			multipart/form-data
+// @Produce 		json
+// @Param 			file formData file true "Trello board JSON export"
+// @Param 			lists query string false "Map lists to boards or to the options of a List field on one board" Enums(boards, field)
+// @Success 		202 {object} Job "The started import job"
+// @Failure 		400 {object} ErrorSwagger "Bad request - missing file or not a Trello export"
+// @Failure 		413 {object} ErrorSwagger "The file is too large"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func importTrelloWorkspace(c *gin.Context) {
+	startTrelloImport(c, nil)
+}
+
+// @Summary 		Import a Trello board into a workspace
+// @Description 	Starts a background job importing a Trello board export. Lists become boards, or options of a List field. Cards become tasks with their description, due date, labels and members matched by name. Checklists and comments are added to the description. Archived cards, attachments and unmatched members are listed in the warnings of the job.
+// @Router 			/workspaces/{workspaceId}/import/trello [post]
+// @Tags 			Import and export
+// @Security 		BearerAuth
+// @Accept 			multipart/form-data
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			file formData file true "Trello board JSON export"
+// @Param 			lists query string false "Map lists to boards or to the options of a List field on one board" Enums(boards, field)
+// @Success 		202 {object} Job "The started import job"
+// @Failure 		400 {object} ErrorSwagger "Bad request - missing file or not a Trello export"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - workspace not found"
+// @Failure 		413 {object} ErrorSwagger "The file is too large"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func importTrelloBoard(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	startTrelloImport(c, &workspace)
+}
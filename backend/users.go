@@ -9,6 +9,7 @@ import (
 	"golang.org/x/crypto/argon2"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -72,6 +73,7 @@ func createUser(c *gin.Context) {
 				Name:           input.Name,
 				HashedPassword: base64.RawStdEncoding.EncodeToString(argon2.IDKey([]byte(input.Password+pepper), []byte(generatedSalt), uint32(1), uint32(32*1024), uint8(4), uint32(32))),
 				Email:          input.Email,
+				EmailDigest:    digestImmediate,
 			}
 			result, err := usersDb.InsertOne(context.TODO(), newUser)
 			if err != nil {
@@ -371,7 +373,7 @@ func getUserDetails(c *gin.Context) {
 
 func updateUserInfo(c *gin.Context) {
 	userId, _ := c.Get("id")
-	valuesToEdit := CreateUser{}
+	valuesToEdit := EditUser{}
 	user := User{}
 	if err := json.NewDecoder(c.Request.Body).Decode(&valuesToEdit); err != nil {
 		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to decode request"})
@@ -392,6 +394,20 @@ func updateUserInfo(c *gin.Context) {
 	if valuesToEdit.Email != "" {
 		user.Name = valuesToEdit.Name
 	}
+	if valuesToEdit.Timezone != "" {
+		if _, err := time.LoadLocation(valuesToEdit.Timezone); err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Unknown timezone"})
+			return
+		}
+		user.Timezone = valuesToEdit.Timezone
+	}
+	if valuesToEdit.EmailDigest != "" {
+		if !slices.Contains(digestFrequencies, valuesToEdit.EmailDigest) {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Field 'email_digest' must be immediate, hourly, daily or off"})
+			return
+		}
+		user.EmailDigest = valuesToEdit.EmailDigest
+	}
 	if valuesToEdit.Password != "" {
 		if !validatePassword(valuesToEdit.Password) {
 			c.AbortWithStatusJSON(400, gin.H{"error": "Password does not meet requirements"})
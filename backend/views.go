@@ -0,0 +1,376 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var viewGroupings = []string{"", "board", "priority", "label", "assignee", "status"}
+
+var viewTaskFields = []string{"key", "name", "description", "board", "deadline", "completed_at", "created_at", "priority", "estimate", "labels", "assignees", "links"}
+
+// validateSavedView checks the grouping, visible fields and query of a view before it is stored.
+func validateSavedView(workspaceId bson.ObjectID, userId bson.ObjectID, view SavedView) (string, error) {
+	if !slices.Contains(viewGroupings, view.GroupBy) {
+		return "Field 'group_by' must be one of board, priority, label, assignee, status", nil
+	}
+	for _, field := range view.VisibleFields {
+		if slices.Contains(viewTaskFields, field) {
+			continue
+		}
+		if _, err := bson.ObjectIDFromHex(strings.TrimPrefix(field, "cf.")); err != nil || !strings.HasPrefix(field, "cf.") {
+			return "Unknown visible field '" + field + "'", nil
+		}
+	}
+	// Building the filter once catches unknown labels, custom fields and malformed ranges
+	if _, err := taskQueryFilter(workspaceId, userId, view.Query); errors.Is(err, errInvalidTaskQuery) {
+		return err.Error(), nil
+	} else if err != nil {
+		return "", err
+	}
+	if _, _, err := taskSortExpr(view.Query.Sort); err != nil {
+		return err.Error(), nil
+	}
+	return "", nil
+}
+
+// findSavedView loads a view of the workspace from the path that the current user is allowed to see.
+// On failure the request is aborted and false is returned.
+func findSavedView(c *gin.Context, workspace Workspace) (SavedView, bool) {
+	userId, _ := c.Get("id")
+	var view SavedView
+	viewId, err := bson.ObjectIDFromHex(c.Param("viewId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid viewId"})
+		return view, false
+	}
+	filter := bson.D{
+		{"_id", viewId},
+		{"workspace", workspace.Id},
+		{"$or", bson.A{bson.D{{"owner", userId}}, bson.D{{"shared", true}}}},
+	}
+	if err := viewsDb.FindOne(context.TODO(), filter).Decode(&view); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.AbortWithStatusJSON(404, gin.H{"error": "View does not exist"})
+		} else {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		}
+		return view, false
+	}
+	return view, true
+}
+
+// groupTasks splits a page of tasks by the grouping of a view. Tasks with several labels or
+// assignees show up in every matching group, tasks without any end up in the group with an empty key.
+func groupTasks(tasks []Task, groupBy string) []TaskGroup {
+	groups := make([]TaskGroup, 0)
+	if groupBy == "" {
+		return groups
+	}
+	index := map[string]int{}
+	add := func(key string, taskId bson.ObjectID) {
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, TaskGroup{Key: key, Tasks: make([]bson.ObjectID, 0)})
+		}
+		groups[i].Tasks = append(groups[i].Tasks, taskId)
+	}
+	for _, task := range tasks {
+		var keys []string
+		switch groupBy {
+		case "board":
+			keys = []string{task.Board.Hex()}
+		case "priority":
+			keys = []string{task.Priority}
+		case "status":
+			if task.CompletedAt != 0 {
+				keys = []string{"done"}
+			} else {
+				keys = []string{"open"}
+			}
+		case "label":
+			for _, label := range task.Labels {
+				keys = append(keys, label.Hex())
+			}
+		case "assignee":
+			for _, assignee := range task.Assignees {
+				keys = append(keys, assignee.Hex())
+			}
+		}
+		if len(keys) == 0 {
+			keys = []string{""}
+		}
+		for _, key := range keys {
+			add(key, task.Id)
+		}
+	}
+	return groups
+}
+
+// @Summary 		Get all saved views
+// @Description 	Returns your own views and the views shared with the workspace. Pass boardId to only get views of one board.
+// @Router 			/workspaces/{workspaceId}/views [get]
+// @Tags 			Views
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			boardId query string false "Only return views of this board"
+// @Success 		200 {object} AllSavedViewsResponse "A list of saved views"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid board id"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		500 {object} ErrorSwagger "Internal Server Error"
+func getAllSavedViews(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	filter := bson.D{
+		{"workspace", workspace.Id},
+		{"$or", bson.A{bson.D{{"owner", userId}}, bson.D{{"shared", true}}}},
+	}
+	if bId := c.Query("boardId"); bId != "" {
+		boardId, err := bson.ObjectIDFromHex(bId)
+		if err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": "invalid boardId"})
+			return
+		}
+		filter = append(filter, bson.E{"board", boardId})
+	}
+	cursor, err := viewsDb.Find(context.TODO(), filter)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	views := make([]SavedView, 0)
+	if err := cursor.All(context.TODO(), &views); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "failed to decode views"})
+		return
+	}
+	c.JSON(200, gin.H{"views": views})
+}
+
+// @Summary 		Create a saved view
+// @Description 	Saves a named task filter with sort, grouping and visible fields. Views are private unless shared with the workspace.
+// @Router 			/workspaces/{workspaceId}/views [post]
+// @Tags 			Views
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			data body CreateSavedView true "View definition"
+// @Success 		200 {object} SavedView "The created view"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid filter, grouping or board"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func createSavedView(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	var input CreateSavedView
+	if err := json.NewDecoder(c.Request.Body).Decode(&input); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return
+	} else if input.Name == "" {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'name' is not specified"})
+		return
+	}
+	if !input.Board.IsZero() {
+		count, err := boardsDb.CountDocuments(context.TODO(), bson.D{{"_id", input.Board}, {"owned_by", workspace.Id}})
+		if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		} else if count == 0 {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Board does not exist"})
+			return
+		}
+	}
+	view := SavedView{
+		Name:          input.Name,
+		Workspace:     workspace.Id,
+		Board:         input.Board,
+		Owner:         userId.(bson.ObjectID),
+		Shared:        input.Shared,
+		Query:         input.Query,
+		GroupBy:       input.GroupBy,
+		VisibleFields: input.VisibleFields,
+		CreatedAt:     time.Now().UTC().Unix(),
+	}
+	if view.VisibleFields == nil {
+		view.VisibleFields = make([]string, 0)
+	}
+	if message, err := validateSavedView(workspace.Id, view.Owner, view); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	} else if message != "" {
+		c.AbortWithStatusJSON(400, gin.H{"error": message})
+		return
+	}
+	result, err := viewsDb.InsertOne(context.TODO(), view)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to create view"})
+		return
+	}
+	view.Id = result.InsertedID.(bson.ObjectID)
+	c.JSON(200, view)
+}
+
+// @Summary 		Edit a saved view
+// @Description 	Changes a view. Only the user who created the view can edit it.
+// @Router 			/workspaces/{workspaceId}/views/{viewId} [patch]
+// @Tags 			Views
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			viewId path string true "View ID"
+// @Param 			data body EditSavedView true "Fields to edit in the view"
+// @Success 		200 {object} SavedView "The updated view"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid input"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you did not create this view"
+// @Failure 		404 {object} ErrorSwagger "Not Found - view not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func editSavedView(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	view, ok := findSavedView(c, workspace)
+	if !ok {
+		return
+	} else if view.Owner != userId.(bson.ObjectID) {
+		c.AbortWithStatusJSON(403, gin.H{"error": "Only the creator of a view can edit it"})
+		return
+	}
+	var valuesToEdit EditSavedView
+	if err := json.NewDecoder(c.Request.Body).Decode(&valuesToEdit); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return
+	}
+	if valuesToEdit.Name != "" {
+		view.Name = valuesToEdit.Name
+	}
+	if valuesToEdit.Shared != nil {
+		view.Shared = *valuesToEdit.Shared
+	}
+	if valuesToEdit.Query != nil {
+		view.Query = *valuesToEdit.Query
+	}
+	if valuesToEdit.GroupBy != nil {
+		view.GroupBy = *valuesToEdit.GroupBy
+	}
+	if valuesToEdit.VisibleFields != nil {
+		view.VisibleFields = valuesToEdit.VisibleFields
+	}
+	if message, err := validateSavedView(workspace.Id, view.Owner, view); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	} else if message != "" {
+		c.AbortWithStatusJSON(400, gin.H{"error": message})
+		return
+	}
+	if _, err := viewsDb.ReplaceOne(context.TODO(), bson.D{{"_id", view.Id}}, view); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to update view"})
+		return
+	}
+	c.JSON(200, view)
+}
+
+// @Summary 		Delete a saved view
+// @Description 	Deletes a view. The creator of a view and the workspace owner can delete it.
+// @Router 			/workspaces/{workspaceId}/views/{viewId} [delete]
+// @Tags 			Views
+// @Security 		BearerAuth
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			viewId path string true "View ID"
+// @Success 		200 "View deleted successfully"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid view id"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you can not delete this view"
+// @Failure 		404 {object} ErrorSwagger "Not Found - view not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func deleteSavedView(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	view, ok := findSavedView(c, workspace)
+	if !ok {
+		return
+	} else if view.Owner != userId.(bson.ObjectID) && workspace.OwnedBy != userId.(bson.ObjectID) {
+		c.AbortWithStatusJSON(403, gin.H{"error": "Only the creator of a view can delete it"})
+		return
+	}
+	if _, err := viewsDb.DeleteOne(context.TODO(), bson.D{{"_id", view.Id}}); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to delete view"})
+		return
+	}
+	c.AbortWithStatus(200)
+}
+
+// @Summary 		Get tasks of a saved view
+// @Description 	Runs the filter and sort of a view with the same pagination as task listings. "me" in a view always means the current user. Groups describe how the tasks of the returned page are split by the view grouping.
+// @Router 			/workspaces/{workspaceId}/views/{viewId}/tasks [get]
+// @Tags 			Views
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			viewId path string true "View ID"
+// @Param 			limit query int false "Page size, 1 to 500, default 100"
+// @Param 			cursor query string false "Cursor of the next page from a previous response"
+// @Success 		200 {object} SavedViewTasksResponse "The view and a page of its tasks"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid view id, cursor or outdated view filter"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - view not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func getSavedViewTasks(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	view, ok := findSavedView(c, workspace)
+	if !ok {
+		return
+	}
+	filter, err := taskQueryFilter(workspace.Id, userId.(bson.ObjectID), view.Query)
+	if errors.Is(err, errInvalidTaskQuery) {
+		// Labels or custom fields used by the view may have been deleted since it was saved
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	if !view.Board.IsZero() {
+		filter = append(filter, bson.E{"board", view.Board})
+	}
+	sortExpr, descending, err := taskSortExpr(view.Query.Sort)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	page, ok := parsePageRequest(c, view.Query.Sort, sortExpr, descending)
+	if !ok {
+		return
+	}
+	tasks, nextCursor, err := paginate[Task](tasksDb, filter, page)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(200, gin.H{"view": view, "tasks": tasks, "groups": groupTasks(tasks, view.GroupBy), "next_cursor": nextCursor})
+}
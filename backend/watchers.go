@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// taskWatchers returns the users following a task, directly or through its board.
+func taskWatchers(task Task) ([]bson.ObjectID, error) {
+	watchers := slices.Clone(task.Watchers)
+	var board Board
+	err := boardsDb.FindOne(context.TODO(), bson.D{{"_id", task.Board}}, options.FindOne().SetProjection(bson.D{{"watchers", 1}})).Decode(&board)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+	for _, userId := range board.Watchers {
+		if !slices.Contains(watchers, userId) {
+			watchers = append(watchers, userId)
+		}
+	}
+	return watchers, nil
+}
+
+// importedTaskWatchers returns the watchers of a task an import creates. The importer follows it like
+// the creator of a task does, and its assignees like members who are assigned to a task.
+func importedTaskWatchers(importer bson.ObjectID, assignees []bson.ObjectID) []bson.ObjectID {
+	watchers := []bson.ObjectID{importer}
+	for _, userId := range assignees {
+		if !slices.Contains(watchers, userId) {
+			watchers = append(watchers, userId)
+		}
+	}
+	return watchers
+}
+
+// notifyWatchers notifies everyone following a task except the user who made the change. Watchers
+// that left the workspace keep their watch but are not notified.
+func notifyWatchers(task Task, actor bson.ObjectID, kind string, message string) {
+	go func() {
+		watchers, err := taskWatchers(task)
+		if err != nil {
+			println("WARNING Failed to load watchers of task ", task.Id.Hex(), ": ", err.Error())
+			return
+		}
+		var workspace Workspace
+		if err := workspacesDb.FindOne(context.TODO(), bson.D{{"_id", task.CreatedBy}}, options.FindOne().SetProjection(bson.D{{"owned_by", 1}, {"members", 1}})).Decode(&workspace); err != nil {
+			println("WARNING Failed to load workspace of task ", task.Id.Hex(), ": ", err.Error())
+			return
+		}
+		for _, userId := range watchers {
+			if userId == actor || !(slices.Contains(workspace.Members, userId) || workspace.OwnedBy == userId) {
+				continue
+			}
+			if err := notify(userId, task.CreatedBy, task.Id, actor, kind, message); err != nil {
+				println("WARNING Failed to store notification: ", err.Error())
+			}
+		}
+	}()
+}
+
+func changeTaskWatch(c *gin.Context, operator string) {
+	taskInput, exists := c.Get("taskObj")
+	if !exists {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Task object not found in context"})
+		return
+	}
+	task := taskInput.(Task)
+	if !authorizeTaskAccess(c, task) {
+		return
+	}
+	userId, _ := c.Get("id")
+	err := tasksDb.FindOneAndUpdate(context.TODO(),
+		bson.D{{"_id", task.Id}},
+		bson.D{{operator, bson.D{{"watchers", userId}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&task)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(200, task)
+}
+
+// @Summary 		Watch a task
+// @Description 	Subscribes you to notifications about changes of a task.
+// @Router 			/workspaces/{workspaceId}/tasks/{taskId}/watch [post]
+// @Tags 			Watchers
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			taskId path string true "Task ID or key like WEB-42"
+// @Success 		200 {object} Task "The watched task"
+// @Failure 		403 {object} ErrorSwagger "Forbidden - you do not have access to this task"
+// @Failure 		404 {object} ErrorSwagger "Not Found - task not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func watchTask(c *gin.Context) {
+	changeTaskWatch(c, "$addToSet")
+}
+
+// @Summary 		Stop watching a task
+// @Router 			/workspaces/{workspaceId}/tasks/{taskId}/watch [delete]
+// @Tags 			Watchers
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			taskId path string true "Task ID or key like WEB-42"
+// @Success 		200 {object} Task "The task"
+// @Failure 		403 {object} ErrorSwagger "Forbidden - you do not have access to this task"
+// @Failure 		404 {object} ErrorSwagger "Not Found - task not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func unwatchTask(c *gin.Context) {
+	changeTaskWatch(c, "$pull")
+}
+
+func changeBoardWatch(c *gin.Context, operator string) {
+	workspace, ok := authorizeWorkspaceAccess(c)
+	if !ok {
+		return
+	}
+	userId, _ := c.Get("id")
+	boardId, err := bson.ObjectIDFromHex(c.Param("boardId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid boardId"})
+		return
+	}
+	var board Board
+	err = boardsDb.FindOneAndUpdate(context.TODO(),
+		bson.D{{"_id", boardId}, {"owned_by", workspace.Id}},
+		bson.D{{operator, bson.D{{"watchers", userId}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&board)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Board does not exist"})
+		return
+	} else if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(200, board)
+}
+
+// @Summary 		Watch a board
+// @Description 	Subscribes you to notifications about every task on a board.
+// @Router 			/workspaces/{workspaceId}/boards/{boardId}/watch [post]
+// @Tags 			Watchers
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			boardId path string true "Board ID"
+// @Success 		200 {object} Board "The watched board"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid board id"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - board not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func watchBoard(c *gin.Context) {
+	changeBoardWatch(c, "$addToSet")
+}
+
+// @Summary 		Stop watching a board
+// @Router 			/workspaces/{workspaceId}/boards/{boardId}/watch [delete]
+// @Tags 			Watchers
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			boardId path string true "Board ID"
+// @Success 		200 {object} Board "The board"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid board id"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not a member of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - board not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func unwatchBoard(c *gin.Context) {
+	changeBoardWatch(c, "$pull")
+}
+
+// @Summary 		Get watched tasks
+// @Description 	Returns tasks you watch directly or through a watched board, across all workspaces you are part of.
+// @Router 			/users/watched_tasks [get]
+// @Tags 			Watchers
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			status query string false "Only return open or done tasks" Enums(open, done)
+// @Param 			sort query string false "Sort key: created, name, priority, deadline, prefix with - to reverse"
+// @Param 			limit query int false "Page size, 1 to 500, default 100"
+// @Param 			cursor query string false "Cursor of the next page from a previous response"
+// @Success 		200 {object} WatchedTasksResponse "A page of watched tasks"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid filter, sort or cursor"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func getWatchedTasks(c *gin.Context) {
+	userId, _ := c.Get("id")
+	workspaceIds, err := distinctIds(workspacesDb, bson.D{{"$or", bson.A{bson.D{{"members", userId}}, bson.D{{"owned_by", userId}}}}})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	boardIds, err := distinctIds(boardsDb, bson.D{{"watchers", userId}, {"owned_by", bson.D{{"$in", workspaceIds}}}})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	filter := bson.D{
+		{"created_by", bson.D{{"$in", workspaceIds}}},
+		{"$or", bson.A{bson.D{{"watchers", userId}}, bson.D{{"board", bson.D{{"$in", boardIds}}}}}},
+	}
+	if status := c.Query("status"); status != "" {
+		statusMatch, ok := statusFilter(status)
+		if !ok {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Field 'status' must be open or done"})
+			return
+		}
+		filter = append(filter, statusMatch)
+	}
+	sort := c.Query("sort")
+	sortExpr, descending, err := taskSortExpr(sort)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	page, ok := parsePageRequest(c, sort, sortExpr, descending)
+	if !ok {
+		return
+	}
+	tasks, nextCursor, err := paginate[Task](tasksDb, filter, page)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(200, gin.H{"tasks": tasks, "next_cursor": nextCursor})
+}
@@ -0,0 +1,574 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	deliveryPending   = "pending"
+	deliveryDelivered = "delivered"
+	deliveryFailed    = "failed"
+)
+
+const (
+	webhookTick        = 5 * time.Second
+	webhookTimeout     = 10 * time.Second
+	webhookLease       = time.Minute
+	webhookMaxAttempts = 8
+	// Retries wait 30s, 1m, 2m, 4m and so on, at most six hours
+	webhookBaseDelay = 30 * time.Second
+	webhookMaxDelay  = 6 * time.Hour
+	eventPing        = "ping"
+)
+
+var webhookEvents = []string{
+	eventTaskCreated, eventTaskUpdated, eventTaskDeleted,
+	eventBoardCreated, eventBoardUpdated, eventBoardDeleted,
+	eventMemberJoined, eventMemberKicked, eventMemberPromoted,
+	"task.*", "board.*", "member.*", "*",
+}
+
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+// webhookWakeup lets handlers trigger an immediate delivery run instead of waiting for the next tick.
+var webhookWakeup = make(chan struct{}, 1)
+
+func wakeWebhookWorker() {
+	select {
+	case webhookWakeup <- struct{}{}:
+	default:
+	}
+}
+
+// subscribesTo reports whether a webhook wants an event. Patterns are exact event types,
+// a group like board.* or * for everything.
+func (webhook Webhook) subscribesTo(eventType string) bool {
+	for _, pattern := range webhook.Events {
+		if pattern == "*" || pattern == eventType {
+			return true
+		}
+		if group, ok := strings.CutSuffix(pattern, ".*"); ok && strings.HasPrefix(eventType, group+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// signPayload returns the value of the X-Rela-Signature header, an HMAC-SHA256 of the body.
+func signPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func validateWebhookURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+func validateWebhookEvents(events []string) bool {
+	if len(events) == 0 {
+		return false
+	}
+	for _, event := range events {
+		if !slices.Contains(webhookEvents, event) {
+			return false
+		}
+	}
+	return true
+}
+
+func newDelivery(webhook Webhook, eventType string, payload []byte) WebhookDelivery {
+	now := time.Now().UTC().Unix()
+	return WebhookDelivery{
+		Webhook:       webhook.Id,
+		Workspace:     webhook.Workspace,
+		Event:         eventType,
+		Payload:       string(payload),
+		Status:        deliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+// enqueueWebhookDeliveries queues a delivery of the event for every active webhook of its workspace that subscribes to it.
+func enqueueWebhookDeliveries(event Event) error {
+	cursor, err := webhooksDb.Find(context.TODO(), bson.D{{"workspace", event.Workspace}, {"active", true}})
+	if err != nil {
+		return err
+	}
+	webhooks := make([]Webhook, 0)
+	if err := cursor.All(context.TODO(), &webhooks); err != nil {
+		return err
+	}
+	deliveries := make([]WebhookDelivery, 0)
+	var payload []byte
+	for _, webhook := range webhooks {
+		if !webhook.subscribesTo(event.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(WebhookPayload{Event: event.Type, Workspace: event.Workspace, CreatedAt: event.CreatedAt, Data: event}); err != nil {
+				return err
+			}
+		}
+		deliveries = append(deliveries, newDelivery(webhook, event.Type, payload))
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if _, err := webhookDeliveriesDb.InsertMany(context.TODO(), deliveries); err != nil {
+		return err
+	}
+	wakeWebhookWorker()
+	return nil
+}
+
+// attemptDelivery posts a delivery once and records the outcome, scheduling a retry with
+// exponential backoff on failure.
+func attemptDelivery(delivery WebhookDelivery) (WebhookDelivery, error) {
+	var webhook Webhook
+	if err := webhooksDb.FindOne(context.TODO(), bson.D{{"_id", delivery.Webhook}}).Decode(&webhook); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// The webhook was deleted while the delivery was pending
+			_, err = webhookDeliveriesDb.DeleteOne(context.TODO(), bson.D{{"_id", delivery.Id}})
+		}
+		return delivery, err
+	}
+	delivery = sendDelivery(webhook, delivery)
+	_, err := webhookDeliveriesDb.ReplaceOne(context.TODO(), bson.D{{"_id", delivery.Id}}, delivery)
+	return delivery, err
+}
+
+// sendDelivery posts a delivery to its webhook and returns it with the outcome of the attempt.
+func sendDelivery(webhook Webhook, delivery WebhookDelivery) WebhookDelivery {
+	delivery.Attempts++
+	delivery.ResponseCode, delivery.Error = 0, ""
+	request, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader([]byte(delivery.Payload)))
+	if err == nil {
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("User-Agent", "Rela-Webhook")
+		request.Header.Set("X-Rela-Event", delivery.Event)
+		request.Header.Set("X-Rela-Delivery", delivery.Id.Hex())
+		request.Header.Set("X-Rela-Signature", signPayload(webhook.Secret, []byte(delivery.Payload)))
+		var response *http.Response
+		if response, err = webhookClient.Do(request); err == nil {
+			// Drain a bit of the body so the connection can be reused
+			io.CopyN(io.Discard, response.Body, 4096)
+			response.Body.Close()
+			delivery.ResponseCode = response.StatusCode
+			if response.StatusCode < 200 || response.StatusCode > 299 {
+				err = fmt.Errorf("receiver responded with %s", response.Status)
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	if err == nil {
+		delivery.Status = deliveryDelivered
+		delivery.DeliveredAt = now.Unix()
+	} else {
+		delivery.Error = err.Error()
+		if delivery.Attempts >= webhookMaxAttempts {
+			delivery.Status = deliveryFailed
+		} else {
+			delay := min(webhookBaseDelay<<(delivery.Attempts-1), webhookMaxDelay)
+			delivery.NextAttemptAt = now.Add(delay).Unix()
+		}
+	}
+	delivery.ClaimedUntil = 0
+	return delivery
+}
+
+// claimDelivery leases the next due delivery to this instance, so replicas never post the same delivery at once.
+func claimDelivery(now time.Time) (WebhookDelivery, bool, error) {
+	var delivery WebhookDelivery
+	err := webhookDeliveriesDb.FindOneAndUpdate(context.TODO(),
+		bson.D{
+			{"status", deliveryPending},
+			{"next_attempt_at", bson.D{{"$lte", now.Unix()}}},
+			{"claimed_until", bson.D{{"$lte", now.Unix()}}},
+		},
+		bson.D{{"$set", bson.D{{"claimed_until", now.Add(webhookLease).Unix()}}}},
+		options.FindOneAndUpdate().SetSort(bson.D{{"next_attempt_at", 1}}).SetReturnDocument(options.After),
+	).Decode(&delivery)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return delivery, false, nil
+	}
+	return delivery, err == nil, err
+}
+
+// runWebhookWorker delivers due webhook deliveries until none are left, then waits for the next tick or wakeup.
+func runWebhookWorker() {
+	ticker := time.NewTicker(webhookTick)
+	defer ticker.Stop()
+	for {
+		for {
+			delivery, ok, err := claimDelivery(time.Now().UTC())
+			if err != nil {
+				println("WARNING Failed to claim webhook delivery: ", err.Error())
+				break
+			} else if !ok {
+				break
+			}
+			if _, err := attemptDelivery(delivery); err != nil {
+				println("WARNING Failed to deliver webhook ", delivery.Id.Hex(), ": ", err.Error())
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-webhookWakeup:
+		}
+	}
+}
+
+// findWebhook loads the webhook from the path. On failure the request is aborted.
+func findWebhook(c *gin.Context, workspace Workspace) (Webhook, bool) {
+	var webhook Webhook
+	webhookId, err := bson.ObjectIDFromHex(c.Param("webhookId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid webhookId"})
+		return webhook, false
+	}
+	if err := webhooksDb.FindOne(context.TODO(), bson.D{{"_id", webhookId}, {"workspace", workspace.Id}}).Decode(&webhook); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.AbortWithStatusJSON(404, gin.H{"error": "Webhook does not exist"})
+		} else {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		}
+		return webhook, false
+	}
+	return webhook, true
+}
+
+// @Summary 		Get all webhooks
+// @Description 	Returns the webhooks of a workspace. Secrets are not included. Only the workspace owner can do this.
+// @Router 			/workspaces/{workspaceId}/webhooks [get]
+// @Tags 			Webhooks
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Success 		200 {object} AllWebhooksResponse "A list of webhooks"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not the owner of this workspace"
+// @Failure 		500 {object} ErrorSwagger "Internal Server Error"
+func getAllWebhooks(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceOwner(c)
+	if !ok {
+		return
+	}
+	cursor, err := webhooksDb.Find(context.TODO(), bson.D{{"workspace", workspace.Id}}, options.Find().SetProjection(bson.D{{"secret", 0}}))
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	webhooks := make([]Webhook, 0)
+	if err := cursor.All(context.TODO(), &webhooks); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "failed to decode webhooks"})
+		return
+	}
+	c.JSON(200, gin.H{"webhooks": webhooks})
+}
+
+// @Summary 		Create a webhook
+// @Description 	Subscribes a URL to workspace events. Every request carries an X-Rela-Signature header with the HMAC-SHA256 of the body keyed with the secret. A secret is generated when none is given, it is only returned here.
+// @Router 			/workspaces/{workspaceId}/webhooks [post]
+// @Tags 			Webhooks
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			data body CreateWebhook true "URL, secret and events like task.created, board.* or *"
+// @Success 		200 {object} Webhook "The created webhook including its secret"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid url or events"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not the owner of this workspace"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func createWebhook(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceOwner(c)
+	if !ok {
+		return
+	}
+	var input CreateWebhook
+	if err := json.NewDecoder(c.Request.Body).Decode(&input); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return
+	} else if !validateWebhookURL(input.URL) {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'url' must be an http or https URL"})
+		return
+	} else if !validateWebhookEvents(input.Events) {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'events' must list events like task.created, task.*, board.*, member.* or *"})
+		return
+	}
+	if input.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		input.Secret = secret
+	}
+	webhook := Webhook{
+		Workspace: workspace.Id,
+		URL:       input.URL,
+		Secret:    input.Secret,
+		Events:    input.Events,
+		Active:    true,
+		CreatedAt: time.Now().UTC().Unix(),
+	}
+	result, err := webhooksDb.InsertOne(context.TODO(), webhook)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to create webhook"})
+		return
+	}
+	webhook.Id = result.InsertedID.(bson.ObjectID)
+	c.JSON(200, webhook)
+}
+
+// @Summary 		Edit a webhook
+// @Description 	Changes the URL, secret or events of a webhook, or pauses it with active set to false.
+// @Router 			/workspaces/{workspaceId}/webhooks/{webhookId} [patch]
+// @Tags 			Webhooks
+// @Security 		BearerAuth
+// @Accept 			json
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			webhookId path string true "Webhook ID"
+// @Param 			data body EditWebhook true "Fields to edit in the webhook"
+// @Success 		200 {object} Webhook "The updated webhook"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid input"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not the owner of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - webhook not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func editWebhook(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceOwner(c)
+	if !ok {
+		return
+	}
+	webhook, ok := findWebhook(c, workspace)
+	if !ok {
+		return
+	}
+	var valuesToEdit EditWebhook
+	if err := json.NewDecoder(c.Request.Body).Decode(&valuesToEdit); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Something went wrong when parsing request"})
+		return
+	}
+	if valuesToEdit.URL != "" {
+		if !validateWebhookURL(valuesToEdit.URL) {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Field 'url' must be an http or https URL"})
+			return
+		}
+		webhook.URL = valuesToEdit.URL
+	}
+	if valuesToEdit.Events != nil {
+		if !validateWebhookEvents(valuesToEdit.Events) {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Field 'events' must list events like task.created, task.*, board.*, member.* or *"})
+			return
+		}
+		webhook.Events = valuesToEdit.Events
+	}
+	if valuesToEdit.Secret != "" {
+		webhook.Secret = valuesToEdit.Secret
+	}
+	if valuesToEdit.Active != nil {
+		webhook.Active = *valuesToEdit.Active
+	}
+	if _, err := webhooksDb.ReplaceOne(context.TODO(), bson.D{{"_id", webhook.Id}}, webhook); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to update webhook"})
+		return
+	}
+	webhook.Secret = ""
+	c.JSON(200, webhook)
+}
+
+// @Summary 		Delete a webhook
+// @Description 	Deletes a webhook together with its delivery log.
+// @Router 			/workspaces/{workspaceId}/webhooks/{webhookId} [delete]
+// @Tags 			Webhooks
+// @Security 		BearerAuth
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			webhookId path string true "Webhook ID"
+// @Success 		200 "Webhook deleted successfully"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid webhook id"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not the owner of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - webhook not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func deleteWebhook(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceOwner(c)
+	if !ok {
+		return
+	}
+	webhook, ok := findWebhook(c, workspace)
+	if !ok {
+		return
+	}
+	if _, err := webhooksDb.DeleteOne(context.TODO(), bson.D{{"_id", webhook.Id}}); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to delete webhook"})
+		return
+	}
+	if _, err := webhookDeliveriesDb.DeleteMany(context.TODO(), bson.D{{"webhook", webhook.Id}}); err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Failed to delete webhook deliveries"})
+		return
+	}
+	c.AbortWithStatus(200)
+}
+
+// @Summary 		Ping a webhook
+// @Description 	Sends a ping event to the webhook right away and returns the logged delivery. Failed pings are retried like other deliveries.
+// @Router 			/workspaces/{workspaceId}/webhooks/{webhookId}/ping [post]
+// @Tags 			Webhooks
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			webhookId path string true "Webhook ID"
+// @Success 		200 {object} WebhookDelivery "The ping delivery"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid webhook id"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not the owner of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - webhook not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func pingWebhook(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceOwner(c)
+	if !ok {
+		return
+	}
+	webhook, ok := findWebhook(c, workspace)
+	if !ok {
+		return
+	}
+	now := time.Now().UTC().Unix()
+	payload, err := json.Marshal(WebhookPayload{Event: eventPing, Workspace: workspace.Id, CreatedAt: now, Data: gin.H{"webhook": webhook.Id}})
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	delivery := newDelivery(webhook, eventPing, payload)
+	// Claimed right away so the background worker leaves the first attempt to this request
+	delivery.ClaimedUntil = time.Now().UTC().Add(webhookLease).Unix()
+	result, err := webhookDeliveriesDb.InsertOne(context.TODO(), delivery)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	delivery.Id = result.InsertedID.(bson.ObjectID)
+	delivery, err = attemptDelivery(delivery)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(200, delivery)
+}
+
+// @Summary 		Get webhook deliveries
+// @Description 	Returns the delivery log of a webhook, newest first.
+// @Router 			/workspaces/{workspaceId}/webhooks/{webhookId}/deliveries [get]
+// @Tags 			Webhooks
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			webhookId path string true "Webhook ID"
+// @Param 			status query string false "Only return deliveries with this status" Enums(pending, delivered, failed)
+// @Param 			limit query int false "Page size, 1 to 500, default 100"
+// @Param 			cursor query string false "Cursor of the next page from a previous response"
+// @Success 		200 {object} AllWebhookDeliveriesResponse "A page of deliveries"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid webhook id or cursor"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not the owner of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - webhook not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func getWebhookDeliveries(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceOwner(c)
+	if !ok {
+		return
+	}
+	webhook, ok := findWebhook(c, workspace)
+	if !ok {
+		return
+	}
+	filter := bson.D{{"webhook", webhook.Id}}
+	if status := c.Query("status"); status != "" {
+		filter = append(filter, bson.E{"status", status})
+	}
+	page, ok := parsePageRequest(c, "", "$created_at", true)
+	if !ok {
+		return
+	}
+	deliveries, nextCursor, err := paginate[WebhookDelivery](webhookDeliveriesDb, filter, page)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(200, gin.H{"deliveries": deliveries, "next_cursor": nextCursor})
+}
+
+// @Summary 		Redeliver a webhook delivery
+// @Description 	Queues a new delivery with the same payload as an earlier one.
+// @Router 			/workspaces/{workspaceId}/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver [post]
+// @Tags 			Webhooks
+// @Security 		BearerAuth
+// @Produce 		json
+// @Param 			workspaceId path string true "Workspace ID"
+// @Param 			webhookId path string true "Webhook ID"
+// @Param 			deliveryId path string true "Delivery ID"
+// @Success 		200 {object} WebhookDelivery "The queued delivery"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid id"
+// @Failure			403 {object} ErrorSwagger "Forbidden - you are not the owner of this workspace"
+// @Failure 		404 {object} ErrorSwagger "Not Found - webhook or delivery not found"
+// @Failure 		500 {object} ErrorSwagger "Internal server error"
+func redeliverWebhook(c *gin.Context) {
+	workspace, ok := authorizeWorkspaceOwner(c)
+	if !ok {
+		return
+	}
+	webhook, ok := findWebhook(c, workspace)
+	if !ok {
+		return
+	}
+	deliveryId, err := bson.ObjectIDFromHex(c.Param("deliveryId"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid deliveryId"})
+		return
+	}
+	var original WebhookDelivery
+	if err := webhookDeliveriesDb.FindOne(context.TODO(), bson.D{{"_id", deliveryId}, {"webhook", webhook.Id}}).Decode(&original); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.AbortWithStatusJSON(404, gin.H{"error": "Delivery does not exist"})
+		} else {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		}
+		return
+	}
+	delivery := newDelivery(webhook, original.Event, []byte(original.Payload))
+	result, err := webhookDeliveriesDb.InsertOne(context.TODO(), delivery)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	delivery.Id = result.InsertedID.(bson.ObjectID)
+	wakeWebhookWorker()
+	c.JSON(200, delivery)
+}
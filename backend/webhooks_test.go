@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestSignPayload(t *testing.T) {
+	// HMAC-SHA256 test case 2 of RFC 4231
+	if got, want := signPayload("Jefe", []byte("what do ya want for nothing?")), "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"; got != want {
+		t.Errorf("signPayload = %s, want %s", got, want)
+	}
+	if signPayload("one", []byte("{}")) == signPayload("two", []byte("{}")) {
+		t.Error("different secrets give the same signature")
+	}
+}
+
+func TestWebhookSubscribesTo(t *testing.T) {
+	tests := []struct {
+		events    []string
+		eventType string
+		want      bool
+	}{
+		{[]string{"*"}, eventMemberKicked, true},
+		{[]string{eventTaskCreated}, eventTaskCreated, true},
+		{[]string{eventTaskCreated}, eventTaskUpdated, false},
+		{[]string{"task.*"}, eventTaskDeleted, true},
+		{[]string{"task.*"}, eventBoardCreated, false},
+		{[]string{"board.*", eventMemberJoined}, eventMemberJoined, true},
+		{[]string{}, eventTaskCreated, false},
+	}
+	for _, test := range tests {
+		if got := (Webhook{Events: test.events}).subscribesTo(test.eventType); got != test.want {
+			t.Errorf("%v subscribesTo(%s) = %v, want %v", test.events, test.eventType, got, test.want)
+		}
+	}
+}
+
+// receivedRequest is what the test receiver saw of a delivery.
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newWebhookReceiver(t *testing.T, status int) (*httptest.Server, chan receivedRequest) {
+	t.Helper()
+	received := make(chan receivedRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- receivedRequest{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func TestSendDelivery(t *testing.T) {
+	webhook := Webhook{Id: bson.NewObjectID(), Workspace: bson.NewObjectID(), Secret: "s3cret", Events: []string{"*"}, Active: true}
+	pending := newDelivery(webhook, eventTaskCreated, []byte(`{"event":"task.created"}`))
+	pending.Id = bson.NewObjectID()
+	pending.ClaimedUntil = time.Now().Add(webhookLease).Unix()
+
+	t.Run("delivers a signed payload", func(t *testing.T) {
+		server, received := newWebhookReceiver(t, 204)
+		webhook.URL = server.URL
+		delivery := sendDelivery(webhook, pending)
+		request := <-received
+		if string(request.body) != pending.Payload {
+			t.Errorf("body = %s, want %s", request.body, pending.Payload)
+		}
+		mac := hmac.New(sha256.New, []byte(webhook.Secret))
+		mac.Write(request.body)
+		if got, want := request.header.Get("X-Rela-Signature"), "sha256="+hex.EncodeToString(mac.Sum(nil)); got != want {
+			t.Errorf("X-Rela-Signature = %s, want %s", got, want)
+		}
+		if request.header.Get("X-Rela-Event") != eventTaskCreated || request.header.Get("X-Rela-Delivery") != pending.Id.Hex() || request.header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected headers %v", request.header)
+		}
+		if delivery.Status != deliveryDelivered || delivery.Attempts != 1 || delivery.ResponseCode != 204 || delivery.DeliveredAt == 0 || delivery.Error != "" || delivery.ClaimedUntil != 0 {
+			t.Errorf("delivery = %+v", delivery)
+		}
+	})
+
+	t.Run("retries with backoff when the receiver fails", func(t *testing.T) {
+		server, received := newWebhookReceiver(t, 500)
+		webhook.URL = server.URL
+		retried := pending
+		retried.Attempts = 2
+		before := time.Now().UTC().Unix()
+		delivery := sendDelivery(webhook, retried)
+		<-received
+		// After the third attempt the retry waits four times the base delay
+		wantNext := before + int64(4*webhookBaseDelay/time.Second)
+		if delivery.Status != deliveryPending || delivery.Attempts != 3 || delivery.ResponseCode != 500 || delivery.Error == "" || delivery.NextAttemptAt < wantNext || delivery.NextAttemptAt > wantNext+2 {
+			t.Errorf("delivery = %+v, want next attempt at %d", delivery, wantNext)
+		}
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		server, received := newWebhookReceiver(t, 410)
+		webhook.URL = server.URL
+		last := pending
+		last.Attempts = webhookMaxAttempts - 1
+		delivery := sendDelivery(webhook, last)
+		<-received
+		if delivery.Status != deliveryFailed || delivery.Attempts != webhookMaxAttempts || delivery.ResponseCode != 410 {
+			t.Errorf("delivery = %+v", delivery)
+		}
+	})
+
+	t.Run("records unreachable receivers", func(t *testing.T) {
+		server, _ := newWebhookReceiver(t, 200)
+		server.Close()
+		webhook.URL = server.URL
+		delivery := sendDelivery(webhook, pending)
+		if delivery.Status != deliveryPending || delivery.ResponseCode != 0 || delivery.Error == "" {
+			t.Errorf("delivery = %+v", delivery)
+		}
+	})
+}
+
+func insertTestWebhook(t *testing.T, url string, events ...string) Webhook {
+	t.Helper()
+	webhook := Webhook{Id: bson.NewObjectID(), Workspace: bson.NewObjectID(), URL: url, Secret: "s3cret", Events: events, Active: true}
+	if _, err := webhooksDb.InsertOne(context.TODO(), webhook); err != nil {
+		t.Fatal(err)
+	}
+	return webhook
+}
+
+func insertTestDelivery(t *testing.T, webhook Webhook) WebhookDelivery {
+	t.Helper()
+	delivery := newDelivery(webhook, eventTaskCreated, []byte(`{"event":"task.created"}`))
+	delivery.Id = bson.NewObjectID()
+	if _, err := webhookDeliveriesDb.InsertOne(context.TODO(), delivery); err != nil {
+		t.Fatal(err)
+	}
+	return delivery
+}
+
+func TestAttemptDelivery(t *testing.T) {
+	useTestDatabase(t)
+
+	t.Run("stores the outcome", func(t *testing.T) {
+		server, received := newWebhookReceiver(t, 200)
+		delivery := insertTestDelivery(t, insertTestWebhook(t, server.URL, "*"))
+		if _, err := attemptDelivery(delivery); err != nil {
+			t.Fatal(err)
+		}
+		<-received
+		var stored WebhookDelivery
+		if err := webhookDeliveriesDb.FindOne(context.TODO(), bson.D{{"_id", delivery.Id}}).Decode(&stored); err != nil {
+			t.Fatal(err)
+		}
+		if stored.Status != deliveryDelivered || stored.Attempts != 1 || stored.ResponseCode != 200 {
+			t.Errorf("stored delivery = %+v", stored)
+		}
+	})
+
+	t.Run("drops deliveries of deleted webhooks", func(t *testing.T) {
+		delivery := insertTestDelivery(t, Webhook{Id: bson.NewObjectID(), Workspace: bson.NewObjectID()})
+		if _, err := attemptDelivery(delivery); err != nil {
+			t.Fatal(err)
+		}
+		if count, _ := webhookDeliveriesDb.CountDocuments(context.TODO(), bson.D{{"_id", delivery.Id}}); count != 0 {
+			t.Error("delivery of a deleted webhook was kept")
+		}
+	})
+}
+
+func TestEnqueueWebhookDeliveries(t *testing.T) {
+	useTestDatabase(t)
+	subscribed := insertTestWebhook(t, "http://example.com/a", "task.*")
+	other := insertTestWebhook(t, "http://example.com/b", eventBoardCreated)
+	other.Workspace = subscribed.Workspace
+	if _, err := webhooksDb.ReplaceOne(context.TODO(), bson.D{{"_id", other.Id}}, other); err != nil {
+		t.Fatal(err)
+	}
+	inactive := insertTestWebhook(t, "http://example.com/c", "*")
+	inactive.Workspace, inactive.Active = subscribed.Workspace, false
+	if _, err := webhooksDb.ReplaceOne(context.TODO(), bson.D{{"_id", inactive.Id}}, inactive); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := enqueueWebhookDeliveries(Event{Id: bson.NewObjectID(), Type: eventTaskCreated, Workspace: subscribed.Workspace}); err != nil {
+		t.Fatal(err)
+	}
+	var deliveries []WebhookDelivery
+	cursor, err := webhookDeliveriesDb.Find(context.TODO(), bson.D{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cursor.All(context.TODO(), &deliveries); err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Webhook != subscribed.Id || deliveries[0].Status != deliveryPending {
+		t.Fatalf("deliveries = %+v, want one pending delivery to %s", deliveries, subscribed.Id.Hex())
+	}
+
+	// Once claimed, a delivery is not handed out again until the lease ends
+	now := time.Now().UTC()
+	claimed, ok, err := claimDelivery(now)
+	if err != nil || !ok || claimed.Id != deliveries[0].Id {
+		t.Fatalf("claimDelivery = %+v %v %v", claimed, ok, err)
+	}
+	if _, ok, err := claimDelivery(now); err != nil || ok {
+		t.Errorf("claimed a leased delivery again: %v %v", ok, err)
+	}
+	if _, ok, err := claimDelivery(now.Add(webhookLease)); err != nil || !ok {
+		t.Errorf("expired lease not claimable: %v %v", ok, err)
+	}
+}
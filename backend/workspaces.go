@@ -16,7 +16,7 @@ import (
 )
 
 // @Summary 		Create a new workspace
-// @Description 	Creates a new workspace for the current user.
+// @Description 	Creates a new workspace for the current user, optionally with the boards, labels, custom fields and seed tasks of one of your templates.
 // @Router 			/workspaces/create [post]
 // @Tags 			Workspaces
 // @Security 		BearerAuth
@@ -24,7 +24,7 @@ import (
 // @Produce 		json
 // @Param 			data body CreateWorkspace true "Workspace creation data"
 // @Success 		200 {object} Workspace "The created workspace"
-// @Failure 		400 {object} ErrorSwagger "Bad request - no name specified"
+// @Failure 		400 {object} ErrorSwagger "Bad request - no name specified, invalid key or unknown template"
 // @Failure 		500 {object} ErrorSwagger "Internal server error"
 func createWorkspace(c *gin.Context) {
 	id, _ := c.Get("id")
@@ -37,8 +37,26 @@ func createWorkspace(c *gin.Context) {
 		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'name' is not specified"})
 		return
 	}
+	if input.Key == "" {
+		input.Key = deriveWorkspaceKey(input.Name)
+	} else if !workspaceKeyRegex.MatchString(input.Key) {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Field 'key' must be 2-10 uppercase letters or digits starting with a letter"})
+		return
+	}
+	var template WorkspaceTemplate
+	if !input.Template.IsZero() {
+		err := workspaceTemplatesDb.FindOne(context.TODO(), bson.D{{"_id", input.Template}, {"created_by", userId}}).Decode(&template)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Field 'template' must be one of your templates"})
+			return
+		} else if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+			return
+		}
+	}
 	output := Workspace{
 		Name:    input.Name,
+		Key:     input.Key,
 		OwnedBy: userId,
 		Members: []bson.ObjectID{userId},
 	}
@@ -48,11 +66,21 @@ func createWorkspace(c *gin.Context) {
 		return
 	}
 	output.Id = workspace.InsertedID.(bson.ObjectID)
+	if !template.Id.IsZero() {
+		if err := applyWorkspaceTemplate(template, output); err != nil {
+			// A workspace missing part of its template is removed rather than handed out
+			if discardErr := discardWorkspace(output.Id); discardErr != nil {
+				println("WARNING Failed to remove workspace ", output.Id.Hex(), ": ", discardErr.Error())
+			}
+			c.AbortWithStatusJSON(500, gin.H{"error": "Failed to apply template"})
+			return
+		}
+	}
 	c.JSON(200, output)
 }
 
 // @Summary 		Edit a workspace
-// @Description 	Edits the details of a specific workspace.
+// @Description 	Edits the details of a specific workspace. Changing the key re-keys all tasks, and keys with an earlier prefix keep resolving to the same tasks.
 // @Router 			/workspaces/{workspaceId} [patch]
 // @Tags 			Workspaces
 // @Security 		BearerAuth
@@ -101,6 +129,26 @@ func editWorkspace(c *gin.Context) {
 		workspace.Name = name
 	}
 
+	keyChanged := false
+	if key, ok := input["key"].(string); ok && key != workspace.Key {
+		if !workspaceKeyRegex.MatchString(key) {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Field 'key' must be 2-10 uppercase letters or digits starting with a letter"})
+			return
+		}
+		updateFields["key"] = key
+		// Remember the old prefix so existing references like OLD-42 keep working
+		aliases := make([]string, 0, len(workspace.KeyAliases)+1)
+		for _, alias := range append(workspace.KeyAliases, workspace.Key) {
+			if alias != "" && alias != key && !slices.Contains(aliases, alias) {
+				aliases = append(aliases, alias)
+			}
+		}
+		updateFields["key_aliases"] = aliases
+		workspace.KeyAliases = aliases
+		workspace.Key = key
+		keyChanged = true
+	}
+
 	if avatar, ok := input["avatar"]; ok {
 		updateFields["avatar"] = avatar
 		if av, ok := avatar.(string); ok {
@@ -122,6 +170,19 @@ func editWorkspace(c *gin.Context) {
 		}
 	}
 
+	if keyChanged {
+		// Re-key existing tasks so their keys follow the new prefix
+		_, err := tasksDb.UpdateMany(
+			context.TODO(),
+			bson.D{{"created_by", workspaceId}, {"number", bson.D{{"$gt", 0}}}},
+			mongo.Pipeline{{{"$set", bson.D{{"key", bson.D{{"$concat", bson.A{workspace.Key, "-", bson.D{{"$toString", "$number"}}}}}}}}}},
+		)
+		if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": "Failed to update task keys"})
+			return
+		}
+	}
+
 	c.JSON(200, workspace)
 }
 
@@ -131,22 +192,24 @@ func editWorkspace(c *gin.Context) {
 // @Tags 			Workspaces
 // @Security 		BearerAuth
 // @Produce 		json
-// @Success 		200 {object} AllWorkspacesResponse "A list of workspaces"
+// @Param 			limit query int false "Page size, max 500. Without limit and cursor all items are returned"
+// @Param 			cursor query string false "Cursor from a previous page"
+// @Success 		200 {object} AllWorkspacesResponse "A page of workspaces"
+// @Failure 		400 {object} ErrorSwagger "Bad request - invalid limit or cursor"
 // @Failure 		500 {object} ErrorSwagger "Internal Server Error"
 func getAllWorkspaces(c *gin.Context) {
 	id, _ := c.Get("id")
 	userId := id.(bson.ObjectID)
-	cursor, _ := workspacesDb.Find(context.TODO(), bson.D{{"members", userId}})
-	workspaces := make([]Workspace, 0)
-	if err := cursor.All(context.TODO(), &workspaces); err != nil {
-		c.JSON(500, gin.H{"error": "failed to decode workspaces"})
+	page, ok := parseListRequest(c, "", "$_id", false)
+	if !ok {
 		return
 	}
-	if err := cursor.Close(context.TODO()); err != nil {
-		// Log the error but don't abort, as the response has already been sent.
-		println("Failed to close cursor: ", err.Error())
+	workspaces, nextCursor, err := paginate[Workspace](workspacesDb, bson.D{{"members", userId}}, page)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "failed to decode workspaces"})
+		return
 	}
-	c.IndentedJSON(200, gin.H{"workspaces": workspaces})
+	c.JSON(200, gin.H{"workspaces": workspaces, "next_cursor": nextCursor})
 }
 
 // @Summary 		Delete a workspace
@@ -252,6 +315,8 @@ func addMember(c *gin.Context) {
 			c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
 			return
 		}
+		member := Member{Id: userId.(bson.ObjectID), Name: userName(userId.(bson.ObjectID))}
+		publishEvent(Event{Type: eventMemberJoined, Workspace: workspace.Id, Actor: member.Id, Member: &member})
 	}
 	c.AbortWithStatus(200)
 }
@@ -428,6 +493,9 @@ func kickMember(c *gin.Context) {
 		c.AbortWithStatusJSON(500, gin.H{"error": "Internal server error"})
 		return
 	}
+	notifyInBackground(input.Id, workspace.Id, bson.ObjectID{}, workspace.OwnedBy, notificationKicked, "You were removed from "+workspace.Name)
+	kicked := Member{Id: input.Id, Name: userName(input.Id)}
+	publishEvent(Event{Type: eventMemberKicked, Workspace: workspace.Id, Actor: workspace.OwnedBy, Member: &kicked})
 	c.AbortWithStatus(200)
 }
 
@@ -472,6 +540,9 @@ func promoteMember(c *gin.Context) {
 		c.AbortWithStatusJSON(500, gin.H{"error": "Internal server error"})
 		return
 	}
+	notifyInBackground(userIdToPromote, workspace.Id, bson.ObjectID{}, currentUserId.(bson.ObjectID), notificationPromoted, "You are now the owner of "+workspace.Name)
+	promoted := Member{Id: userIdToPromote, Name: userName(userIdToPromote)}
+	publishEvent(Event{Type: eventMemberPromoted, Workspace: workspace.Id, Actor: currentUserId.(bson.ObjectID), Member: &promoted})
 	c.AbortWithStatus(200)
 }
 